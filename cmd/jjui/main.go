@@ -1,12 +1,11 @@
 package main
 
 import (
-	"errors"
 	"flag"
 	"fmt"
 	"io"
-	"io/fs"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"runtime/debug"
@@ -15,10 +14,12 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/askpass"
+	"github.com/idursun/jjui/internal/clipboard"
 	"github.com/idursun/jjui/internal/ui/common"
 
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/ui"
@@ -45,6 +46,8 @@ var (
 	version    bool
 	editConfig bool
 	help       bool
+	readonly   bool
+	dryRun     bool
 )
 
 func init() {
@@ -57,9 +60,12 @@ func init() {
 	flag.BoolVar(&version, "version", false, "Show version information")
 	flag.BoolVar(&editConfig, "config", false, "Open configuration file in $EDITOR")
 	flag.BoolVar(&help, "help", false, "Show help information")
+	flag.BoolVar(&readonly, "readonly", false, "Disable the operations that change the repository")
+	flag.BoolVar(&dryRun, "dry-run", false, "Show the jj commands that change the repository instead of running them")
 
 	flag.Usage = func() {
 		fmt.Printf("Usage: jjui [flags] [location]\n")
+		fmt.Printf("       jjui %s [flags] [location]  prints the revisions and exits, see jjui %s --help\n", printCommand, printCommand)
 		fmt.Println("Flags:")
 		flag.PrintDefaults()
 	}
@@ -99,6 +105,8 @@ func run() int {
 		return 0
 	case editConfig:
 		return config.Edit()
+	case flag.Arg(0) == printCommand:
+		return runPrint(flag.Args()[1:], os.Stdout, os.Stderr)
 	}
 
 	var location string
@@ -138,62 +146,63 @@ func run() int {
 
 	appContext := context.NewAppContext(rootLocation, askpassServer)
 	defer appContext.Histories.Flush()
-	if output, err := config.LoadConfigFile(); err == nil {
-		if err := config.Current.Load(string(output)); err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
-			return 1
-		}
-		if registry, err := context.LoadCustomCommands(string(output)); err != nil {
+	defer appContext.State.Save()
+	appContext.State.AddRecentRepository(rootLocation)
+	var warnings []string
+	if output, err := loadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	} else if output != "" {
+		if registry, err := context.LoadCustomCommands(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading custom commands: %v\n", err)
 			return 1
 		} else {
 			appContext.CustomCommands = registry
 		}
-		if registry, err := context.LoadLeader(string(output)); err != nil {
+		if registry, err := context.LoadLeader(output); err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading leader keys: %v\n", err)
 			return 1
 		} else {
 			appContext.Leader = registry
 		}
-	} else if !errors.Is(err, fs.ErrNotExist) {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		return 1
-	}
-
-	var theme map[string]config.Color
-
-	var defaultThemeName string
-	if lipgloss.HasDarkBackground() {
-		defaultThemeName = "default_dark"
-	} else {
-		defaultThemeName = "default_light"
+		warnings = context.LeaderWarnings(appContext.Leader, appContext.CustomCommands)
 	}
 
-	theme, err = config.LoadEmbeddedTheme(defaultThemeName)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading default theme '%s': %v\n", defaultThemeName, err)
+	appContext.DarkBackground = lipgloss.HasDarkBackground()
+	if err := common.ApplyTheme(appContext.DarkBackground, appContext.JJConfig.GetApplicableColors()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
 		return 1
 	}
 
-	var userThemeName string
-	if lipgloss.HasDarkBackground() {
-		userThemeName = config.Current.UI.Theme.Dark
-	} else {
-		userThemeName = config.Current.UI.Theme.Light
+	appContext.IdType, _ = config.GetIdType(config.Current)
+	if readonly {
+		config.Current.UI.ReadOnly = true
 	}
+	appContext.ReadOnly = config.Current.UI.ReadOnly
+	appContext.DryRun = dryRun || config.Current.UI.DryRun
+	appContext.SetSignCommits(config.Current.Git.SignCommits)
 
-	if userThemeName != "" {
-		theme, err = config.LoadTheme(userThemeName, theme)
+	if file := config.Current.UI.Tracer.File; file != "" {
+		level, _ := config.GetLogLevel(config.Current)
+		f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading user theme '%s': %v\n", userThemeName, err)
+			fmt.Fprintf(os.Stderr, "Error: ui.tracer.file: %v\n", err)
 			return 1
 		}
+		defer f.Close()
+		appContext.SetLogger(slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: level})))
+		appContext.Logger.Info("jjui started", "version", getVersion(), "location", rootLocation)
 	}
 
-	common.DefaultPalette.Update(theme)
-	common.DefaultPalette.Update(appContext.JJConfig.GetApplicableColors())
-	common.DefaultPalette.Update(config.Current.UI.Colors)
-
+	if appContext.State.PreviewWidthPercentage > 0 {
+		config.Current.Preview.WidthPercentage = appContext.State.PreviewWidthPercentage
+	}
+	if appContext.State.AbsoluteTimestamps != nil {
+		config.Current.UI.AbsoluteTimestamps = *appContext.State.AbsoluteTimestamps
+	}
+	if appContext.State.GraphStyle != "" {
+		config.Current.Revisions.GraphStyle = appContext.State.GraphStyle
+	}
 	if period >= 0 {
 		config.Current.UI.AutoRefreshInterval = period
 	}
@@ -206,7 +215,11 @@ func run() int {
 	}
 	appContext.CurrentRevset = appContext.DefaultRevset
 
-	p := tea.NewProgram(ui.New(appContext), tea.WithAltScreen(), tea.WithReportFocus(), tea.WithMouseCellMotion())
+	// the clipboard writes its OSC 52 sequence to the same terminal, between the frames
+	p := tea.NewProgram(ui.New(appContext), tea.WithAltScreen(), tea.WithReportFocus(), tea.WithMouseCellMotion(), tea.WithOutput(clipboard.Terminal))
+	for _, warning := range warnings {
+		go p.Send(intents.AddMessage{Text: warning, NoTimeout: true})
+	}
 	if config.Current.Ssh.HijackAskpass {
 		if err := askpassServer.StartListening(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: ssh.hijack_askpass: %v\n", err)
@@ -219,10 +232,20 @@ func run() int {
 		// uncomment the line below to show a fake prompt upon startup
 		// go showPassword(p.Send)("test", "Enter PIN for 'ssh': ", make(<-chan struct{}))
 	}
-	if _, err := p.Run(); err != nil {
+	// terminals that don't support the reports ignore these and keep the theme picked above
+	fmt.Fprint(os.Stdout, common.EnableBackgroundReports)
+	defer fmt.Fprint(os.Stdout, common.DisableBackgroundReports)
+	stopSignals := handleShutdownSignals(p)
+	_, err = p.Run()
+	sig := stopSignals()
+	if err != nil && sig == 0 {
 		fmt.Printf("Error running program: %v\n", err)
 		return 1
 	}
+	if sig != 0 {
+		// follow the shell convention for processes terminated by a signal
+		return 128 + int(sig)
+	}
 	return 0
 }
 
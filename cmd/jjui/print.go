@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+)
+
+// printCommand is the subcommand that prints the revisions the way they are shown in the UI and exits
+const printCommand = "print"
+
+// loadConfig applies the configuration file to config.Current and returns its content so that
+// the custom commands and leader keys can be read from it. The content is empty when there is
+// no configuration file.
+func loadConfig() (string, error) {
+	output, err := config.LoadConfigFile()
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := config.Current.Load(string(output)); err != nil {
+		return "", fmt.Errorf("loading configuration: %w", err)
+	}
+	return string(output), nil
+}
+
+// runPrint runs `jj log` with the revset and template the UI would use and writes the
+// result to stdout, the flags given on the command line override the configured ones
+func runPrint(args []string, stdout, stderr io.Writer) int {
+	var (
+		printRevset   string
+		printTemplate string
+		printLimit    int
+		noGraph       bool
+	)
+	flags := flag.NewFlagSet(printCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&printRevset, "revset", revset, "Revset to print")
+	flags.StringVar(&printRevset, "r", revset, "Revset to print (same as --revset)")
+	flags.StringVar(&printTemplate, "template", "", "Template to render the revisions with (default: the template of the UI)")
+	flags.StringVar(&printTemplate, "T", "", "Template to render the revisions with (same as --template)")
+	flags.IntVar(&printLimit, "limit", limit, "Number of revisions to print")
+	flags.IntVar(&printLimit, "n", limit, "Number of revisions to print (alias for --limit)")
+	flags.BoolVar(&noGraph, "no-graph", false, "Don't print the graph")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: jjui %s [flags] [location]\n", printCommand)
+		fmt.Fprintln(stderr, "Flags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	location := flags.Arg(0)
+	if location == "" {
+		var err error
+		if location, err = os.Getwd(); err != nil {
+			fmt.Fprintf(stderr, "Error: couldn't determine the current directory: %v\n", err)
+			return 1
+		}
+	}
+	rootLocation, err := getJJRootDir(location)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s\n", err)
+		return 1
+	}
+
+	if _, err := loadConfig(); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	jjConfig := &config.JJConfig{}
+	if output, err := jjOutput(rootLocation, jj.ConfigListAll()); err == nil {
+		// jj's own revset and template are used when its config can't be parsed
+		if parsed, err := config.DefaultConfig(output); err == nil {
+			jjConfig = parsed
+		}
+	}
+	if printRevset == "" {
+		printRevset = config.Current.Revisions.Revset
+	}
+	if printRevset == "" {
+		printRevset = jjConfig.Revsets.Log
+	}
+	if printTemplate == "" {
+		printTemplate = jj.LogTemplate(jjConfig.Templates.Log)
+	}
+	if printLimit <= 0 {
+		printLimit = config.Current.Limit
+	}
+
+	// jj writes to stdout directly so that it can decide on the colours itself
+	var errOutput bytes.Buffer
+	c := exec.Command("jj", jj.WithGlobalArgs(jj.PrintLog(printRevset, printLimit, printTemplate, noGraph))...)
+	c.Dir = rootLocation
+	c.Stdout = stdout
+	c.Stderr = &errOutput
+	if err := c.Run(); err != nil {
+		if message := strings.TrimSpace(errOutput.String()); message != "" {
+			fmt.Fprintln(stderr, message)
+		} else {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+		}
+		return 1
+	}
+	return 0
+}
+
+func jjOutput(location string, args []string) ([]byte, error) {
+	c := exec.Command("jj", jj.WithGlobalArgs(args)...)
+	c.Dir = location
+	return c.Output()
+}
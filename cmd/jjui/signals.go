@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+var shutdownSignals = []os.Signal{syscall.SIGTERM, syscall.SIGHUP}
+
+// handleShutdownSignals kills the interactive processes holding the terminal
+// (editors, pagers, diff tools) and quits the program when one of the shutdown
+// signals is received, so that bubbletea can restore the terminal state.
+// The returned function stops listening and reports the received signal, if any.
+func handleShutdownSignals(p *tea.Program) func() syscall.Signal {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, shutdownSignals...)
+	done := make(chan struct{})
+	var received atomic.Int32
+	go func() {
+		select {
+		case sig := <-signals:
+			if s, ok := sig.(syscall.Signal); ok {
+				received.Store(int32(s))
+			}
+			context.TerminateInteractiveProcesses()
+			p.Quit()
+		case <-done:
+		}
+	}()
+	return func() syscall.Signal {
+		signal.Stop(signals)
+		close(done)
+		return syscall.Signal(received.Load())
+	}
+}
@@ -0,0 +1,69 @@
+// Package clipboard copies text to the clipboard of the user, either through the clipboard tool
+// of the system or by asking the terminal to do it with an OSC 52 escape sequence, and reads it
+// back through the clipboard tool.
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/idursun/jjui/internal/config"
+)
+
+// writeSystem, readSystem and output are swapped out in tests
+var (
+	writeSystem           = clipboard.WriteAll
+	readSystem            = clipboard.ReadAll
+	output      io.Writer = Terminal
+)
+
+// Copy puts the text on the clipboard in the way ui.clipboard asks for. In auto mode the system
+// clipboard is tried first, OSC 52 is used when there is no clipboard tool to run, which is usually
+// the case in ssh sessions.
+func Copy(text string) error {
+	mode, err := config.GetClipboardMode(config.Current)
+	if err != nil {
+		return err
+	}
+	switch mode {
+	case config.ClipboardSystem:
+		return writeSystem(text)
+	case config.ClipboardOSC52:
+		return writeOSC52(text)
+	default:
+		if err := writeSystem(text); err == nil {
+			return nil
+		}
+		return writeOSC52(text)
+	}
+}
+
+// Paste returns the text on the clipboard. Terminals rarely let the clipboard be read with
+// OSC 52, so it is read with the clipboard tool of the system whatever ui.clipboard is set to.
+func Paste() (string, error) {
+	text, err := readSystem()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the clipboard: %w", err)
+	}
+	return text, nil
+}
+
+// writeOSC52 asks the terminal to set the clipboard. tmux and screen only pass the sequence
+// on to the terminal when it is wrapped in their own passthrough sequences.
+func writeOSC52(text string) error {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case os.Getenv("TMUX") != "":
+		sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		sequence = "\x1bP" + sequence + "\x1b\\"
+	}
+	if _, err := io.WriteString(output, sequence); err != nil {
+		return fmt.Errorf("failed to copy to the clipboard: %w", err)
+	}
+	return nil
+}
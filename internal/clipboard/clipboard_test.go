@@ -0,0 +1,88 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setup(t *testing.T, mode string, systemErr error) (*bytes.Buffer, *string) {
+	t.Helper()
+	origConfig := *config.Current
+	origWriteSystem, origOutput := writeSystem, output
+	t.Cleanup(func() {
+		*config.Current = origConfig
+		writeSystem, output = origWriteSystem, origOutput
+	})
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	config.Current.UI.Clipboard = mode
+	var copied string
+	writeSystem = func(text string) error {
+		if systemErr != nil {
+			return systemErr
+		}
+		copied = text
+		return nil
+	}
+	var buf bytes.Buffer
+	output = &buf
+	return &buf, &copied
+}
+
+func TestCopy_AutoPrefersSystemClipboard(t *testing.T) {
+	buf, copied := setup(t, "auto", nil)
+
+	assert.NoError(t, Copy("abc"))
+	assert.Equal(t, "abc", *copied)
+	assert.Empty(t, buf.String())
+}
+
+func TestCopy_AutoFallsBackToOSC52(t *testing.T) {
+	buf, _ := setup(t, "auto", errors.New("no clipboard utilities available"))
+
+	assert.NoError(t, Copy("abc"))
+	assert.Equal(t, "\x1b]52;c;YWJj\a", buf.String())
+}
+
+func TestCopy_SystemModeReportsErrors(t *testing.T) {
+	buf, _ := setup(t, "system", errors.New("no clipboard utilities available"))
+
+	assert.EqualError(t, Copy("abc"), "no clipboard utilities available")
+	assert.Empty(t, buf.String())
+}
+
+func TestCopy_OSC52ModeWrapsForTmux(t *testing.T) {
+	buf, copied := setup(t, "osc52", nil)
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+	assert.NoError(t, Copy("abc"))
+	assert.Empty(t, *copied)
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;YWJj\a\x1b\\", buf.String())
+}
+
+func TestPaste_ReadsSystemClipboard(t *testing.T) {
+	origReadSystem := readSystem
+	t.Cleanup(func() { readSystem = origReadSystem })
+
+	readSystem = func() (string, error) { return "abc", nil }
+	text, err := Paste()
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", text)
+
+	readSystem = func() (string, error) { return "", errors.New("no clipboard utilities available") }
+	_, err = Paste()
+	assert.EqualError(t, err, "failed to read the clipboard: no clipboard utilities available")
+}
+
+func TestProcessOutput_UnwrapsTerminal(t *testing.T) {
+	assert.Equal(t, os.Stdout, ProcessOutput(Terminal))
+
+	var buf bytes.Buffer
+	assert.Equal(t, &buf, ProcessOutput(&buf))
+}
@@ -0,0 +1,45 @@
+package clipboard
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// Terminal is the output the program renders to. Its writes are serialised with the OSC 52
+// sequence so that the sequence never lands in the middle of a frame the renderer is writing.
+var Terminal = &terminal{file: os.Stdout}
+
+// terminal is a file whose writes are serialised, it keeps the Fd of the file so that the
+// program still finds out the size of the terminal
+type terminal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (t *terminal) Read(p []byte) (int, error) {
+	return t.file.Read(p)
+}
+
+func (t *terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.file.Write(p)
+}
+
+func (t *terminal) Close() error {
+	return t.file.Close()
+}
+
+func (t *terminal) Fd() uintptr {
+	return t.file.Fd()
+}
+
+// ProcessOutput returns the file behind the terminal for the processes run while the program
+// is suspended, they would be handed a pipe rather than the terminal otherwise
+func ProcessOutput(w io.Writer) io.Writer {
+	if t, ok := w.(*terminal); ok {
+		return t.file
+	}
+	return w
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// ActionKeys returns the key presses invoking the action with the given name along with its binding.
+// The name is the path of the binding under [keys] such as "abandon" or "git.push", the key of the
+// mode is pressed first for the actions within a mode.
+func ActionKeys(keyMap KeyMappings[key.Binding], name string) ([]string, key.Binding, bool) {
+	var presses []string
+	v := reflect.ValueOf(keyMap)
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		field, ok := fieldByTag(v, part)
+		if !ok {
+			return nil, key.Binding{}, false
+		}
+		if i == len(parts)-1 {
+			binding, ok := field.Interface().(key.Binding)
+			if !ok || len(binding.Keys()) == 0 {
+				return nil, key.Binding{}, false
+			}
+			return append(presses, binding.Keys()[0]), binding, true
+		}
+		if mode, ok := fieldByTag(field, "mode"); ok && parts[i+1] != "mode" {
+			binding, _ := mode.Interface().(key.Binding)
+			if len(binding.Keys()) == 0 {
+				return nil, key.Binding{}, false
+			}
+			presses = append(presses, binding.Keys()[0])
+		}
+		v = field
+	}
+	return nil, key.Binding{}, false
+}
+
+// fieldByTag returns the field of the struct with the given toml tag
+func fieldByTag(v reflect.Value, tag string) (reflect.Value, bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	for i := range v.NumField() {
+		if v.Type().Field(i).Tag.Get("toml") == tag {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionKeys(t *testing.T) {
+	keyMap := Convert(KeyMappings[keys]{
+		Abandon: keys{"a"},
+		Git:     gitModeKeys[keys]{Mode: keys{"g"}, Push: keys{"p", "P"}},
+	})
+
+	presses, binding, ok := ActionKeys(keyMap, "abandon")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a"}, presses)
+	assert.Equal(t, "abandon", binding.Help().Desc)
+
+	presses, _, ok = ActionKeys(keyMap, "git.push")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"g", "p"}, presses)
+
+	presses, _, ok = ActionKeys(keyMap, "git.mode")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"g"}, presses)
+}
+
+func TestActionKeys_Unknown(t *testing.T) {
+	keyMap := Convert(KeyMappings[keys]{Abandon: keys{"a"}})
+
+	for _, name := range []string{"nope", "git", "git.nope", "abandon.mode", "leader_timeout_ms", "new"} {
+		_, _, ok := ActionKeys(keyMap, name)
+		assert.False(t, ok, name)
+	}
+}
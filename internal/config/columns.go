@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// columnTemplates maps the names accepted in revisions.columns to jj template expressions
+var columnTemplates = map[string]string{
+	"change_id":      "format_short_change_id(change_id)",
+	"commit_id":      "format_short_commit_id(commit_id)",
+	"author":         "format_short_signature(author)",
+	"timestamp":      "format_timestamp(committer.timestamp())",
+	"description":    `if(description, description.first_line(), label("description placeholder", "(no description set)"))`,
+	"bookmarks":      "bookmarks",
+	"tags":           "tags",
+	"working_copies": "working_copies",
+	"empty":          `if(empty, label("empty", "(empty)"))`,
+	"conflict":       `if(conflict, label("conflict", "conflict"))`,
+}
+
+// ColumnsTemplate assembles a log template out of revisions.columns.
+// It returns an empty template when no columns are configured.
+func (r RevisionsConfig) ColumnsTemplate() (string, error) {
+	if len(r.Columns) == 0 {
+		return "", nil
+	}
+	var parts []string
+	for _, column := range r.Columns {
+		part, ok := columnTemplates[column]
+		if !ok {
+			available := slices.Sorted(maps.Keys(columnTemplates))
+			return "", fmt.Errorf("revisions.columns: unknown column %q, available columns are: %s", column, strings.Join(available, ", "))
+		}
+		parts = append(parts, part)
+	}
+	return fmt.Sprintf(`separate(" ", %s) ++ "\n"`, strings.Join(parts, ", ")), nil
+}
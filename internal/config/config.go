@@ -4,9 +4,11 @@ import (
 	"embed"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path"
+	"slices"
 	"strings"
 )
 
@@ -16,16 +18,83 @@ var configFS embed.FS
 var Current = loadDefaultConfig()
 
 type Config struct {
-	Keys      KeyMappings[keys] `toml:"keys"`
-	UI        UIConfig          `toml:"ui"`
-	Suggest   SuggestConfig     `toml:"suggest"`
-	Revisions RevisionsConfig   `toml:"revisions"`
-	Preview   PreviewConfig     `toml:"preview"`
-	Diff      DiffConfig        `toml:"diff"`
-	OpLog     OpLogConfig       `toml:"oplog"`
-	Limit     int               `toml:"limit"`
-	Git       GitConfig         `toml:"git"`
-	Ssh       SshConfig         `toml:"ssh"`
+	Keys        KeyMappings[keys] `toml:"keys"`
+	UI          UIConfig          `toml:"ui"`
+	Suggest     SuggestConfig     `toml:"suggest"`
+	Revisions   RevisionsConfig   `toml:"revisions"`
+	Preview     PreviewConfig     `toml:"preview"`
+	Diff        DiffConfig        `toml:"diff"`
+	Details     DetailsConfig     `toml:"details"`
+	OpLog       OpLogConfig       `toml:"oplog"`
+	Limit       int               `toml:"limit"`
+	Git         GitConfig         `toml:"git"`
+	Ssh         SshConfig         `toml:"ssh"`
+	JJ          JJCommandConfig   `toml:"jj"`
+	PostCommand PostCommandConfig `toml:"post_command"`
+}
+
+// DetailsConfig configures the details view
+type DetailsConfig struct {
+	PromptDescriptionAfterSquash bool `toml:"prompt_description_after_squash"`
+	FollowSelection              bool `toml:"follow_selection"`
+	DiffInPreview                bool `toml:"diff_in_preview"`
+}
+
+// PostCommandConfig configures what happens once a command started from jjui completes
+type PostCommandConfig struct {
+	Exec          string `toml:"exec"`
+	Shell         string `toml:"shell"`
+	CustomCommand string `toml:"custom_command"`
+}
+
+type PostCommand int
+
+const (
+	PostCommandRefresh PostCommand = iota
+	PostCommandKeepSelections
+	PostCommandNone
+)
+
+func getPostCommand(name string, value string) (PostCommand, error) {
+	switch value {
+	case "", "refresh":
+		return PostCommandRefresh, nil
+	case "keep_selections":
+		return PostCommandKeepSelections, nil
+	case "none":
+		return PostCommandNone, nil
+	default:
+		return PostCommandRefresh, fmt.Errorf("invalid value for 'post_command.%s': %q (expected one of: refresh, keep_selections, none)", name, value)
+	}
+}
+
+// GetExec returns what happens after a command run from the jj exec prompt completes
+func (p PostCommandConfig) GetExec() (PostCommand, error) {
+	return getPostCommand("exec", p.Exec)
+}
+
+// GetShell returns what happens after a command run from the shell exec prompt completes
+func (p PostCommandConfig) GetShell() (PostCommand, error) {
+	return getPostCommand("shell", p.Shell)
+}
+
+// GetCustomCommand returns what happens after a custom command completes
+func (p PostCommandConfig) GetCustomCommand() (PostCommand, error) {
+	return getPostCommand("custom_command", p.CustomCommand)
+}
+
+func (p PostCommandConfig) Validate() error {
+	for _, get := range []func() (PostCommand, error){p.GetExec, p.GetShell, p.GetCustomCommand} {
+		if _, err := get(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JJCommandConfig configures how jj is invoked
+type JJCommandConfig struct {
+	GlobalArgs []string `toml:"global_args"`
 }
 
 type Color struct {
@@ -118,7 +187,24 @@ func (t *ThemeConfig) UnmarshalTOML(data any) error {
 }
 
 type TracerConfig struct {
-	Enabled bool `toml:"enabled"`
+	Enabled bool   `toml:"enabled"`
+	Level   string `toml:"level"`
+	File    string `toml:"file"`
+}
+
+func GetLogLevel(c *Config) (slog.Level, error) {
+	switch value := c.UI.Tracer.Level; value {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid value for 'ui.tracer.level': %q (expected one of: debug, info, warn, error)", value)
+	}
 }
 
 type UIConfig struct {
@@ -126,15 +212,190 @@ type UIConfig struct {
 	Colors map[string]Color `toml:"colors"`
 	// TODO(ilyagr): It might make sense to rename this to `auto_refresh_period` to match `--period` option
 	// once we have a mechanism to deprecate the old name softly.
-	AutoRefreshInterval int          `toml:"auto_refresh_interval"`
-	Tracer              TracerConfig `toml:"tracer"`
+	AutoRefreshInterval  int          `toml:"auto_refresh_interval"`
+	AbsoluteTimestamps   bool         `toml:"absolute_timestamps"`
+	Tracer               TracerConfig `toml:"tracer"`
+	ReadOnly             bool         `toml:"readonly"`
+	DryRun               bool         `toml:"dry_run"`
+	FollowWorkingCopy    bool         `toml:"follow_working_copy"`
+	ShowSelectionSummary bool         `toml:"show_selection_summary"`
+	StatusBar            StatusConfig `toml:"statusbar"`
+	Flash                FlashConfig  `toml:"flash"`
+	ConfirmDefault       string       `toml:"confirm_default"`
+	Clipboard            string       `toml:"clipboard"`
+	Shell                string       `toml:"shell"`
+	// Confirmations are the message templates of the confirmations by their names
+	Confirmations map[string]string `toml:"confirmations"`
+}
+
+type FlashConfig struct {
+	// WidthPercentage limits the width of the messages, 0 lets them take the whole width
+	WidthPercentage float64 `toml:"width_percentage"`
+	// MaxLines cuts longer messages, 0 means no limit
+	MaxLines int `toml:"max_lines"`
+}
+
+type ClipboardMode int
+
+const (
+	ClipboardAuto ClipboardMode = iota
+	ClipboardSystem
+	ClipboardOSC52
+)
+
+// GetClipboardMode returns how text is copied to the clipboard, auto uses the system clipboard
+// and falls back to OSC 52 when no clipboard tool is available
+func GetClipboardMode(c *Config) (ClipboardMode, error) {
+	switch value := c.UI.Clipboard; value {
+	case "", "auto":
+		return ClipboardAuto, nil
+	case "system":
+		return ClipboardSystem, nil
+	case "osc52":
+		return ClipboardOSC52, nil
+	default:
+		return ClipboardAuto, fmt.Errorf("invalid value for 'ui.clipboard': %q (expected one of: auto, system, osc52)", value)
+	}
+}
+
+// GetConfirmDefaultNo reports whether confirmation dialogs start with the negative option highlighted
+func GetConfirmDefaultNo(c *Config) (bool, error) {
+	switch value := c.UI.ConfirmDefault; value {
+	case "", "yes":
+		return false, nil
+	case "no":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid value for 'ui.confirm_default': %q (expected one of: yes, no)", value)
+	}
+}
+
+// StatusSegments are the segments that can be listed in ui.statusbar.segments
+var StatusSegments = []string{"mode", "revset", "operation", "selection", "remote"}
+
+type StatusConfig struct {
+	Segments  []string `toml:"segments"`
+	Separator string   `toml:"separator"`
+}
+
+func (s StatusConfig) Validate() error {
+	for _, segment := range s.Segments {
+		if !slices.Contains(StatusSegments, segment) {
+			return fmt.Errorf("ui.statusbar.segments: unknown segment %q, available segments are: %s", segment, strings.Join(StatusSegments, ", "))
+		}
+	}
+	return nil
 }
 
 type RevisionsConfig struct {
-	LogBatching  bool   `toml:"log_batching"`
-	LogBatchSize int    `toml:"log_batch_size"`
-	Template     string `toml:"template"`
-	Revset       string `toml:"revset"`
+	LogBatching     bool              `toml:"log_batching"`
+	LogBatchSize    int               `toml:"log_batch_size"`
+	Template        string            `toml:"template"`
+	SummaryTemplate string            `toml:"summary_template"`
+	PreviewTemplate string            `toml:"preview_template"`
+	Revset          string            `toml:"revset"`
+	SavedRevsets    map[string]string `toml:"saved_revsets"`
+	Columns         []string          `toml:"columns"`
+	IdType          string            `toml:"id_type"`
+	HiddenRevset    string            `toml:"hidden_revset"`
+	AutoSnapshot    bool              `toml:"auto_snapshot"`
+	GraphStyle      string            `toml:"graph_style"`
+	Elide           string            `toml:"elide"`
+	ElideBeyond     int               `toml:"elide_beyond"`
+	EmptyMessage    string            `toml:"empty_message"`
+}
+
+type GraphStyle int
+
+const (
+	GraphStyleGraph GraphStyle = iota
+	GraphStyleFlat
+)
+
+func GetGraphStyle(c *Config) (GraphStyle, error) {
+	switch value := c.Revisions.GraphStyle; value {
+	case "", "graph":
+		return GraphStyleGraph, nil
+	case "flat":
+		return GraphStyleFlat, nil
+	default:
+		return GraphStyleGraph, fmt.Errorf("invalid value for 'revisions.graph_style': %q (expected one of: graph, flat)", value)
+	}
+}
+
+// GetElision tells whether runs of intermediate revisions are elided and how many revisions
+// of each run are kept
+func GetElision(c *Config) (bool, int, error) {
+	switch value := c.Revisions.Elide; value {
+	case "", "never":
+		return false, 0, nil
+	case "always":
+		return true, 0, nil
+	case "beyond":
+		if c.Revisions.ElideBeyond < 0 {
+			return false, 0, fmt.Errorf("invalid value for 'revisions.elide_beyond': %d (expected a positive number)", c.Revisions.ElideBeyond)
+		}
+		return true, c.Revisions.ElideBeyond, nil
+	default:
+		return false, 0, fmt.Errorf("invalid value for 'revisions.elide': %q (expected one of: never, always, beyond)", value)
+	}
+}
+
+type IdType int
+
+const (
+	IdTypeChangeId IdType = iota
+	IdTypeCommitId
+)
+
+func GetIdType(c *Config) (IdType, error) {
+	switch value := c.Revisions.IdType; value {
+	case "", "change_id":
+		return IdTypeChangeId, nil
+	case "commit_id":
+		return IdTypeCommitId, nil
+	default:
+		return IdTypeChangeId, fmt.Errorf("invalid value for 'revisions.id_type': %q (expected one of: change_id, commit_id)", value)
+	}
+}
+
+// GetLogTemplate returns the template of the revisions view, revisions.template takes
+// precedence over the template assembled from revisions.columns. It is empty when
+// jj's templates.log is to be used.
+func (r RevisionsConfig) GetLogTemplate() string {
+	if r.Template != "" {
+		return r.Template
+	}
+	template, _ := r.ColumnsTemplate()
+	return template
+}
+
+// GetSummaryTemplate returns the template of the selection summary, falling back to the
+// template of the revisions view
+func (r RevisionsConfig) GetSummaryTemplate() string {
+	if r.SummaryTemplate != "" {
+		return r.SummaryTemplate
+	}
+	return r.GetLogTemplate()
+}
+
+// GetPreviewTemplate returns the template of the header shown above the preview of a
+// revision, falling back to the template of the revisions view
+func (r RevisionsConfig) GetPreviewTemplate() string {
+	if r.PreviewTemplate != "" {
+		return r.PreviewTemplate
+	}
+	return r.GetLogTemplate()
+}
+
+// SavedRevsetLabels returns the labels of the saved revsets sorted by name
+func (r RevisionsConfig) SavedRevsetLabels() []string {
+	labels := make([]string, 0, len(r.SavedRevsets))
+	for label := range r.SavedRevsets {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+	return labels
 }
 
 type PreviewPosition int
@@ -149,10 +410,12 @@ type PreviewConfig struct {
 	RevisionCommand          []string `toml:"revision_command"`
 	OplogCommand             []string `toml:"oplog_command"`
 	FileCommand              []string `toml:"file_command"`
+	AnnotateCommand          []string `toml:"annotate_command"`
 	ShowAtStart              bool     `toml:"show_at_start"`
 	Position                 string   `toml:"position"`
 	WidthPercentage          float64  `toml:"width_percentage"`
 	WidthIncrementPercentage float64  `toml:"width_increment_percentage"`
+	SyntaxHighlight          bool     `toml:"syntax_highlight"`
 }
 
 func GetPreviewPosition(c *Config) (PreviewPosition, error) {
@@ -169,10 +432,44 @@ func GetPreviewPosition(c *Config) (PreviewPosition, error) {
 }
 
 type DiffConfig struct {
+	Command []string            `toml:"command"`
+	Show    ShowOption          `toml:"show"`
+	Tools   map[string]DiffTool `toml:"tools"`
+	// SideBySide opens the diff view in the side by side layout
+	SideBySide bool `toml:"side_by_side"`
+}
+
+type DiffTool struct {
 	Command []string   `toml:"command"`
 	Show    ShowOption `toml:"show"`
 }
 
+// DefaultDiffTool is the name of the tool built from diff.command and diff.show
+const DefaultDiffTool = "default"
+
+// ToolNames returns the default tool followed by the configured tools sorted by name
+func (d DiffConfig) ToolNames() []string {
+	names := []string{DefaultDiffTool}
+	var tools []string
+	for name := range d.Tools {
+		if name != DefaultDiffTool {
+			tools = append(tools, name)
+		}
+	}
+	slices.Sort(tools)
+	return append(names, tools...)
+}
+
+func (d DiffConfig) GetTool(name string) (DiffTool, bool) {
+	if tool, ok := d.Tools[name]; ok {
+		return tool, true
+	}
+	if name == DefaultDiffTool {
+		return DiffTool{Command: d.Command, Show: d.Show}, true
+	}
+	return DiffTool{}, false
+}
+
 type OpLogConfig struct {
 	Limit int `toml:"limit"`
 }
@@ -259,7 +556,17 @@ const (
 )
 
 type SuggestConfig struct {
-	Exec SuggestExecConfig `toml:"exec"`
+	Exec   SuggestExecConfig   `toml:"exec"`
+	Revset SuggestRevsetConfig `toml:"revset"`
+}
+
+type SuggestRevsetConfig struct {
+	// MaxSuggestions limits the number of suggestions shown at once, 0 means no limit
+	MaxSuggestions int `toml:"max_suggestions"`
+	// MaxWidth limits the width of the suggestion line, 0 means no limit
+	MaxWidth int `toml:"max_width"`
+	// CountLimit is the number of matching revisions counted while typing, 0 turns the count off
+	CountLimit int `toml:"count_limit"`
 }
 
 type SuggestExecConfig struct {
@@ -281,6 +588,8 @@ func GetSuggestExecMode(c *Config) (SuggestMode, error) {
 
 type GitConfig struct {
 	DefaultRemote string `toml:"default_remote"`
+	MainBookmark  string `toml:"main_bookmark"`
+	SignCommits   bool   `toml:"sign_commits"`
 }
 
 func GetGitDefaultRemote(c *Config) string {
@@ -291,6 +600,15 @@ func GetGitDefaultRemote(c *Config) string {
 	return remote
 }
 
+// GetGitMainBookmark returns the bookmark moved by keys.bookmark.set_main, "main" when unset
+func GetGitMainBookmark(c *Config) string {
+	name := c.Git.MainBookmark
+	if strings.TrimSpace(name) == "" {
+		return "main"
+	}
+	return name
+}
+
 type SshConfig struct {
 	HijackAskpass bool `toml:"hijack_askpass"`
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -76,3 +78,297 @@ complex = { fg = "blue", bg = "white", bold = true }
 	assert.Equal(t, "white", config.UI.Colors["complex"].Bg)
 	assert.True(t, config.UI.Colors["complex"].Bold)
 }
+
+func TestLoad_DiffTools(t *testing.T) {
+	content := `
+[diff]
+command = ["diff", "-r", "$change_id"]
+
+[diff.tools.difft]
+command = ["diff", "--tool", "difft", "-r", "$change_id"]
+show = "interactive"
+
+[diff.tools.stat]
+command = ["diff", "--stat", "-r", "$change_id"]
+`
+	config := &Config{}
+	err := config.Load(content)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{DefaultDiffTool, "difft", "stat"}, config.Diff.ToolNames())
+
+	tool, ok := config.Diff.GetTool("difft")
+	assert.True(t, ok)
+	assert.Equal(t, ShowOptionInteractive, tool.Show)
+
+	tool, ok = config.Diff.GetTool(DefaultDiffTool)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"diff", "-r", "$change_id"}, tool.Command)
+
+	_, ok = config.Diff.GetTool("missing")
+	assert.False(t, ok)
+}
+
+func TestLoad_SavedRevsets(t *testing.T) {
+	content := `
+[revisions.saved_revsets]
+stack = "trunk()..@"
+mine = "mine()"
+`
+	config := &Config{}
+	err := config.Load(content)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mine", "stack"}, config.Revisions.SavedRevsetLabels())
+	assert.Equal(t, "trunk()..@", config.Revisions.SavedRevsets["stack"])
+}
+
+func TestLoad_Columns(t *testing.T) {
+	content := `
+[revisions]
+columns = ["change_id", "description"]
+`
+	config := &Config{}
+	err := config.Load(content)
+	assert.NoError(t, err)
+
+	template, err := config.Revisions.ColumnsTemplate()
+	assert.NoError(t, err)
+	assert.Equal(t, `separate(" ", format_short_change_id(change_id), if(description, description.first_line(), label("description placeholder", "(no description set)"))) ++ "\n"`, template)
+}
+
+func TestRevisionsConfig_Templates(t *testing.T) {
+	revisions := RevisionsConfig{}
+	assert.Empty(t, revisions.GetSummaryTemplate())
+	assert.Empty(t, revisions.GetPreviewTemplate())
+
+	revisions.Columns = []string{"change_id"}
+	assert.Equal(t, `separate(" ", format_short_change_id(change_id)) ++ "\n"`, revisions.GetSummaryTemplate())
+	assert.Equal(t, `separate(" ", format_short_change_id(change_id)) ++ "\n"`, revisions.GetPreviewTemplate())
+
+	revisions.Template = "builtin_log_compact"
+	revisions.PreviewTemplate = "builtin_log_oneline"
+	assert.Equal(t, "builtin_log_compact", revisions.GetLogTemplate())
+	assert.Equal(t, "builtin_log_compact", revisions.GetSummaryTemplate())
+	assert.Equal(t, "builtin_log_oneline", revisions.GetPreviewTemplate())
+
+	revisions.SummaryTemplate = "description"
+	assert.Equal(t, "description", revisions.GetSummaryTemplate())
+}
+
+func TestLoad_UnknownColumn(t *testing.T) {
+	content := `
+[revisions]
+columns = ["change_id", "reviewer"]
+`
+	config := &Config{}
+	err := config.Load(content)
+	assert.ErrorContains(t, err, `unknown column "reviewer"`)
+}
+
+func TestLoad_IdType(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[revisions]
+id_type = "commit_id"
+`)
+	assert.NoError(t, err)
+	idType, err := GetIdType(config)
+	assert.NoError(t, err)
+	assert.Equal(t, IdTypeCommitId, idType)
+
+	err = config.Load(`
+[revisions]
+id_type = "git_hash"
+`)
+	assert.ErrorContains(t, err, "revisions.id_type")
+}
+
+func TestLoad_GraphStyle(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[revisions]
+graph_style = "flat"
+`)
+	assert.NoError(t, err)
+	style, err := GetGraphStyle(config)
+	assert.NoError(t, err)
+	assert.Equal(t, GraphStyleFlat, style)
+
+	err = config.Load(`
+[revisions]
+graph_style = "tree"
+`)
+	assert.ErrorContains(t, err, "revisions.graph_style")
+}
+
+func TestLoad_Elide(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[revisions]
+elide = "beyond"
+elide_beyond = 5
+`)
+	assert.NoError(t, err)
+	elide, keep, err := GetElision(config)
+	assert.NoError(t, err)
+	assert.True(t, elide)
+	assert.Equal(t, 5, keep)
+
+	err = config.Load(`
+[revisions]
+elide = "sometimes"
+`)
+	assert.ErrorContains(t, err, "revisions.elide")
+}
+
+func TestGetKeyMap_ReadOnlyMarksMutatingBindings(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[ui]
+readonly = true
+[keys]
+abandon = ["a"]
+diff = ["d"]
+`)
+	assert.NoError(t, err)
+	keyMap := config.GetKeyMap()
+	assert.Equal(t, "abandon (read-only)", keyMap.Abandon.Help().Desc)
+	assert.Equal(t, "diff", keyMap.Diff.Help().Desc)
+}
+
+func TestState_SaveAndLoad(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	state := LoadState()
+	assert.Equal(t, 0.0, state.PreviewWidthPercentage)
+
+	state.SetPreviewWidthPercentage(35)
+	assert.NoError(t, state.Save())
+	assert.Equal(t, 35.0, LoadState().PreviewWidthPercentage)
+}
+
+func TestState_AddRecentRepository(t *testing.T) {
+	state := &State{}
+	state.AddRecentRepository("/a")
+	state.AddRecentRepository("/b")
+	state.AddRecentRepository("/a")
+	assert.Equal(t, []string{"/a", "/b"}, state.RecentRepositories)
+
+	for i := range maxRecentRepositories {
+		state.AddRecentRepository(fmt.Sprintf("/%d", i))
+	}
+	assert.Len(t, state.RecentRepositories, maxRecentRepositories)
+	assert.Equal(t, "/9", state.RecentRepositories[0])
+}
+
+func TestLoad_TracerLevel(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[ui.tracer]
+level = "debug"
+file = "/tmp/jjui.log"
+`)
+	assert.NoError(t, err)
+	level, err := GetLogLevel(config)
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, level)
+	assert.Equal(t, "/tmp/jjui.log", config.UI.Tracer.File)
+
+	err = config.Load(`
+[ui.tracer]
+level = "verbose"
+`)
+	assert.ErrorContains(t, err, "ui.tracer.level")
+}
+
+func TestLoad_StatusBarSegments(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[ui.statusbar]
+segments = ["mode", "selection"]
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mode", "selection"}, config.UI.StatusBar.Segments)
+
+	err = config.Load(`
+[ui.statusbar]
+segments = ["mode", "clock"]
+`)
+	assert.ErrorContains(t, err, `unknown segment "clock"`)
+}
+
+func TestLoad_ConfirmDefault(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[ui]
+confirm_default = "no"
+`)
+	assert.NoError(t, err)
+	defaultNo, err := GetConfirmDefaultNo(config)
+	assert.NoError(t, err)
+	assert.True(t, defaultNo)
+
+	err = config.Load(`
+[ui]
+confirm_default = "maybe"
+`)
+	assert.ErrorContains(t, err, "ui.confirm_default")
+}
+
+func TestLoad_Clipboard(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[ui]
+clipboard = "osc52"
+`)
+	assert.NoError(t, err)
+	mode, err := GetClipboardMode(config)
+	assert.NoError(t, err)
+	assert.Equal(t, ClipboardOSC52, mode)
+
+	err = config.Load(`
+[ui]
+clipboard = "xclip"
+`)
+	assert.ErrorContains(t, err, "ui.clipboard")
+}
+
+func TestLoad_PostCommand(t *testing.T) {
+	config := &Config{}
+	err := config.Load(`
+[post_command]
+exec = "keep_selections"
+shell = "none"
+`)
+	assert.NoError(t, err)
+	exec, err := config.PostCommand.GetExec()
+	assert.NoError(t, err)
+	assert.Equal(t, PostCommandKeepSelections, exec)
+	shell, err := config.PostCommand.GetShell()
+	assert.NoError(t, err)
+	assert.Equal(t, PostCommandNone, shell)
+	customCommand, err := config.PostCommand.GetCustomCommand()
+	assert.NoError(t, err)
+	assert.Equal(t, PostCommandRefresh, customCommand)
+
+	err = config.Load(`
+[post_command]
+custom_command = "reload"
+`)
+	assert.ErrorContains(t, err, "post_command.custom_command")
+}
+
+func TestState_SavesTheToggles(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	state := LoadState()
+	assert.Nil(t, state.AbsoluteTimestamps)
+	assert.Empty(t, state.GraphStyle)
+
+	state.SetAbsoluteTimestamps(false)
+	state.SetGraphStyle("flat")
+	assert.NoError(t, state.Save())
+
+	loaded := LoadState()
+	assert.Equal(t, false, *loaded.AbsoluteTimestamps)
+	assert.Equal(t, "flat", loaded.GraphStyle)
+}
@@ -26,6 +26,8 @@ func (c *JJConfig) GetApplicableColors() map[string]Color {
 		"diff copied",
 		"diff modified",
 		"diff removed",
+		"diff added token",
+		"diff removed token",
 		"change_id",
 		"commit_id",
 		"conflict",
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -8,48 +9,81 @@ import (
 
 func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 	return KeyMappings[key.Binding]{
-		Up:                key.NewBinding(key.WithKeys(m.Up...), key.WithHelp(JoinKeys(m.Up), "up")),
-		Down:              key.NewBinding(key.WithKeys(m.Down...), key.WithHelp(JoinKeys(m.Down), "down")),
-		ScrollUp:          key.NewBinding(key.WithKeys(m.ScrollUp...), key.WithHelp(JoinKeys(m.ScrollUp), "scroll up")),
-		ScrollDown:        key.NewBinding(key.WithKeys(m.ScrollDown...), key.WithHelp(JoinKeys(m.ScrollDown), "scroll down")),
-		JumpToParent:      key.NewBinding(key.WithKeys(m.JumpToParent...), key.WithHelp(JoinKeys(m.JumpToParent), "jump to parent")),
-		JumpToChildren:    key.NewBinding(key.WithKeys(m.JumpToChildren...), key.WithHelp(JoinKeys(m.JumpToChildren), "jump to children")),
-		JumpToWorkingCopy: key.NewBinding(key.WithKeys(m.JumpToWorkingCopy...), key.WithHelp(JoinKeys(m.JumpToWorkingCopy), "jump to working copy")),
-		Apply:             key.NewBinding(key.WithKeys(m.Apply...), key.WithHelp(JoinKeys(m.Apply), "apply")),
-		ForceApply:        key.NewBinding(key.WithKeys(m.ForceApply...), key.WithHelp(JoinKeys(m.ForceApply), "force apply")),
-		Cancel:            key.NewBinding(key.WithKeys(m.Cancel...), key.WithHelp(JoinKeys(m.Cancel), "cancel")),
-		ToggleSelect:      key.NewBinding(key.WithKeys(m.ToggleSelect...), key.WithHelp(JoinKeys(m.ToggleSelect), "toggle selection")),
-		New:               key.NewBinding(key.WithKeys(m.New...), key.WithHelp(JoinKeys(m.New), "new")),
-		Commit:            key.NewBinding(key.WithKeys(m.Commit...), key.WithHelp(JoinKeys(m.Commit), "commit")),
-		Refresh:           key.NewBinding(key.WithKeys(m.Refresh...), key.WithHelp(JoinKeys(m.Refresh), "refresh")),
-		Quit:              key.NewBinding(key.WithKeys(m.Quit...), key.WithHelp(JoinKeys(m.Quit), "quit")),
-		Diff:              key.NewBinding(key.WithKeys(m.Diff...), key.WithHelp(JoinKeys(m.Diff), "diff")),
-		Describe:          key.NewBinding(key.WithKeys(m.Describe...), key.WithHelp(JoinKeys(m.Describe), "describe")),
-		Undo:              key.NewBinding(key.WithKeys(m.Undo...), key.WithHelp(JoinKeys(m.Undo), "undo")),
-		Redo:              key.NewBinding(key.WithKeys(m.Redo...), key.WithHelp(JoinKeys(m.Redo), "redo")),
-		Abandon:           key.NewBinding(key.WithKeys(m.Abandon...), key.WithHelp(JoinKeys(m.Abandon), "abandon")),
-		Edit:              key.NewBinding(key.WithKeys(m.Edit...), key.WithHelp(JoinKeys(m.Edit), "edit")),
-		ForceEdit:         key.NewBinding(key.WithKeys(m.ForceEdit...), key.WithHelp(JoinKeys(m.ForceEdit), "force edit")),
-		Diffedit:          key.NewBinding(key.WithKeys(m.Diffedit...), key.WithHelp(JoinKeys(m.Diffedit), "diff edit")),
-		Absorb:            key.NewBinding(key.WithKeys(m.Absorb...), key.WithHelp(JoinKeys(m.Absorb), "absorb")),
-		Split:             key.NewBinding(key.WithKeys(m.Split...), key.WithHelp(JoinKeys(m.Split), "split")),
-		SplitParallel:     key.NewBinding(key.WithKeys(m.SplitParallel...), key.WithHelp(JoinKeys(m.SplitParallel), "split (parallel)")),
-		Help:              key.NewBinding(key.WithKeys(m.Help...), key.WithHelp(JoinKeys(m.Help), "help")),
+		Up:                            key.NewBinding(key.WithKeys(m.Up...), key.WithHelp(JoinKeys(m.Up), "up")),
+		Down:                          key.NewBinding(key.WithKeys(m.Down...), key.WithHelp(JoinKeys(m.Down), "down")),
+		ScrollUp:                      key.NewBinding(key.WithKeys(m.ScrollUp...), key.WithHelp(JoinKeys(m.ScrollUp), "scroll up")),
+		ScrollDown:                    key.NewBinding(key.WithKeys(m.ScrollDown...), key.WithHelp(JoinKeys(m.ScrollDown), "scroll down")),
+		JumpToParent:                  key.NewBinding(key.WithKeys(m.JumpToParent...), key.WithHelp(JoinKeys(m.JumpToParent), "jump to parent")),
+		JumpToChildren:                key.NewBinding(key.WithKeys(m.JumpToChildren...), key.WithHelp(JoinKeys(m.JumpToChildren), "jump to children")),
+		JumpToWorkingCopy:             key.NewBinding(key.WithKeys(m.JumpToWorkingCopy...), key.WithHelp(JoinKeys(m.JumpToWorkingCopy), "jump to working copy")),
+		JumpToTop:                     key.NewBinding(key.WithKeys(m.JumpToTop...), key.WithHelp(JoinKeys(m.JumpToTop), "jump to top (or gg)")),
+		JumpToBottom:                  key.NewBinding(key.WithKeys(m.JumpToBottom...), key.WithHelp(JoinKeys(m.JumpToBottom), "jump to bottom")),
+		Apply:                         key.NewBinding(key.WithKeys(m.Apply...), key.WithHelp(JoinKeys(m.Apply), "apply")),
+		ForceApply:                    key.NewBinding(key.WithKeys(m.ForceApply...), key.WithHelp(JoinKeys(m.ForceApply), "force apply")),
+		Cancel:                        key.NewBinding(key.WithKeys(m.Cancel...), key.WithHelp(JoinKeys(m.Cancel), "cancel")),
+		ToggleSelect:                  key.NewBinding(key.WithKeys(m.ToggleSelect...), key.WithHelp(JoinKeys(m.ToggleSelect), "toggle selection")),
+		New:                           key.NewBinding(key.WithKeys(m.New...), key.WithHelp(JoinKeys(m.New), "new")),
+		NewDescribed:                  key.NewBinding(key.WithKeys(m.NewDescribed...), key.WithHelp(JoinKeys(m.NewDescribed), "new with description")),
+		NewMerge:                      key.NewBinding(key.WithKeys(m.NewMerge...), key.WithHelp(JoinKeys(m.NewMerge), "new merge of selected")),
+		Commit:                        key.NewBinding(key.WithKeys(m.Commit...), key.WithHelp(JoinKeys(m.Commit), "commit")),
+		Refresh:                       key.NewBinding(key.WithKeys(m.Refresh...), key.WithHelp(JoinKeys(m.Refresh), "refresh")),
+		Quit:                          key.NewBinding(key.WithKeys(m.Quit...), key.WithHelp(JoinKeys(m.Quit), "quit")),
+		Diff:                          key.NewBinding(key.WithKeys(m.Diff...), key.WithHelp(JoinKeys(m.Diff), "diff")),
+		DiffTool:                      key.NewBinding(key.WithKeys(m.DiffTool...), key.WithHelp(JoinKeys(m.DiffTool), "diff with tool")),
+		ExportDiff:                    key.NewBinding(key.WithKeys(m.ExportDiff...), key.WithHelp(JoinKeys(m.ExportDiff), "export diff")),
+		ExportGraph:                   key.NewBinding(key.WithKeys(m.ExportGraph...), key.WithHelp(JoinKeys(m.ExportGraph), "export graph")),
+		DiffSideBySide:                key.NewBinding(key.WithKeys(m.DiffSideBySide...), key.WithHelp(JoinKeys(m.DiffSideBySide), "side by side")),
+		CopyPatch:                     key.NewBinding(key.WithKeys(m.CopyPatch...), key.WithHelp(JoinKeys(m.CopyPatch), "copy as patch")),
+		Describe:                      key.NewBinding(key.WithKeys(m.Describe...), key.WithHelp(JoinKeys(m.Describe), "describe")),
+		Undo:                          key.NewBinding(key.WithKeys(m.Undo...), key.WithHelp(JoinKeys(m.Undo), "undo")),
+		UndoMany:                      key.NewBinding(key.WithKeys(m.UndoMany...), key.WithHelp(JoinKeys(m.UndoMany), "undo operations")),
+		Redo:                          key.NewBinding(key.WithKeys(m.Redo...), key.WithHelp(JoinKeys(m.Redo), "redo")),
+		Abandon:                       key.NewBinding(key.WithKeys(m.Abandon...), key.WithHelp(JoinKeys(m.Abandon), "abandon")),
+		Edit:                          key.NewBinding(key.WithKeys(m.Edit...), key.WithHelp(JoinKeys(m.Edit), "edit")),
+		ForceEdit:                     key.NewBinding(key.WithKeys(m.ForceEdit...), key.WithHelp(JoinKeys(m.ForceEdit), "force edit")),
+		Diffedit:                      key.NewBinding(key.WithKeys(m.Diffedit...), key.WithHelp(JoinKeys(m.Diffedit), "diff edit")),
+		Absorb:                        key.NewBinding(key.WithKeys(m.Absorb...), key.WithHelp(JoinKeys(m.Absorb), "absorb")),
+		RebaseOntoWorkingCopy:         key.NewBinding(key.WithKeys(m.RebaseOntoWorkingCopy...), key.WithHelp(JoinKeys(m.RebaseOntoWorkingCopy), "move onto @")),
+		RebaseRevisionOntoWorkingCopy: key.NewBinding(key.WithKeys(m.RebaseRevisionOntoWorkingCopy...), key.WithHelp(JoinKeys(m.RebaseRevisionOntoWorkingCopy), "move revision onto @")),
+		Split:                         key.NewBinding(key.WithKeys(m.Split...), key.WithHelp(JoinKeys(m.Split), "split")),
+		SplitParallel:                 key.NewBinding(key.WithKeys(m.SplitParallel...), key.WithHelp(JoinKeys(m.SplitParallel), "split (parallel)")),
+		Help:                          key.NewBinding(key.WithKeys(m.Help...), key.WithHelp(JoinKeys(m.Help), "help")),
 		Evolog: evologModeKeys[key.Binding]{
 			Mode:    key.NewBinding(key.WithKeys(m.Evolog.Mode...), key.WithHelp(JoinKeys(m.Evolog.Mode), "evolog")),
 			Diff:    key.NewBinding(key.WithKeys(m.Evolog.Diff...), key.WithHelp(JoinKeys(m.Evolog.Diff), "diff")),
 			Restore: key.NewBinding(key.WithKeys(m.Evolog.Restore...), key.WithHelp(JoinKeys(m.Evolog.Restore), "restore")),
 		},
 		Revset:           key.NewBinding(key.WithKeys(m.Revset...), key.WithHelp(JoinKeys(m.Revset), "revset")),
+		SavedRevsets:     key.NewBinding(key.WithKeys(m.SavedRevsets...), key.WithHelp(JoinKeys(m.SavedRevsets), "saved revsets")),
+		CopyRevset:       key.NewBinding(key.WithKeys(m.CopyRevset...), key.WithHelp(JoinKeys(m.CopyRevset), "copy revset")),
+		PasteRevset:      key.NewBinding(key.WithKeys(m.PasteRevset...), key.WithHelp(JoinKeys(m.PasteRevset), "paste revset")),
 		AceJump:          key.NewBinding(key.WithKeys(m.AceJump...), key.WithHelp(JoinKeys(m.AceJump), "ace jump")),
+		GotoChange:       key.NewBinding(key.WithKeys(m.GotoChange...), key.WithHelp(JoinKeys(m.GotoChange), "go to change id")),
 		QuickSearch:      key.NewBinding(key.WithKeys(m.QuickSearch...), key.WithHelp(JoinKeys(m.QuickSearch), "quick search")),
 		QuickSearchCycle: key.NewBinding(key.WithKeys(m.QuickSearchCycle...), key.WithHelp(JoinKeys(m.QuickSearchCycle), "locate next match")),
 		CustomCommands:   key.NewBinding(key.WithKeys(m.CustomCommands...), key.WithHelp(JoinKeys(m.CustomCommands), "custom commands menu")),
+		RepeatCommand:    key.NewBinding(key.WithKeys(m.RepeatCommand...), key.WithHelp(JoinKeys(m.RepeatCommand), "repeat last custom command")),
 		Leader:           key.NewBinding(key.WithKeys(m.Leader...), key.WithHelp(JoinKeys(m.Leader), "leader")),
+		LeaderTimeoutMs:  m.LeaderTimeoutMs,
 		Suspend:          key.NewBinding(key.WithKeys(m.Suspend...), key.WithHelp(JoinKeys(m.Suspend), "suspend")),
+		ExpandMessage:    key.NewBinding(key.WithKeys(m.ExpandMessage...), key.WithHelp(JoinKeys(m.ExpandMessage), "expand message")),
 		SetParents:       key.NewBinding(key.WithKeys(m.SetParents...), key.WithHelp(JoinKeys(m.SetParents), "set parents")),
+		ToggleTimestamps: key.NewBinding(key.WithKeys(m.ToggleTimestamps...), key.WithHelp(JoinKeys(m.ToggleTimestamps), "toggle absolute timestamps")),
+		ToggleIdType:     key.NewBinding(key.WithKeys(m.ToggleIdType...), key.WithHelp(JoinKeys(m.ToggleIdType), "toggle change/commit id")),
+		ToggleHidden:     key.NewBinding(key.WithKeys(m.ToggleHidden...), key.WithHelp(JoinKeys(m.ToggleHidden), "toggle hidden revisions")),
+		ToggleGraph:      key.NewBinding(key.WithKeys(m.ToggleGraph...), key.WithHelp(JoinKeys(m.ToggleGraph), "toggle graph/flat list")),
+		ToggleDryRun:     key.NewBinding(key.WithKeys(m.ToggleDryRun...), key.WithHelp(JoinKeys(m.ToggleDryRun), "toggle dry run")),
+		ToggleSigning:    key.NewBinding(key.WithKeys(m.ToggleSigning...), key.WithHelp(JoinKeys(m.ToggleSigning), "toggle signing commits")),
+		Repositories:     key.NewBinding(key.WithKeys(m.Repositories...), key.WithHelp(JoinKeys(m.Repositories), "recent repositories")),
+		Workspaces:       key.NewBinding(key.WithKeys(m.Workspaces...), key.WithHelp(JoinKeys(m.Workspaces), "switch workspace")),
+		ResetRevset:      key.NewBinding(key.WithKeys(m.ResetRevset...), key.WithHelp(JoinKeys(m.ResetRevset), "reset revset when empty")),
+		SelectRevset:     key.NewBinding(key.WithKeys(m.SelectRevset...), key.WithHelp(JoinKeys(m.SelectRevset), "select revisions matching revset")),
+		InvertSelection:  key.NewBinding(key.WithKeys(m.InvertSelection...), key.WithHelp(JoinKeys(m.InvertSelection), "invert selection")),
+		ExpandElided:     key.NewBinding(key.WithKeys(m.ExpandElided...), key.WithHelp(JoinKeys(m.ExpandElided), "expand/collapse elided revisions")),
+		CollapseElided:   key.NewBinding(key.WithKeys(m.CollapseElided...), key.WithHelp(JoinKeys(m.CollapseElided), "collapse all elided revisions")),
 		ExecJJ:           key.NewBinding(key.WithKeys(m.ExecJJ...), key.WithHelp(JoinKeys(m.ExecJJ), "interactive jj")),
 		ExecShell:        key.NewBinding(key.WithKeys(m.ExecShell...), key.WithHelp(JoinKeys(m.ExecShell), "interactive shell command")),
+		OpenShell:        key.NewBinding(key.WithKeys(m.OpenShell...), key.WithHelp(JoinKeys(m.OpenShell), "open shell at revision")),
 		Revert: revertModeKeys[key.Binding]{
 			Mode:   key.NewBinding(key.WithKeys(m.Revert.Mode...), key.WithHelp(JoinKeys(m.Revert.Mode), "revert")),
 			After:  key.NewBinding(key.WithKeys(m.Revert.After...), key.WithHelp(JoinKeys(m.Revert.After), "insert after")),
@@ -67,12 +101,19 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			Onto:        key.NewBinding(key.WithKeys(m.Rebase.Onto...), key.WithHelp(JoinKeys(m.Rebase.Onto), "onto")),
 			Insert:      key.NewBinding(key.WithKeys(m.Rebase.Insert...), key.WithHelp(JoinKeys(m.Rebase.Insert), "insert between")),
 			SkipEmptied: key.NewBinding(key.WithKeys(m.Rebase.SkipEmptied...), key.WithHelp(JoinKeys(m.Rebase.SkipEmptied), "skip emptied")),
+			Reorder:     key.NewBinding(key.WithKeys(m.Rebase.Reorder...), key.WithHelp(JoinKeys(m.Rebase.Reorder), "reorder checked")),
 		},
 		Duplicate: duplicateModeKeys[key.Binding]{
-			Mode:   key.NewBinding(key.WithKeys(m.Duplicate.Mode...), key.WithHelp(JoinKeys(m.Duplicate.Mode), "duplicate")),
-			After:  key.NewBinding(key.WithKeys(m.Duplicate.After...), key.WithHelp(JoinKeys(m.Duplicate.After), "duplicate after")),
-			Before: key.NewBinding(key.WithKeys(m.Duplicate.Before...), key.WithHelp(JoinKeys(m.Duplicate.Before), "duplicate before")),
-			Onto:   key.NewBinding(key.WithKeys(m.Duplicate.Onto...), key.WithHelp(JoinKeys(m.Duplicate.Onto), "duplicate onto")),
+			Mode:    key.NewBinding(key.WithKeys(m.Duplicate.Mode...), key.WithHelp(JoinKeys(m.Duplicate.Mode), "duplicate")),
+			After:   key.NewBinding(key.WithKeys(m.Duplicate.After...), key.WithHelp(JoinKeys(m.Duplicate.After), "duplicate after")),
+			Before:  key.NewBinding(key.WithKeys(m.Duplicate.Before...), key.WithHelp(JoinKeys(m.Duplicate.Before), "duplicate before")),
+			Onto:    key.NewBinding(key.WithKeys(m.Duplicate.Onto...), key.WithHelp(JoinKeys(m.Duplicate.Onto), "duplicate onto")),
+			Choose:  key.NewBinding(key.WithKeys(m.Duplicate.Choose...), key.WithHelp(JoinKeys(m.Duplicate.Choose), "choose destination")),
+			Reorder: key.NewBinding(key.WithKeys(m.Duplicate.Reorder...), key.WithHelp(JoinKeys(m.Duplicate.Reorder), "reorder checked")),
+		},
+		Reorder: reorderKeys[key.Binding]{
+			MoveUp:   key.NewBinding(key.WithKeys(m.Reorder.MoveUp...), key.WithHelp(JoinKeys(m.Reorder.MoveUp), "move up")),
+			MoveDown: key.NewBinding(key.WithKeys(m.Reorder.MoveDown...), key.WithHelp(JoinKeys(m.Reorder.MoveDown), "move down")),
 		},
 		Squash: squashModeKeys[key.Binding]{
 			Mode:                  key.NewBinding(key.WithKeys(m.Squash.Mode...), key.WithHelp(JoinKeys(m.Squash.Mode), "squash")),
@@ -85,21 +126,34 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			Close:                 key.NewBinding(key.WithKeys(m.Details.Close...), key.WithHelp(JoinKeys(m.Details.Close), "close")),
 			Split:                 key.NewBinding(key.WithKeys(m.Details.Split...), key.WithHelp(JoinKeys(m.Details.Split), "split")),
 			SplitParallel:         key.NewBinding(key.WithKeys(m.Details.SplitParallel...), key.WithHelp(JoinKeys(m.Details.SplitParallel), "split (parallel)")),
+			SplitInteractive:      key.NewBinding(key.WithKeys(m.Details.SplitInteractive...), key.WithHelp(JoinKeys(m.Details.SplitInteractive), "split hunks")),
 			Squash:                key.NewBinding(key.WithKeys(m.Details.Squash...), key.WithHelp(JoinKeys(m.Details.Squash), "squash")),
 			Restore:               key.NewBinding(key.WithKeys(m.Details.Restore...), key.WithHelp(JoinKeys(m.Details.Restore), "restore")),
 			Absorb:                key.NewBinding(key.WithKeys(m.Details.Absorb...), key.WithHelp(JoinKeys(m.Details.Absorb), "absorb")),
 			Diff:                  key.NewBinding(key.WithKeys(m.Details.Diff...), key.WithHelp(JoinKeys(m.Details.Diff), "diff")),
 			ToggleSelect:          key.NewBinding(key.WithKeys(m.Details.ToggleSelect...), key.WithHelp(JoinKeys(m.Details.ToggleSelect), "details toggle select")),
 			RevisionsChangingFile: key.NewBinding(key.WithKeys(m.Details.RevisionsChangingFile...), key.WithHelp(JoinKeys(m.Details.RevisionsChangingFile), "show revisions changing file")),
+			OpenInEditor:          key.NewBinding(key.WithKeys(m.Details.OpenInEditor...), key.WithHelp(JoinKeys(m.Details.OpenInEditor), "open in editor")),
+			NextConflict:          key.NewBinding(key.WithKeys(m.Details.NextConflict...), key.WithHelp(JoinKeys(m.Details.NextConflict), "next conflict")),
+			PrevConflict:          key.NewBinding(key.WithKeys(m.Details.PrevConflict...), key.WithHelp(JoinKeys(m.Details.PrevConflict), "previous conflict")),
+			Resolve:               key.NewBinding(key.WithKeys(m.Details.Resolve...), key.WithHelp(JoinKeys(m.Details.Resolve), "resolve conflicts")),
+			RestoreDeleted:        key.NewBinding(key.WithKeys(m.Details.RestoreDeleted...), key.WithHelp(JoinKeys(m.Details.RestoreDeleted), "restore deleted file")),
+			Follow:                key.NewBinding(key.WithKeys(m.Details.Follow...), key.WithHelp(JoinKeys(m.Details.Follow), "follow selection")),
+			SplitSession:          key.NewBinding(key.WithKeys(m.Details.SplitSession...), key.WithHelp(JoinKeys(m.Details.SplitSession), "split file by file")),
+			NextRevision:          key.NewBinding(key.WithKeys(m.Details.NextRevision...), key.WithHelp(JoinKeys(m.Details.NextRevision), "next revision")),
+			PrevRevision:          key.NewBinding(key.WithKeys(m.Details.PrevRevision...), key.WithHelp(JoinKeys(m.Details.PrevRevision), "previous revision")),
 		},
 		Bookmark: bookmarkModeKeys[key.Binding]{
 			Mode:    key.NewBinding(key.WithKeys(m.Bookmark.Mode...), key.WithHelp(JoinKeys(m.Bookmark.Mode), "bookmarks")),
 			Set:     key.NewBinding(key.WithKeys(m.Bookmark.Set...), key.WithHelp(JoinKeys(m.Bookmark.Set), "set bookmark")),
+			Create:  key.NewBinding(key.WithKeys(m.Bookmark.Create...), key.WithHelp(JoinKeys(m.Bookmark.Create), "create bookmark with a generated name")),
 			Delete:  key.NewBinding(key.WithKeys(m.Bookmark.Delete...), key.WithHelp(JoinKeys(m.Bookmark.Delete), "delete")),
 			Move:    key.NewBinding(key.WithKeys(m.Bookmark.Move...), key.WithHelp(JoinKeys(m.Bookmark.Move), "move")),
 			Forget:  key.NewBinding(key.WithKeys(m.Bookmark.Forget...), key.WithHelp(JoinKeys(m.Bookmark.Forget), "forget")),
 			Track:   key.NewBinding(key.WithKeys(m.Bookmark.Track...), key.WithHelp(JoinKeys(m.Bookmark.Track), "track")),
 			Untrack: key.NewBinding(key.WithKeys(m.Bookmark.Untrack...), key.WithHelp(JoinKeys(m.Bookmark.Untrack), "untrack")),
+			Rename:  key.NewBinding(key.WithKeys(m.Bookmark.Rename...), key.WithHelp(JoinKeys(m.Bookmark.Rename), "rename")),
+			SetMain: key.NewBinding(key.WithKeys(m.Bookmark.SetMain...), key.WithHelp(JoinKeys(m.Bookmark.SetMain), "move main bookmark here")),
 		},
 		Preview: previewModeKeys[key.Binding]{
 			Mode:         key.NewBinding(key.WithKeys(m.Preview.Mode...), key.WithHelp(JoinKeys(m.Preview.Mode), "preview")),
@@ -110,16 +164,26 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			HalfPageUp:   key.NewBinding(key.WithKeys(m.Preview.HalfPageUp...), key.WithHelp(JoinKeys(m.Preview.HalfPageUp), "preview half page up")),
 			Expand:       key.NewBinding(key.WithKeys(m.Preview.Expand...), key.WithHelp(JoinKeys(m.Preview.Expand), "expand width")),
 			Shrink:       key.NewBinding(key.WithKeys(m.Preview.Shrink...), key.WithHelp(JoinKeys(m.Preview.Shrink), "shrink width")),
+			Search:       key.NewBinding(key.WithKeys(m.Preview.Search...), key.WithHelp(JoinKeys(m.Preview.Search), "preview search")),
+			SearchNext:   key.NewBinding(key.WithKeys(m.Preview.SearchNext...), key.WithHelp(JoinKeys(m.Preview.SearchNext), "preview next match")),
+			SearchPrev:   key.NewBinding(key.WithKeys(m.Preview.SearchPrev...), key.WithHelp(JoinKeys(m.Preview.SearchPrev), "preview previous match")),
+			Annotate:     key.NewBinding(key.WithKeys(m.Preview.Annotate...), key.WithHelp(JoinKeys(m.Preview.Annotate), "toggle file annotations")),
 		},
 		Git: gitModeKeys[key.Binding]{
-			Mode:  key.NewBinding(key.WithKeys(m.Git.Mode...), key.WithHelp(JoinKeys(m.Git.Mode), "git")),
-			Push:  key.NewBinding(key.WithKeys(m.Git.Push...), key.WithHelp(JoinKeys(m.Git.Push), "git push")),
-			Fetch: key.NewBinding(key.WithKeys(m.Git.Fetch...), key.WithHelp(JoinKeys(m.Git.Fetch), "git fetch")),
+			Mode:     key.NewBinding(key.WithKeys(m.Git.Mode...), key.WithHelp(JoinKeys(m.Git.Mode), "git")),
+			Push:     key.NewBinding(key.WithKeys(m.Git.Push...), key.WithHelp(JoinKeys(m.Git.Push), "git push")),
+			Fetch:    key.NewBinding(key.WithKeys(m.Git.Fetch...), key.WithHelp(JoinKeys(m.Git.Fetch), "git fetch")),
+			FetchAll: key.NewBinding(key.WithKeys(m.Git.FetchAll...), key.WithHelp(JoinKeys(m.Git.FetchAll), "git fetch all remotes")),
+			Remote:   key.NewBinding(key.WithKeys(m.Git.Remote...), key.WithHelp(JoinKeys(m.Git.Remote), "choose remote")),
 		},
 		OpLog: opLogModeKeys[key.Binding]{
-			Mode:    key.NewBinding(key.WithKeys(m.OpLog.Mode...), key.WithHelp(JoinKeys(m.OpLog.Mode), "oplog")),
-			Restore: key.NewBinding(key.WithKeys(m.OpLog.Restore...), key.WithHelp(JoinKeys(m.OpLog.Restore), "restore")),
-			Revert:  key.NewBinding(key.WithKeys(m.OpLog.Revert...), key.WithHelp(JoinKeys(m.OpLog.Revert), "revert")),
+			Mode:       key.NewBinding(key.WithKeys(m.OpLog.Mode...), key.WithHelp(JoinKeys(m.OpLog.Mode), "oplog")),
+			Restore:    key.NewBinding(key.WithKeys(m.OpLog.Restore...), key.WithHelp(JoinKeys(m.OpLog.Restore), "restore")),
+			Revert:     key.NewBinding(key.WithKeys(m.OpLog.Revert...), key.WithHelp(JoinKeys(m.OpLog.Revert), "revert")),
+			Jump:       key.NewBinding(key.WithKeys(m.OpLog.Jump...), key.WithHelp(JoinKeys(m.OpLog.Jump), "jump to operation")),
+			LoadMore:   key.NewBinding(key.WithKeys(m.OpLog.LoadMore...), key.WithHelp(JoinKeys(m.OpLog.LoadMore), "load more")),
+			ViewAt:     key.NewBinding(key.WithKeys(m.OpLog.ViewAt...), key.WithHelp(JoinKeys(m.OpLog.ViewAt), "view at operation")),
+			ViewLatest: key.NewBinding(key.WithKeys(m.OpLog.ViewLatest...), key.WithHelp(JoinKeys(m.OpLog.ViewLatest), "view latest operation")),
 		},
 		InlineDescribe: inlineDescribeModeKeys[key.Binding]{
 			Mode:   key.NewBinding(key.WithKeys(m.InlineDescribe.Mode...), key.WithHelp(JoinKeys(m.InlineDescribe.Mode), "inline describe")),
@@ -127,17 +191,60 @@ func Convert(m KeyMappings[keys]) KeyMappings[key.Binding] {
 			Editor: key.NewBinding(key.WithKeys(m.InlineDescribe.Editor...), key.WithHelp(JoinKeys(m.InlineDescribe.Editor), "open in editor")),
 		},
 		FileSearch: fileSearchKeys[key.Binding]{
-			Toggle: key.NewBinding(key.WithKeys(m.FileSearch.Toggle...), key.WithHelp(JoinKeys(m.FileSearch.Toggle), "fuzzy files search")),
-			Up:     key.NewBinding(key.WithKeys(m.FileSearch.Up...), key.WithHelp(JoinKeys(m.FileSearch.Up), "up")),
-			Down:   key.NewBinding(key.WithKeys(m.FileSearch.Down...), key.WithHelp(JoinKeys(m.FileSearch.Down), "down")),
-			Accept: key.NewBinding(key.WithKeys(m.FileSearch.Accept...), key.WithHelp(JoinKeys(m.FileSearch.Accept), "file revset")),
-			Edit:   key.NewBinding(key.WithKeys(m.FileSearch.Edit...), key.WithHelp(JoinKeys(m.FileSearch.Edit), "edit file")),
+			Toggle:    key.NewBinding(key.WithKeys(m.FileSearch.Toggle...), key.WithHelp(JoinKeys(m.FileSearch.Toggle), "fuzzy files search")),
+			Workspace: key.NewBinding(key.WithKeys(m.FileSearch.Workspace...), key.WithHelp(JoinKeys(m.FileSearch.Workspace), "fuzzy find a working copy file")),
+			Up:        key.NewBinding(key.WithKeys(m.FileSearch.Up...), key.WithHelp(JoinKeys(m.FileSearch.Up), "up")),
+			Down:      key.NewBinding(key.WithKeys(m.FileSearch.Down...), key.WithHelp(JoinKeys(m.FileSearch.Down), "down")),
+			Accept:    key.NewBinding(key.WithKeys(m.FileSearch.Accept...), key.WithHelp(JoinKeys(m.FileSearch.Accept), "file revset")),
+			Edit:      key.NewBinding(key.WithKeys(m.FileSearch.Edit...), key.WithHelp(JoinKeys(m.FileSearch.Edit), "edit file")),
+		},
+		ContentSearch: contentSearchKeys[key.Binding]{
+			Start:       key.NewBinding(key.WithKeys(m.ContentSearch.Start...), key.WithHelp(JoinKeys(m.ContentSearch.Start), "search")),
+			Next:        key.NewBinding(key.WithKeys(m.ContentSearch.Next...), key.WithHelp(JoinKeys(m.ContentSearch.Next), "next match")),
+			Prev:        key.NewBinding(key.WithKeys(m.ContentSearch.Prev...), key.WithHelp(JoinKeys(m.ContentSearch.Prev), "previous match")),
+			ToggleRegex: key.NewBinding(key.WithKeys(m.ContentSearch.ToggleRegex...), key.WithHelp(JoinKeys(m.ContentSearch.ToggleRegex), "toggle regex")),
 		},
 	}
 }
 
 func (c *Config) GetKeyMap() KeyMappings[key.Binding] {
-	return Convert(c.Keys)
+	keyMap := Convert(c.Keys)
+	if c.UI.ReadOnly {
+		bindings := slices.Concat(keyMap.MutatingRevisions(), keyMap.MutatingDetails(), keyMap.MutatingOpLog(), keyMap.MutatingEvolog())
+		for _, binding := range bindings {
+			binding.SetHelp(binding.Help().Key, binding.Help().Desc+" (read-only)")
+		}
+	}
+	return keyMap
+}
+
+// MutatingRevisions returns the bindings of the revisions view that change the repository
+func (k *KeyMappings[T]) MutatingRevisions() []*T {
+	return []*T{
+		&k.New, &k.NewDescribed, &k.NewMerge, &k.Commit, &k.Abandon, &k.Describe, &k.Edit, &k.ForceEdit,
+		&k.Diffedit, &k.OpenShell, &k.Absorb, &k.Split, &k.SplitParallel, &k.Undo, &k.UndoMany, &k.Redo, &k.SetParents,
+		&k.RebaseOntoWorkingCopy, &k.RebaseRevisionOntoWorkingCopy, &k.Rebase.Mode, &k.Revert.Mode, &k.Duplicate.Mode, &k.Squash.Mode, &k.Bookmark.Mode,
+		&k.Bookmark.Create, &k.Bookmark.SetMain, &k.InlineDescribe.Mode, &k.Git.Mode, &k.Git.FetchAll,
+	}
+}
+
+// MutatingDetails returns the bindings of the details view that change the repository
+func (k *KeyMappings[T]) MutatingDetails() []*T {
+	return []*T{
+		&k.Details.Split, &k.Details.SplitParallel, &k.Details.SplitInteractive, &k.Details.SplitSession,
+		&k.Details.Squash, &k.Details.Restore, &k.Details.Absorb, &k.Details.Resolve,
+		&k.Details.RestoreDeleted,
+	}
+}
+
+// MutatingOpLog returns the bindings of the operation log that change the repository
+func (k *KeyMappings[T]) MutatingOpLog() []*T {
+	return []*T{&k.OpLog.Restore, &k.OpLog.Revert}
+}
+
+// MutatingEvolog returns the bindings of the evolog view that change the repository
+func (k *KeyMappings[T]) MutatingEvolog() []*T {
+	return []*T{&k.Evolog.Restore}
 }
 
 func JoinKeys(keys []string) string {
@@ -160,65 +267,103 @@ func JoinKeys(keys []string) string {
 type keys []string
 
 type KeyMappings[T any] struct {
-	Up                T                         `toml:"up"`
-	Down              T                         `toml:"down"`
-	ScrollUp          T                         `toml:"scroll_up"`
-	ScrollDown        T                         `toml:"scroll_down"`
-	JumpToParent      T                         `toml:"jump_to_parent"`
-	JumpToChildren    T                         `toml:"jump_to_children"`
-	JumpToWorkingCopy T                         `toml:"jump_to_working_copy"`
-	Apply             T                         `toml:"apply"`
-	Cancel            T                         `toml:"cancel"`
-	ForceApply        T                         `toml:"force_apply"`
-	ToggleSelect      T                         `toml:"toggle_select"`
-	New               T                         `toml:"new"`
-	Commit            T                         `toml:"commit"`
-	Refresh           T                         `toml:"refresh"`
-	Abandon           T                         `toml:"abandon"`
-	Diff              T                         `toml:"diff"`
-	Quit              T                         `toml:"quit"`
-	Help              T                         `toml:"help"`
-	Describe          T                         `toml:"describe"`
-	Edit              T                         `toml:"edit"`
-	ForceEdit         T                         `toml:"force_edit"`
-	Diffedit          T                         `toml:"diffedit"`
-	Absorb            T                         `toml:"absorb"`
-	Split             T                         `toml:"split"`
-	SplitParallel     T                         `toml:"split_parallel"`
-	Undo              T                         `toml:"undo"`
-	Redo              T                         `toml:"redo"`
-	Revset            T                         `toml:"revset"`
-	ExecJJ            T                         `toml:"exec_jj"`
-	ExecShell         T                         `toml:"exec_shell"`
-	AceJump           T                         `toml:"ace_jump"`
-	QuickSearch       T                         `toml:"quick_search"`
-	QuickSearchCycle  T                         `toml:"quick_search_cycle"`
-	CustomCommands    T                         `toml:"custom_commands"`
-	Leader            T                         `toml:"leader"`
-	Suspend           T                         `toml:"suspend"`
-	SetParents        T                         `toml:"set_parents"`
-	Revert            revertModeKeys[T]         `toml:"revert"`
-	Rebase            rebaseModeKeys[T]         `toml:"rebase"`
-	Duplicate         duplicateModeKeys[T]      `toml:"duplicate"`
-	Squash            squashModeKeys[T]         `toml:"squash"`
-	Details           detailsModeKeys[T]        `toml:"details"`
-	Evolog            evologModeKeys[T]         `toml:"evolog"`
-	Preview           previewModeKeys[T]        `toml:"preview"`
-	Bookmark          bookmarkModeKeys[T]       `toml:"bookmark"`
-	InlineDescribe    inlineDescribeModeKeys[T] `toml:"inline_describe"`
-	Git               gitModeKeys[T]            `toml:"git"`
-	OpLog             opLogModeKeys[T]          `toml:"oplog"`
-	FileSearch        fileSearchKeys[T]         `toml:"file_search"`
+	Up                            T                         `toml:"up"`
+	Down                          T                         `toml:"down"`
+	ScrollUp                      T                         `toml:"scroll_up"`
+	ScrollDown                    T                         `toml:"scroll_down"`
+	JumpToParent                  T                         `toml:"jump_to_parent"`
+	JumpToChildren                T                         `toml:"jump_to_children"`
+	JumpToWorkingCopy             T                         `toml:"jump_to_working_copy"`
+	JumpToTop                     T                         `toml:"jump_to_top"`
+	JumpToBottom                  T                         `toml:"jump_to_bottom"`
+	Apply                         T                         `toml:"apply"`
+	Cancel                        T                         `toml:"cancel"`
+	ForceApply                    T                         `toml:"force_apply"`
+	ToggleSelect                  T                         `toml:"toggle_select"`
+	New                           T                         `toml:"new"`
+	NewDescribed                  T                         `toml:"new_described"`
+	NewMerge                      T                         `toml:"new_merge"`
+	Commit                        T                         `toml:"commit"`
+	Refresh                       T                         `toml:"refresh"`
+	Abandon                       T                         `toml:"abandon"`
+	Diff                          T                         `toml:"diff"`
+	DiffTool                      T                         `toml:"diff_tool"`
+	ExportDiff                    T                         `toml:"export_diff"`
+	ExportGraph                   T                         `toml:"export_graph"`
+	DiffSideBySide                T                         `toml:"diff_side_by_side"`
+	CopyPatch                     T                         `toml:"copy_patch"`
+	Quit                          T                         `toml:"quit"`
+	Help                          T                         `toml:"help"`
+	Describe                      T                         `toml:"describe"`
+	Edit                          T                         `toml:"edit"`
+	ForceEdit                     T                         `toml:"force_edit"`
+	Diffedit                      T                         `toml:"diffedit"`
+	Absorb                        T                         `toml:"absorb"`
+	RebaseOntoWorkingCopy         T                         `toml:"rebase_onto_working_copy"`
+	RebaseRevisionOntoWorkingCopy T                         `toml:"rebase_revision_onto_working_copy"`
+	Split                         T                         `toml:"split"`
+	SplitParallel                 T                         `toml:"split_parallel"`
+	Undo                          T                         `toml:"undo"`
+	UndoMany                      T                         `toml:"undo_many"`
+	Redo                          T                         `toml:"redo"`
+	Revset                        T                         `toml:"revset"`
+	SavedRevsets                  T                         `toml:"saved_revsets"`
+	CopyRevset                    T                         `toml:"copy_revset"`
+	PasteRevset                   T                         `toml:"paste_revset"`
+	ExecJJ                        T                         `toml:"exec_jj"`
+	ExecShell                     T                         `toml:"exec_shell"`
+	OpenShell                     T                         `toml:"open_shell"`
+	AceJump                       T                         `toml:"ace_jump"`
+	GotoChange                    T                         `toml:"goto_change"`
+	QuickSearch                   T                         `toml:"quick_search"`
+	QuickSearchCycle              T                         `toml:"quick_search_cycle"`
+	CustomCommands                T                         `toml:"custom_commands"`
+	RepeatCommand                 T                         `toml:"repeat_command"`
+	Leader                        T                         `toml:"leader"`
+	LeaderTimeoutMs               int                       `toml:"leader_timeout_ms"`
+	Suspend                       T                         `toml:"suspend"`
+	ExpandMessage                 T                         `toml:"expand_message"`
+	SetParents                    T                         `toml:"set_parents"`
+	ToggleTimestamps              T                         `toml:"toggle_timestamps"`
+	ToggleIdType                  T                         `toml:"toggle_id_type"`
+	ToggleHidden                  T                         `toml:"toggle_hidden"`
+	ToggleGraph                   T                         `toml:"toggle_graph"`
+	ToggleDryRun                  T                         `toml:"toggle_dry_run"`
+	ToggleSigning                 T                         `toml:"toggle_signing"`
+	Repositories                  T                         `toml:"repositories"`
+	Workspaces                    T                         `toml:"workspaces"`
+	ResetRevset                   T                         `toml:"reset_revset"`
+	SelectRevset                  T                         `toml:"select_revset"`
+	InvertSelection               T                         `toml:"invert_selection"`
+	ExpandElided                  T                         `toml:"expand_elided"`
+	CollapseElided                T                         `toml:"collapse_elided"`
+	Revert                        revertModeKeys[T]         `toml:"revert"`
+	Rebase                        rebaseModeKeys[T]         `toml:"rebase"`
+	Duplicate                     duplicateModeKeys[T]      `toml:"duplicate"`
+	Reorder                       reorderKeys[T]            `toml:"reorder"`
+	Squash                        squashModeKeys[T]         `toml:"squash"`
+	Details                       detailsModeKeys[T]        `toml:"details"`
+	Evolog                        evologModeKeys[T]         `toml:"evolog"`
+	Preview                       previewModeKeys[T]        `toml:"preview"`
+	Bookmark                      bookmarkModeKeys[T]       `toml:"bookmark"`
+	InlineDescribe                inlineDescribeModeKeys[T] `toml:"inline_describe"`
+	Git                           gitModeKeys[T]            `toml:"git"`
+	OpLog                         opLogModeKeys[T]          `toml:"oplog"`
+	FileSearch                    fileSearchKeys[T]         `toml:"file_search"`
+	ContentSearch                 contentSearchKeys[T]      `toml:"content_search"`
 }
 
 type bookmarkModeKeys[T any] struct {
 	Mode    T `toml:"mode"`
 	Set     T `toml:"set"`
+	Create  T `toml:"create"`
 	Delete  T `toml:"delete"`
 	Move    T `toml:"move"`
 	Forget  T `toml:"forget"`
 	Track   T `toml:"track"`
 	Untrack T `toml:"untrack"`
+	Rename  T `toml:"rename"`
+	SetMain T `toml:"set_main"`
 }
 
 type squashModeKeys[T any] struct {
@@ -246,13 +391,21 @@ type rebaseModeKeys[T any] struct {
 	Onto        T `toml:"onto"`
 	Insert      T `toml:"insert"`
 	SkipEmptied T `toml:"skip_emptied"`
+	Reorder     T `toml:"reorder"`
 }
 
 type duplicateModeKeys[T any] struct {
-	Mode   T `toml:"mode"`
-	After  T `toml:"after"`
-	Before T `toml:"before"`
-	Onto   T `toml:"onto"`
+	Mode    T `toml:"mode"`
+	After   T `toml:"after"`
+	Before  T `toml:"before"`
+	Onto    T `toml:"onto"`
+	Choose  T `toml:"choose"`
+	Reorder T `toml:"reorder"`
+}
+
+type reorderKeys[T any] struct {
+	MoveUp   T `toml:"move_up"`
+	MoveDown T `toml:"move_down"`
 }
 
 type evologModeKeys[T any] struct {
@@ -266,18 +419,30 @@ type detailsModeKeys[T any] struct {
 	Close                 T `toml:"close"`
 	Split                 T `toml:"split"`
 	SplitParallel         T `toml:"split_parallel"`
+	SplitInteractive      T `toml:"split_interactive"`
 	Restore               T `toml:"restore"`
 	Absorb                T `toml:"absorb"`
 	Squash                T `toml:"squash"`
 	Diff                  T `toml:"diff"`
 	ToggleSelect          T `toml:"select"`
 	RevisionsChangingFile T `toml:"revisions_changing_file"`
+	OpenInEditor          T `toml:"open_in_editor"`
+	NextConflict          T `toml:"next_conflict"`
+	PrevConflict          T `toml:"prev_conflict"`
+	Resolve               T `toml:"resolve"`
+	RestoreDeleted        T `toml:"restore_deleted"`
+	Follow                T `toml:"follow"`
+	SplitSession          T `toml:"split_session"`
+	NextRevision          T `toml:"next_revision"`
+	PrevRevision          T `toml:"prev_revision"`
 }
 
 type gitModeKeys[T any] struct {
-	Mode  T `toml:"mode"`
-	Push  T `toml:"push"`
-	Fetch T `toml:"fetch"`
+	Mode     T `toml:"mode"`
+	Push     T `toml:"push"`
+	Fetch    T `toml:"fetch"`
+	FetchAll T `toml:"fetch_all"`
+	Remote   T `toml:"remote"`
 }
 
 type previewModeKeys[T any] struct {
@@ -289,12 +454,20 @@ type previewModeKeys[T any] struct {
 	HalfPageUp   T `toml:"half_page_up"`
 	Expand       T `toml:"expand"`
 	Shrink       T `toml:"shrink"`
+	Search       T `toml:"search"`
+	SearchNext   T `toml:"search_next"`
+	SearchPrev   T `toml:"search_prev"`
+	Annotate     T `toml:"annotate"`
 }
 
 type opLogModeKeys[T any] struct {
-	Mode    T `toml:"mode"`
-	Restore T `toml:"restore"`
-	Revert  T `toml:"revert"`
+	Mode       T `toml:"mode"`
+	Restore    T `toml:"restore"`
+	Revert     T `toml:"revert"`
+	Jump       T `toml:"jump"`
+	LoadMore   T `toml:"load_more"`
+	ViewAt     T `toml:"view_at"`
+	ViewLatest T `toml:"view_latest"`
 }
 
 type inlineDescribeModeKeys[T any] struct {
@@ -304,9 +477,17 @@ type inlineDescribeModeKeys[T any] struct {
 }
 
 type fileSearchKeys[T any] struct {
-	Toggle T `toml:"toggle"`
-	Up     T `toml:"up"`
-	Down   T `toml:"down"`
-	Accept T `toml:"accept"`
-	Edit   T `toml:"edit"`
+	Toggle    T `toml:"toggle"`
+	Workspace T `toml:"workspace"`
+	Up        T `toml:"up"`
+	Down      T `toml:"down"`
+	Accept    T `toml:"accept"`
+	Edit      T `toml:"edit"`
+}
+
+type contentSearchKeys[T any] struct {
+	Start       T `toml:"start"`
+	Next        T `toml:"next"`
+	Prev        T `toml:"prev"`
+	ToggleRegex T `toml:"toggle_regex"`
 }
@@ -70,6 +70,33 @@ func (c *Config) Load(data string) error {
 		return err
 	}
 
+	if _, err = c.Revisions.ColumnsTemplate(); err != nil {
+		return err
+	}
+	if _, err = GetIdType(c); err != nil {
+		return err
+	}
+	if _, err = GetGraphStyle(c); err != nil {
+		return err
+	}
+	if _, _, err = GetElision(c); err != nil {
+		return err
+	}
+	if _, err = GetLogLevel(c); err != nil {
+		return err
+	}
+	if err = c.UI.StatusBar.Validate(); err != nil {
+		return err
+	}
+	if _, err = GetConfirmDefaultNo(c); err != nil {
+		return err
+	}
+	if _, err = GetClipboardMode(c); err != nil {
+		return err
+	}
+	if err = c.PostCommand.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -117,3 +144,24 @@ func LoadTheme(name string, base map[string]Color) (map[string]Color, error) {
 	}
 	return loadTheme(data, base)
 }
+
+// ResolveTheme returns the embedded default theme for a dark or a light background with the
+// user's theme for the same background, ui.theme.dark or ui.theme.light, applied on top of it
+func ResolveTheme(dark bool) (map[string]Color, error) {
+	defaultName, userName := "default_light", Current.UI.Theme.Light
+	if dark {
+		defaultName, userName = "default_dark", Current.UI.Theme.Dark
+	}
+	theme, err := LoadEmbeddedTheme(defaultName)
+	if err != nil {
+		return nil, fmt.Errorf("loading default theme '%s': %w", defaultName, err)
+	}
+	if userName == "" {
+		return theme, nil
+	}
+	theme, err = LoadTheme(userName, theme)
+	if err != nil {
+		return nil, fmt.Errorf("loading user theme '%s': %w", userName, err)
+	}
+	return theme, nil
+}
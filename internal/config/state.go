@@ -0,0 +1,110 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/BurntSushi/toml"
+)
+
+// State keeps the values adjusted while jjui is running, so that the next
+// launch starts where the previous one left off.
+type State struct {
+	PreviewWidthPercentage float64  `toml:"preview_width_percentage"`
+	RecentRepositories     []string `toml:"recent_repositories"`
+	// AbsoluteTimestamps and GraphStyle are toggled in the UI, they override ui.absolute_timestamps
+	// and revisions.graph_style of the config file once set
+	AbsoluteTimestamps *bool  `toml:"absolute_timestamps,omitempty"`
+	GraphStyle         string `toml:"graph_style,omitempty"`
+
+	changed bool
+}
+
+// LoadState reads the state file, starting from an empty state when it does
+// not exist or cannot be read.
+func LoadState() *State {
+	state := &State{}
+	data, err := os.ReadFile(stateFile())
+	if err != nil {
+		return state
+	}
+	if _, err := toml.Decode(string(data), state); err != nil {
+		return &State{}
+	}
+	return state
+}
+
+func (s *State) SetPreviewWidthPercentage(percentage float64) {
+	if s.PreviewWidthPercentage == percentage {
+		return
+	}
+	s.PreviewWidthPercentage = percentage
+	s.changed = true
+}
+
+func (s *State) SetAbsoluteTimestamps(absolute bool) {
+	if s.AbsoluteTimestamps != nil && *s.AbsoluteTimestamps == absolute {
+		return
+	}
+	s.AbsoluteTimestamps = &absolute
+	s.changed = true
+}
+
+func (s *State) SetGraphStyle(style string) {
+	if s.GraphStyle == style {
+		return
+	}
+	s.GraphStyle = style
+	s.changed = true
+}
+
+const maxRecentRepositories = 10
+
+// AddRecentRepository moves the location to the top of the recently opened repositories
+func (s *State) AddRecentRepository(location string) {
+	if len(s.RecentRepositories) > 0 && s.RecentRepositories[0] == location {
+		return
+	}
+	s.RemoveRecentRepository(location)
+	s.RecentRepositories = slices.Insert(s.RecentRepositories, 0, location)
+	if len(s.RecentRepositories) > maxRecentRepositories {
+		s.RecentRepositories = s.RecentRepositories[:maxRecentRepositories]
+	}
+	s.changed = true
+}
+
+func (s *State) RemoveRecentRepository(location string) {
+	if index := slices.Index(s.RecentRepositories, location); index >= 0 {
+		s.RecentRepositories = slices.Delete(s.RecentRepositories, index, index+1)
+		s.changed = true
+	}
+}
+
+// Save writes the state to the state file in the user cache directory, it does nothing
+// when nothing changed since it was loaded.
+func (s *State) Save() error {
+	if !s.changed {
+		return nil
+	}
+	file := stateFile()
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(s); err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
+
+func stateFile() string {
+	var cacheDir string
+	if dir, err := os.UserCacheDir(); err == nil {
+		cacheDir = dir
+	} else {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "jjui", "state.toml")
+}
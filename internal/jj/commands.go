@@ -2,6 +2,7 @@ package jj
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -43,25 +44,66 @@ func Log(revset string, limit int, jjTemplate string) CommandArgs {
 	if limit > 0 {
 		args = append(args, "--limit", strconv.Itoa(limit))
 	}
-	template := config.Current.Revisions.Template
-	// If jjui's template is empty, fall back to jj's templates.log
-	if template == "" {
-		template = jjTemplate
-	}
 	prefix := fmt.Sprintf(
 		"stringify('%s' ++ separate('%s', change_id.shortest(), commit_id.shortest(), divergent))",
 		JJUIPrefix, JJUIPrefix)
-	template = fmt.Sprintf("%s ++ ' ' ++ %s", prefix, template)
+	template := fmt.Sprintf("%s ++ ' ' ++ %s", prefix, LogTemplate(jjTemplate))
 	args = append(args, "-T", template)
+	if style, _ := config.GetGraphStyle(config.Current); style == config.GraphStyleFlat {
+		args = append(args, "--no-graph")
+	}
+	args = append(args, timestampArgs()...)
 	return args
 }
 
+// LogTemplate returns the template the revisions are rendered with
+func LogTemplate(jjTemplate string) string {
+	template := config.Current.Revisions.GetLogTemplate()
+	// If jjui's template is empty, fall back to jj's templates.log
+	if template == "" {
+		template = jjTemplate
+	}
+	return template
+}
+
+// PrintLog renders the revisions for printing outside of the UI, colours are left to jj
+// so that they are only used when the output goes to a terminal
+func PrintLog(revset string, limit int, template string, noGraph bool) CommandArgs {
+	args := []string{"log", "--quiet", "-r", revset, "-T", template}
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	if noGraph {
+		args = append(args, "--no-graph")
+	}
+	args = append(args, timestampArgs()...)
+	return args
+}
+
+// timestampArgs overrides jj's timestamp aliases when absolute timestamps are enabled
+func timestampArgs() []string {
+	if !config.Current.UI.AbsoluteTimestamps {
+		return nil
+	}
+	return []string{
+		"--config", `template-aliases."format_timestamp(timestamp)"='timestamp.local().format("%Y-%m-%d %H:%M:%S")'`,
+		"--config", `template-aliases."format_time_range(time_range)"='time_range.start().local().format("%Y-%m-%d %H:%M:%S") ++ ", lasted " ++ time_range.duration()'`,
+	}
+}
+
 func New(revisions SelectedRevisions) CommandArgs {
 	args := []string{"new"}
 	args = append(args, revisions.AsArgs()...)
 	return args
 }
 
+// NewMerge creates a revision on top of all the given parents
+func NewMerge(parents ...string) CommandArgs {
+	args := []string{"new"}
+	args = append(args, parents...)
+	return args
+}
+
 func CommitWorkingCopy() CommandArgs {
 	return []string{"commit"}
 }
@@ -91,6 +133,20 @@ func Split(revision string, files []string, parallel bool) CommandArgs {
 	return args
 }
 
+// SplitWithMessage splits the files out of the revision without opening an editor, the files keep
+// the revision and its description while the rest moves to a new child revision
+func SplitWithMessage(revision string, files []string, message string) CommandArgs {
+	args := []string{"split", "-r", revision, "-m", message}
+	for _, file := range files {
+		args = append(args, EscapeFileName(file))
+	}
+	return args
+}
+
+func SplitInteractive(revision string) CommandArgs {
+	return []string{"split", "-r", revision, "--interactive"}
+}
+
 func SquashFiles(from string, into string, files []string) CommandArgs {
 	args := []string{"squash", "--from", from, "--into", into, "--use-destination-message"}
 	var escapedFiles []string
@@ -115,6 +171,10 @@ func GetDescription(revision string) CommandArgs {
 	return []string{"log", "-r", revision, "--template", "description", "--no-graph", "--ignore-working-copy", "--color", "never", "--quiet"}
 }
 
+func IsImmutable(revision string) CommandArgs {
+	return []string{"log", "-r", revision, "--template", "immutable", "--no-graph", "--ignore-working-copy", "--color", "never", "--quiet"}
+}
+
 func Abandon(revision SelectedRevisions, ignoreImmutable bool) CommandArgs {
 	args := []string{"abandon", "--retain-bookmarks"}
 	args = append(args, revision.AsArgs()...)
@@ -145,6 +205,16 @@ func Restore(revision string, files []string) CommandArgs {
 	return args
 }
 
+// Resolve runs the merge tool on the conflicted files of the revision, every conflicted file
+// is resolved when no file is given
+func Resolve(revision string, files []string) CommandArgs {
+	args := []string{"resolve", "-r", revision}
+	for _, file := range files {
+		args = append(args, EscapeFileName(file))
+	}
+	return args
+}
+
 func RestoreInteractive(revision string, file string) CommandArgs {
 	args := []string{"restore", "-c", revision, "--interactive"}
 	if file != "" {
@@ -153,6 +223,11 @@ func RestoreInteractive(revision string, file string) CommandArgs {
 	return args
 }
 
+// RestoreFromParent brings back the file from the given parent of the revision
+func RestoreFromParent(revision string, parent string, file string) CommandArgs {
+	return []string{"restore", "--from", parent, "--into", revision, EscapeFileName(file)}
+}
+
 func RestoreEvolog(from string, into string) CommandArgs {
 	args := []string{"restore", "--from", from, "--into", into, "--restore-descendants"}
 	return args
@@ -170,6 +245,10 @@ func Snapshot() CommandArgs {
 	return []string{"debug", "snapshot"}
 }
 
+func DiffStat(revision string) CommandArgs {
+	return []string{"diff", "--stat", "-r", revision, "--color", "never", "--quiet", "--ignore-working-copy"}
+}
+
 func Status(revision string) CommandArgs {
 	template := `separate(";", diff.files().map(|x| x.target().conflict())) ++ " $\n"`
 	return []string{"log", "-r", revision, "--summary", "--no-graph", "--color", "never", "--quiet", "--template", template, "--ignore-working-copy"}
@@ -179,6 +258,10 @@ func BookmarkSet(revision string, name string) CommandArgs {
 	return []string{"bookmark", "set", "-r", revision, name}
 }
 
+func BookmarkCreate(name string, revision string) CommandArgs {
+	return []string{"bookmark", "create", "-r", revision, name}
+}
+
 func BookmarkMove(revision string, bookmark string, extraFlags ...string) CommandArgs {
 	args := []string{"bookmark", "move", bookmark, "--to", revision}
 	if extraFlags != nil {
@@ -187,8 +270,12 @@ func BookmarkMove(revision string, bookmark string, extraFlags ...string) Comman
 	return args
 }
 
-func BookmarkDelete(name string) CommandArgs {
-	return []string{"bookmark", "delete", name}
+func BookmarkDelete(names ...string) CommandArgs {
+	return append([]string{"bookmark", "delete"}, names...)
+}
+
+func BookmarkRename(oldName string, newName string) CommandArgs {
+	return []string{"bookmark", "rename", oldName, newName}
 }
 
 func BookmarkForget(name string) CommandArgs {
@@ -234,6 +321,19 @@ func BookmarkList(revset string) CommandArgs {
 	return []string{"bookmark", "list", "-a", "-r", revset, "--template", template, "--color", "never", "--ignore-working-copy"}
 }
 
+// NearestBookmarks lists the bookmarks of the closest ancestors of the working copy that have one
+func NearestBookmarks() CommandArgs {
+	return BookmarkList("heads(::@ & bookmarks())")
+}
+
+// AheadBehind prints a "+" for each revision the working copy is ahead of the remote bookmark
+// and a "-" for each revision it is behind
+func AheadBehind(bookmark string, remote string) CommandArgs {
+	target := fmt.Sprintf("remote_bookmarks(exact:%q, exact:%q)", bookmark, remote)
+	revset := fmt.Sprintf("(%s..@) | (@..%s)", target, target)
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", `if(self.contained_in("::@"), "+", "-") ++ "\n"`}
+}
+
 func BookmarkListMovable(revision string) CommandArgs {
 	revsetBefore := fmt.Sprintf("::%s", revision)
 	revsetAfter := fmt.Sprintf("%s::", revision)
@@ -266,6 +366,25 @@ func GitRemoteList() CommandArgs {
 	return []string{"git", "remote", "list"}
 }
 
+// SelectionSummary prints the revision with the given template, the short change id, the author and
+// the first line of the description are printed when the template is empty
+func SelectionSummary(revision string, template string) CommandArgs {
+	if template == "" {
+		template = `separate(" ", change_id.shortest(8), author.name(), if(description, description.first_line(), "(no description set)"))`
+	}
+	return []string{"log", "-r", revision, "--no-graph", "--color", "never", "--quiet", "--ignore-working-copy", "--template", template}
+}
+
+// RevisionHeader renders the revision with the given template to be shown above its preview
+func RevisionHeader(revision string, template string) CommandArgs {
+	return []string{"log", "-r", revision, "--no-graph", "--color", "always", "--quiet", "--ignore-working-copy", "--template", template}
+}
+
+// DiffPatch returns the changes of a revision as a git style patch that can be applied elsewhere
+func DiffPatch(changeId string) CommandArgs {
+	return []string{"diff", "--git", "-r", changeId, "--color", "never", "--ignore-working-copy"}
+}
+
 func Show(revision string, extraArgs ...string) CommandArgs {
 	args := []string{"show", "-r", revision, "--color", "always", "--ignore-working-copy"}
 	if extraArgs != nil {
@@ -332,10 +451,13 @@ func RevertInsert(from SelectedRevisions, insertAfter string, insertBefore strin
 	return args
 }
 
+// Duplicate duplicates the revisions relative to the target, an empty target keeps the original parents
 func Duplicate(from SelectedRevisions, to string, target string) CommandArgs {
 	args := []string{"duplicate"}
 	args = append(args, from.AsPrefixedArgs("-r")...)
-	args = append(args, target, to)
+	if to != "" {
+		args = append(args, target, to)
+	}
 	return args
 }
 
@@ -352,6 +474,35 @@ func Args(args ...string) CommandArgs {
 	return args
 }
 
+// WithGlobalArgs prepends the arguments configured under jj.global_args, which
+// are passed to every jj invocation
+func WithGlobalArgs(args []string) []string {
+	globalArgs := config.Current.JJ.GlobalArgs
+	if len(globalArgs) == 0 {
+		return args
+	}
+	return append(slices.Clone(globalArgs), args...)
+}
+
+// WithAtOperation makes jj load the repository as it was at the given operation,
+// the latest operation is loaded when the id is empty. jj ignores the working copy when
+// loading a past operation so nothing is snapshotted.
+func WithAtOperation(operationId string, args []string) []string {
+	if operationId == "" {
+		return args
+	}
+	return append([]string{"--at-operation", operationId}, args...)
+}
+
+// WithSigning makes jj sign the commits the command creates or rewrites, overriding the
+// signing.behavior configured for the repository
+func WithSigning(sign bool, args []string) []string {
+	if !sign {
+		return args
+	}
+	return append([]string{"--config", "signing.behavior=force"}, args...)
+}
+
 func TemplatedArgs(templatedArgs []string, replacements map[string]string) CommandArgs {
 	var args []string
 	if fileReplacement, exists := replacements[FilePlaceholder]; exists {
@@ -385,11 +536,17 @@ func OpLogId(snapshot bool) CommandArgs {
 	return args
 }
 
+// OpLogIds lists the ids of the latest operations, one per line
+func OpLogIds(limit int) CommandArgs {
+	return []string{"op", "log", "--color", "never", "--quiet", "--no-graph", "--ignore-working-copy", "--limit", strconv.Itoa(limit), "--template", `id ++ "\n"`}
+}
+
 func OpLog(limit int) CommandArgs {
 	args := []string{"op", "log", "--color", "always", "--quiet", "--ignore-working-copy"}
 	if limit > 0 {
 		args = append(args, "--limit", strconv.Itoa(limit))
 	}
+	args = append(args, timestampArgs()...)
 	return args
 }
 
@@ -419,6 +576,16 @@ func GetParents(revision string) CommandArgs {
 	return args
 }
 
+// GetCommitIdsFromRevset prints the commit ids of the revset one per line the same way the revisions are identified in the log
+func GetCommitIdsFromRevset(revset string) CommandArgs {
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "commit_id.shortest() ++ '\n'"}
+}
+
+// GetTrunk prints the commit id of trunk() the same way the revisions are identified in the log
+func GetTrunk() CommandArgs {
+	return []string{"log", "-r", "trunk()", "-n", "1", "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "commit_id.shortest()"}
+}
+
 func GetFirstChild(revision *Commit) CommandArgs {
 	args := []string{"log", "-r"}
 	args = append(args, fmt.Sprintf("%s+", revision.CommitId))
@@ -435,10 +602,51 @@ func FilesInRevision(revision *Commit) CommandArgs {
 	return args
 }
 
+// ValidateRevset evaluates the revset without printing anything, jj fails when the revset is invalid
+func ValidateRevset(revset string) CommandArgs {
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--limit", "1", "--template", "''"}
+}
+
+// CountRevset prints a character for each revision in the revset, up to limit revisions. It
+// isn't a newline since the output of the commands is trimmed of them.
+func CountRevset(revset string, limit int) CommandArgs {
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--limit", strconv.Itoa(limit), "--template", "'x'"}
+}
+
+// GetRevisionIds prints the id each revision of the log is checked with and its commit id, a
+// revision per line. As in the log, the hidden and divergent revisions are checked with their commit id.
+func GetRevisionIds(revset string, limit int) CommandArgs {
+	args := []string{"log", "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy"}
+	if revset != "" {
+		args = append(args, "-r", revset)
+	}
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	return append(args, "--template", "if(hidden || divergent, commit_id.shortest(), change_id.shortest()) ++ ' ' ++ commit_id.shortest() ++ '\n'")
+}
+
 func GetIdsFromRevset(revset string) CommandArgs {
 	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", "change_id.shortest() ++ '\n'"}
 }
 
+// WorkspaceList lists the names of the workspaces of the repository one per line
+func WorkspaceList() CommandArgs {
+	return []string{"workspace", "list", "--color", "never", "--ignore-working-copy", "--template", `name ++ "\n"`}
+}
+
+// WorkspaceRoot prints the directory the workspace with the given name is checked out in
+func WorkspaceRoot(name string) CommandArgs {
+	return []string{"workspace", "root", "--name", name, "--color", "never", "--ignore-working-copy"}
+}
+
+// GraphEdges lists the revisions of the revset one per line as the commit id, the commit ids of
+// the parents and the change id followed by the first line of the description, separated by tabs
+func GraphEdges(revset string) CommandArgs {
+	template := `commit_id.short() ++ "\t" ++ parents.map(|p| p.commit_id().short()).join(" ") ++ "\t" ++ change_id.shortest(8) ++ " " ++ description.first_line() ++ "\n"`
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", template}
+}
+
 func EscapeFileName(fileName string) string {
 	// Escape backslashes and quotes in the file name for shell compatibility
 	if strings.Contains(fileName, "\\") {
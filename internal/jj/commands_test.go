@@ -0,0 +1,65 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithGlobalArgs(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+
+	args := TemplatedArgs([]string{"diff", "-r", ChangeIdPlaceholder}, map[string]string{ChangeIdPlaceholder: "abc"})
+	assert.Equal(t, []string{"diff", "-r", "abc"}, WithGlobalArgs(args))
+
+	config.Current.JJ.GlobalArgs = []string{"--ignore-working-copy"}
+	assert.Equal(t, []string{"--ignore-working-copy", "diff", "-r", "abc"}, WithGlobalArgs(args))
+	assert.Equal(t, []string{"--ignore-working-copy"}, config.Current.JJ.GlobalArgs)
+}
+
+func TestWithSigning(t *testing.T) {
+	assert.Equal(t, []string{"describe", "-r", "@"}, WithSigning(false, []string{"describe", "-r", "@"}))
+	assert.Equal(t, []string{"--config", "signing.behavior=force", "describe", "-r", "@"}, WithSigning(true, []string{"describe", "-r", "@"}))
+}
+
+func TestWithAtOperation(t *testing.T) {
+	assert.Equal(t, []string{"log", "-r", "@"}, WithAtOperation("", []string{"log", "-r", "@"}))
+	assert.Equal(t, []string{"--at-operation", "abc123", "log", "-r", "@"}, WithAtOperation("abc123", []string{"log", "-r", "@"}))
+}
+
+func TestPrintLog(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+
+	config.Current.Revisions.Template = "description"
+	assert.Equal(t, "description", LogTemplate("builtin_log_compact"))
+	config.Current.Revisions.Template = ""
+	config.Current.Revisions.Columns = nil
+	assert.Equal(t, "builtin_log_compact", LogTemplate("builtin_log_compact"))
+
+	assert.Equal(t, CommandArgs{"log", "--quiet", "-r", "@", "-T", "description", "--limit", "5", "--no-graph"}, PrintLog("@", 5, "description", true))
+	assert.Equal(t, CommandArgs{"log", "--quiet", "-r", "::@", "-T", "description"}, PrintLog("::@", 0, "description", false))
+}
+
+func TestLog_FlatGraphStyle(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+
+	config.Current.Revisions.GraphStyle = "graph"
+	assert.NotContains(t, Log("@", 0, "description"), "--no-graph")
+	config.Current.Revisions.GraphStyle = "flat"
+	assert.Contains(t, Log("@", 0, "description"), "--no-graph")
+}
+
+func TestOpLogIds_IgnoresWorkingCopy(t *testing.T) {
+	// a snapshot before the listing would add the operation undo is counted from
+	assert.Equal(t, CommandArgs{"op", "log", "--color", "never", "--quiet", "--no-graph", "--ignore-working-copy", "--limit", "3", "--template", `id ++ "\n"`}, OpLogIds(3))
+}
@@ -0,0 +1,21 @@
+package jj
+
+import (
+	"regexp"
+	"strings"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// ParseDuplicateOutput returns the change ids of the new revisions from the output of `jj duplicate`
+// which reports each of them as `Duplicated <commit id> as <change id> <commit id> <description>`
+func ParseDuplicateOutput(output string) []string {
+	var changeIds []string
+	for line := range strings.SplitSeq(ansiEscape.ReplaceAllString(output, ""), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 4 && fields[0] == "Duplicated" && fields[2] == "as" {
+			changeIds = append(changeIds, fields[3])
+		}
+	}
+	return changeIds
+}
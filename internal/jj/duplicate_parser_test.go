@@ -0,0 +1,36 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuplicateOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{
+			name:     "single revision",
+			output:   "Duplicated 8d4f2a1c as kmtyuwvp 3e1b0f7a add readme\n",
+			expected: []string{"kmtyuwvp"},
+		},
+		{
+			name:     "multiple revisions with colors",
+			output:   "Duplicated \x1b[1m\x1b[38;5;4m8d\x1b[0m4f2a1c as \x1b[1m\x1b[38;5;5mkm\x1b[0mtyuwvp 3e1b0f7a first\nDuplicated 1a2b3c4d as xqrlpsvn 9f8e7d6c second\n",
+			expected: []string{"kmtyuwvp", "xqrlpsvn"},
+		},
+		{
+			name:     "unrelated output",
+			output:   "Nothing changed.\n",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseDuplicateOutput(tt.output))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package jj
+
+import (
+	"fmt"
+	"strings"
+)
+
+// elideKept are the revisions of a revset that are never elided: the ends of its chains and the
+// revisions that stand out in them
+const elideKept = "(%[1]s) & (heads(%[1]s) | roots(%[1]s) | merges() | bookmarks() | remote_bookmarks() | tags() | working_copies() | conflicts())"
+
+// ElideRevset narrows the revset down so that runs of intermediate revisions are left out and
+// shown by jj as elided. The first keep revisions of each run stay, the rest of the runs below
+// the expanded revisions are shown again.
+func ElideRevset(revset string, keep int, expanded []string) string {
+	kept := fmt.Sprintf(elideKept, revset)
+	shown := []string{kept}
+	if keep > 0 {
+		shown = []string{fmt.Sprintf("ancestors(%s, %d)", kept, keep+1)}
+	}
+	for _, revision := range expanded {
+		shown = append(shown, fmt.Sprintf("heads(::%[1]s- & %[2]s)..%[1]s", revision, kept))
+	}
+	return fmt.Sprintf("(%s) & (%s)", revset, strings.Join(shown, " | "))
+}
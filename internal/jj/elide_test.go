@@ -0,0 +1,37 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElideRevset(t *testing.T) {
+	kept := "(mine()) & (heads(mine()) | roots(mine()) | merges() | bookmarks() | remote_bookmarks() | tags() | working_copies() | conflicts())"
+	tests := []struct {
+		name     string
+		keep     int
+		expanded []string
+		expected string
+	}{
+		{
+			name:     "always",
+			expected: "(mine()) & (" + kept + ")",
+		},
+		{
+			name:     "beyond",
+			keep:     3,
+			expected: "(mine()) & (ancestors(" + kept + ", 4))",
+		},
+		{
+			name:     "expanded",
+			expanded: []string{"abc"},
+			expected: "(mine()) & (" + kept + " | heads(::abc- & " + kept + ")..abc)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ElideRevset("mine()", tt.keep, tt.expanded))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package jj
+
+import "strings"
+
+// globalFlagsWithValue are the global flags that take the following argument as their value
+var globalFlagsWithValue = map[string]bool{
+	"-R":             true,
+	"--repository":   true,
+	"--config":       true,
+	"--config-toml":  true,
+	"--config-file":  true,
+	"--at-operation": true,
+	"--at-op":        true,
+	"--color":        true,
+}
+
+// readOnlyCommands are the commands that only read the repository, the groups list the
+// subcommands that read it. The rest of the commands are taken as changing the repository.
+var readOnlyCommands = map[string][]string{
+	"log":       nil,
+	"show":      nil,
+	"diff":      nil,
+	"interdiff": nil,
+	"status":    nil,
+	"st":        nil,
+	"evolog":    nil,
+	"obslog":    nil,
+	"help":      nil,
+	"version":   nil,
+	"root":      nil,
+	"bookmark":  {"list", "l"},
+	"b":         {"list", "l"},
+	"tag":       {"list", "l"},
+	"operation": {"log", "show", "diff"},
+	"op":        {"log", "show", "diff"},
+	"workspace": {"list", "root"},
+	"file":      {"list", "show", "annotate", "search"},
+	"config":    {"get", "list", "path"},
+	"sparse":    {"list"},
+	"git":       {"remote list", "root"},
+}
+
+// IsMutating tells whether the command may change the repository, the commands that can't be
+// recognised as only reading it are taken as changing it
+func IsMutating(args []string) bool {
+	var words []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			if globalFlagsWithValue[arg] {
+				i++
+			}
+			continue
+		}
+		words = append(words, arg)
+		if len(words) == 3 {
+			break
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+	subcommands, ok := readOnlyCommands[words[0]]
+	if !ok {
+		return true
+	}
+	if subcommands == nil {
+		return false
+	}
+	rest := strings.Join(words[1:], " ")
+	for _, subcommand := range subcommands {
+		if rest == subcommand || strings.HasPrefix(rest, subcommand+" ") {
+			return false
+		}
+	}
+	return true
+}
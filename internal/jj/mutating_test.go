@@ -0,0 +1,34 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMutating(t *testing.T) {
+	for _, args := range [][]string{
+		Log("::@", 0, ""),
+		{"show", "-r", "abc"},
+		{"--config", "ui.color=never", "log"},
+		{"bookmark", "list", "-r", "abc"},
+		{"op", "log", "--limit", "5"},
+		{"git", "remote", "list"},
+		{"file", "show", "-r", "@", "a.txt"},
+		{},
+	} {
+		assert.False(t, IsMutating(args), args)
+	}
+	for _, args := range [][]string{
+		{"rebase", "-r", "a", "-d", "b"},
+		{"describe", "-r", "@", "-m", "log"},
+		{"bookmark", "set", "-r", "a", "list"},
+		{"op", "restore", "abc"},
+		{"git", "fetch"},
+		{"git", "remote", "add", "origin", "url"},
+		{"--config", "signing.behavior=force", "new"},
+		{"my-alias"},
+	} {
+		assert.True(t, IsMutating(args), args)
+	}
+}
@@ -50,3 +50,21 @@ func TestParseRowsStreaming_Close(t *testing.T) {
 	_, received := <-receiver
 	assert.False(t, received, "expected channel to be closed")
 }
+
+func TestParseRowsStreaming_WithoutGraph(t *testing.T) {
+	var lb test.LogBuilder
+	lb.Write("_PREFIX:abcde_PREFIX:xyrq_PREFIX:false abcde some@author")
+	lb.Write("first commit")
+	lb.Write("_PREFIX:fghij_PREFIX:klmn_PREFIX:false fghij some@author")
+	lb.Write("second commit\n")
+
+	controlChannel := make(chan ControlMsg)
+	receiver, err := ParseRowsStreaming(strings.NewReader(lb.String()), controlChannel, 50)
+	assert.NoError(t, err)
+	controlChannel <- RequestMore
+	batch := <-receiver
+	assert.Len(t, batch.Rows, 2)
+	assert.Equal(t, "fghij", batch.Rows[1].Commit.ChangeId)
+	assert.Equal(t, 0, batch.Rows[1].Indent)
+	assert.Len(t, batch.Rows[1].Lines, 2)
+}
@@ -4,9 +4,10 @@ import (
 	stdcontext "context"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/clipboard"
 	"github.com/idursun/jjui/internal/ui/input"
 	"github.com/idursun/jjui/internal/ui/intents"
 	lua "github.com/yuin/gopher-lua"
@@ -26,6 +27,7 @@ type step struct {
 
 type Runner struct {
 	ctx        *uicontext.MainContext
+	script     string
 	main       *lua.LState
 	thread     *lua.LState
 	cancel     stdcontext.CancelFunc
@@ -34,11 +36,22 @@ type Runner struct {
 	await      func(tea.Msg) (bool, []lua.LValue)
 	resumeArgs []lua.LValue
 	done       bool
+	// callbacks registered with `jjui.on_refresh`, they keep the lua state alive after the script finishes
+	refreshHooks []*lua.LFunction
+	// callbacks scheduled with `jjui.after` that haven't run yet, the script isn't done until they have
+	timers  map[int]*lua.LFunction
+	timerId int
+}
+
+// timerMsg is sent when the callback scheduled with `jjui.after` is due
+type timerMsg struct {
+	runner *Runner
+	id     int
 }
 
 func RunScript(ctx *uicontext.MainContext, src string) (*Runner, tea.Cmd, error) {
 	L := lua.NewState()
-	r := &Runner{ctx: ctx, main: L}
+	r := &Runner{ctx: ctx, main: L, script: src}
 
 	registerAPI(L, r)
 
@@ -51,10 +64,15 @@ func RunScript(ctx *uicontext.MainContext, src string) (*Runner, tea.Cmd, error)
 	r.thread, r.cancel = L.NewThread()
 
 	cmd := r.resume()
-	if r.done {
+	r.closeIfFinished()
+	return r, cmd, nil
+}
+
+// closeIfFinished releases the lua state once nothing of the script is left to run
+func (r *Runner) closeIfFinished() {
+	if r.Done() && !r.HasHooks() {
 		r.close()
 	}
-	return r, cmd, nil
 }
 
 func (r *Runner) close() {
@@ -118,8 +136,12 @@ func (r *Runner) resume() tea.Cmd {
 	return tea.Sequence(cmds...)
 }
 
-// HandleMsg resumes the script if waiting for a matching message.
+// HandleMsg resumes the script if waiting for a matching message, and runs the callbacks
+// scheduled with `jjui.after` when they are due.
 func (r *Runner) HandleMsg(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(timerMsg); ok && msg.runner == r {
+		return r.runTimer(msg.id)
+	}
 	if r.await == nil {
 		return nil
 	}
@@ -130,16 +152,114 @@ func (r *Runner) HandleMsg(msg tea.Msg) tea.Cmd {
 	r.await = nil
 	r.resumeArgs = resume
 	cmd := r.resume()
-	if r.done {
-		r.close()
-	}
+	r.closeIfFinished()
 	return cmd
 }
 
+// Done reports whether the script has finished and none of its timers is pending.
 func (r *Runner) Done() bool {
+	return r.Finished() && len(r.timers) == 0
+}
+
+// Finished reports whether the script has run to its end, the callbacks it scheduled with
+// `jjui.after` may still be pending.
+func (r *Runner) Finished() bool {
 	return r.done && r.await == nil
 }
 
+// Close stops the script, it doesn't resume and its pending timers and refresh hooks don't run anymore.
+func (r *Runner) Close() {
+	r.close()
+	r.done = true
+	r.await = nil
+	r.timers = nil
+	r.refreshHooks = nil
+}
+
+// after schedules the callback to run after the given duration
+func (r *Runner) after(d time.Duration, fn *lua.LFunction) tea.Cmd {
+	if r.timers == nil {
+		r.timers = make(map[int]*lua.LFunction)
+	}
+	r.timerId++
+	id := r.timerId
+	r.timers[id] = fn
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return timerMsg{runner: r, id: id}
+	})
+}
+
+func (r *Runner) runTimer(id int) tea.Cmd {
+	fn, ok := r.timers[id]
+	if !ok {
+		return nil
+	}
+	delete(r.timers, id)
+	var cmd tea.Cmd
+	if r.main != nil {
+		cmd = r.call(fn)
+	}
+	r.closeIfFinished()
+	return cmd
+}
+
+// Script returns the source of the script the runner runs.
+func (r *Runner) Script() string {
+	return r.script
+}
+
+// HasHooks reports whether the script registered callbacks that outlive its execution.
+func (r *Runner) HasHooks() bool {
+	return len(r.refreshHooks) > 0
+}
+
+// OnRefresh invokes the callbacks registered with `jjui.on_refresh` passing the current revset.
+// Callbacks cannot wait for user input, any step that needs to await a message ends the callback.
+func (r *Runner) OnRefresh(revset string) tea.Cmd {
+	if r.main == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, fn := range r.refreshHooks {
+		cmds = append(cmds, r.call(fn, lua.LString(revset)))
+	}
+	return tea.Sequence(cmds...)
+}
+
+// call runs a callback of the script to completion in a thread of its own. Callbacks cannot wait
+// for user input, any step that needs to await a message ends the callback.
+func (r *Runner) call(fn *lua.LFunction, args ...lua.LValue) tea.Cmd {
+	var cmds []tea.Cmd
+	thread, cancel := r.main.NewThread()
+	for {
+		state, err, values := r.main.Resume(thread, fn, args...)
+		if err != nil {
+			cmds = append(cmds, intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err}))
+			break
+		}
+		awaiting := false
+		for _, v := range values {
+			if ud, ok := v.(*lua.LUserData); ok {
+				if st, ok := ud.Value.(step); ok {
+					if st.cmd != nil {
+						cmds = append(cmds, st.cmd)
+					}
+					awaiting = awaiting || st.matcher != nil
+				}
+			}
+		}
+		if state == lua.ResumeOK || awaiting {
+			break
+		}
+		// subsequent resumes continue the yielded coroutine
+		fn, args = nil, nil
+	}
+	if cancel != nil {
+		cancel()
+	}
+	return tea.Sequence(cmds...)
+}
+
 func registerAPI(L *lua.LState, runner *Runner) {
 	revisionsTable := L.NewTable()
 	revisionsTable.RawSetString("current", L.NewFunction(func(L *lua.LState) int {
@@ -254,7 +374,7 @@ func registerAPI(L *lua.LState, runner *Runner) {
 	})
 	copyToClipboardFn := L.NewFunction(func(L *lua.LState) int {
 		text := L.CheckString(1)
-		if err := clipboard.WriteAll(text); err != nil {
+		if err := clipboard.Copy(text); err != nil {
 			L.Push(lua.LNil)
 			L.Push(lua.LString(err.Error()))
 			return 2
@@ -306,6 +426,15 @@ func registerAPI(L *lua.LState, runner *Runner) {
 		options = argsFromLua(L)
 		return yieldStep(L, step{cmd: choose.ShowWithTitle(options, ""), matcher: matchChoose})
 	})
+	onRefreshFn := L.NewFunction(func(L *lua.LState) int {
+		runner.refreshHooks = append(runner.refreshHooks, L.CheckFunction(1))
+		return 0
+	})
+	afterFn := L.NewFunction(func(L *lua.LState) int {
+		ms := L.CheckInt(1)
+		fn := L.CheckFunction(2)
+		return yieldStep(L, step{cmd: runner.after(time.Duration(ms)*time.Millisecond, fn)})
+	})
 	inputFn := L.NewFunction(func(L *lua.LState) int {
 		var title, prompt string
 		if L.GetTop() == 1 {
@@ -334,6 +463,8 @@ func registerAPI(L *lua.LState, runner *Runner) {
 	root.RawSetString("split_lines", splitLinesFn)
 	root.RawSetString("choose", chooseFn)
 	root.RawSetString("input", inputFn)
+	root.RawSetString("on_refresh", onRefreshFn)
+	root.RawSetString("after", afterFn)
 	L.SetGlobal("jjui", root)
 
 	// but also expose at the top level for convenience
@@ -347,6 +478,8 @@ func registerAPI(L *lua.LState, runner *Runner) {
 	L.SetGlobal("split_lines", splitLinesFn)
 	L.SetGlobal("choose", chooseFn)
 	L.SetGlobal("input", inputFn)
+	L.SetGlobal("on_refresh", onRefreshFn)
+	L.SetGlobal("after", afterFn)
 }
 
 func payloadFromTop(L *lua.LState) map[string]any {
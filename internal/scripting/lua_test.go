@@ -0,0 +1,102 @@
+package scripting
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnRefresh_InvokesRegisteredCallbacks(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	runner, _, err := RunScript(test.NewTestContext(commandRunner), `
+jjui.on_refresh(function(revset)
+  flash("viewing " .. revset)
+end)
+`)
+	require.NoError(t, err)
+	assert.True(t, runner.Done())
+	assert.True(t, runner.HasHooks())
+
+	cmd := runner.OnRefresh("trunk()..@")
+	require.NotNil(t, cmd)
+	assert.Equal(t, intents.AddMessage{Text: "viewing trunk()..@"}, cmd())
+}
+
+func TestOnRefresh_WithoutCallbacks(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	runner, _, err := RunScript(test.NewTestContext(commandRunner), `flash("hello")`)
+	require.NoError(t, err)
+	assert.False(t, runner.HasHooks())
+	assert.Nil(t, runner.OnRefresh("@"))
+}
+
+func TestAfter_RunsCallbackWhenDue(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	runner, cmd, err := RunScript(test.NewTestContext(commandRunner), `
+jjui.after(1, function()
+  flash("later")
+end)
+`)
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+	assert.False(t, runner.Done())
+
+	msg := cmd()
+	assert.IsType(t, timerMsg{}, msg)
+	cmd = runner.HandleMsg(msg)
+	require.NotNil(t, cmd)
+	assert.Equal(t, intents.AddMessage{Text: "later"}, cmd())
+	assert.True(t, runner.Done())
+}
+
+func TestAfter_ReschedulesFromCallback(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	runner, cmd, err := RunScript(test.NewTestContext(commandRunner), `
+local polls = 0
+local function poll()
+  polls = polls + 1
+  if polls < 3 then
+    jjui.after(1, poll)
+  else
+    flash("polled " .. polls)
+  end
+end
+jjui.after(1, poll)
+`)
+	require.NoError(t, err)
+
+	for range 2 {
+		cmd = runner.HandleMsg(cmd())
+		require.NotNil(t, cmd)
+		assert.False(t, runner.Done())
+	}
+	cmd = runner.HandleMsg(cmd())
+	assert.Equal(t, intents.AddMessage{Text: "polled 3"}, cmd())
+	assert.True(t, runner.Done())
+}
+
+func TestAfter_IgnoresTimersOfOtherScripts(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	first, cmd, err := RunScript(ctx, `jjui.after(1, function() flash("first") end)`)
+	require.NoError(t, err)
+	second, _, err := RunScript(ctx, `jjui.after(1, function() flash("second") end)`)
+	require.NoError(t, err)
+
+	msg := cmd()
+	assert.Nil(t, second.HandleMsg(msg))
+	assert.NotNil(t, first.HandleMsg(msg))
+}
@@ -3,6 +3,7 @@ package bookmarks
 import (
 	"fmt"
 	"math"
+	"reflect"
 	"slices"
 	"strings"
 
@@ -16,41 +17,70 @@ import (
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/input"
 )
 
 type updateItemsMsg struct {
 	items []list.Item
 }
 
+// reloadItemsMsg reads the bookmarks again while keeping the overlay open
+type reloadItemsMsg struct{}
+
 var _ common.Model = (*Model)(nil)
 
 type Model struct {
 	*common.ViewNode
-	context     *context.MainContext
-	current     *jj.Commit
-	menu        menu.Menu
-	keymap      config.KeyMappings[key.Binding]
-	distanceMap map[string]int
+	context      *context.MainContext
+	current      *jj.Commit
+	menu         menu.Menu
+	keymap       config.KeyMappings[key.Binding]
+	distanceMap  map[string]int
+	confirmation *confirmation.Model
+	rename       *input.Model
+	renaming     string
 }
 
 func (m *Model) ShortHelp() []key.Binding {
+	if m.confirmation != nil {
+		return m.confirmation.ShortHelp()
+	}
+	if m.rename != nil {
+		return m.rename.ShortHelp()
+	}
 	return []key.Binding{
 		m.keymap.Cancel,
 		m.keymap.Apply,
+		m.keymap.ToggleSelect,
 		m.keymap.Bookmark.Move,
 		m.keymap.Bookmark.Delete,
 		m.keymap.Bookmark.Forget,
-		m.keymap.Bookmark.Track,
-		m.keymap.Bookmark.Untrack,
+		m.offered(m.keymap.Bookmark.Track, trackCommand),
+		m.offered(m.keymap.Bookmark.Untrack, untrackCommand),
+		m.keymap.Bookmark.Rename,
 		m.menu.List.KeyMap.Filter,
 	}
 }
 
+// offered disables the binding when none of the bookmarks has the action, e.g. there are no remote
+// bookmarks to track
+func (m *Model) offered(binding key.Binding, action commandType) key.Binding {
+	if !slices.ContainsFunc(m.menu.Items, func(i list.Item) bool { return i.(item).priority == action }) {
+		binding.SetEnabled(false)
+	}
+	return binding
+}
+
 func (m *Model) FullHelp() [][]key.Binding {
 	return [][]key.Binding{m.ShortHelp()}
 }
 
+func (m *Model) Scroll(delta int) tea.Cmd {
+	return m.menu.Scroll(delta)
+}
+
 type commandType int
 
 // defines the order of actions in the list
@@ -62,12 +92,23 @@ const (
 	forgetCommand
 )
 
+type bookmarkKind string
+
+const (
+	localBookmark   bookmarkKind = "local"
+	remoteBookmark  bookmarkKind = "remote"
+	trackedBookmark bookmarkKind = "tracked"
+)
+
 type item struct {
 	name     string
+	bookmark string
+	kind     bookmarkKind
 	priority commandType
 	dist     int
 	args     []string
 	key      string
+	checked  bool
 }
 
 func (i item) ShortCut() string {
@@ -79,11 +120,17 @@ func (i item) FilterValue() string {
 }
 
 func (i item) Title() string {
+	if i.checked {
+		return "✓ " + i.name
+	}
 	return i.name
 }
 
 func (i item) Description() string {
 	desc := strings.Join(i.args, " ")
+	if i.kind != "" {
+		desc = fmt.Sprintf("[%s] %s", i.kind, desc)
+	}
 	return desc
 }
 
@@ -115,6 +162,7 @@ func (m *Model) loadMovables() tea.Msg {
 		}
 		elem := item{
 			name:     name,
+			bookmark: b.Name,
 			priority: moveCommand,
 			args:     jj.BookmarkMove(m.current.GetChangeId(), b.Name, extraFlags...),
 			dist:     m.distance(b.CommitId),
@@ -136,9 +184,15 @@ func (m *Model) loadAll() tea.Msg {
 		items := make([]list.Item, 0)
 		for _, b := range bookmarks {
 			distance := m.distance(b.CommitId)
+			kind := remoteBookmark
+			if b.Local != nil {
+				kind = localBookmark
+			}
 			if b.IsDeletable() {
 				items = append(items, item{
 					name:     fmt.Sprintf("delete '%s'", b.Name),
+					bookmark: b.Name,
+					kind:     kind,
 					priority: deleteCommand,
 					dist:     distance,
 					args:     jj.BookmarkDelete(b.Name),
@@ -147,6 +201,8 @@ func (m *Model) loadAll() tea.Msg {
 
 			items = append(items, item{
 				name:     fmt.Sprintf("forget '%s'", b.Name),
+				bookmark: b.Name,
+				kind:     kind,
 				priority: forgetCommand,
 				dist:     distance,
 				args:     jj.BookmarkForget(b.Name),
@@ -157,6 +213,8 @@ func (m *Model) loadAll() tea.Msg {
 				if remote.Tracked {
 					items = append(items, item{
 						name:     fmt.Sprintf("untrack '%s'", nameWithRemote),
+						bookmark: b.Name,
+						kind:     trackedBookmark,
 						priority: untrackCommand,
 						dist:     distance,
 						args:     jj.BookmarkUntrack(nameWithRemote),
@@ -164,6 +222,8 @@ func (m *Model) loadAll() tea.Msg {
 				} else {
 					items = append(items, item{
 						name:     fmt.Sprintf("track '%s'", nameWithRemote),
+						bookmark: b.Name,
+						kind:     remoteBookmark,
 						priority: trackCommand,
 						dist:     distance,
 						args:     jj.BookmarkTrack(nameWithRemote),
@@ -178,8 +238,33 @@ func (m *Model) loadAll() tea.Msg {
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
+	case confirmation.CloseMsg:
+		m.confirmation = nil
+		return nil
+	case input.SelectedMsg:
+		oldName := m.renaming
+		m.rename = nil
+		m.renaming = ""
+		if msg.Value == "" || msg.Value == oldName {
+			return nil
+		}
+		return m.context.RunCommand(jj.BookmarkRename(oldName, msg.Value), common.Refresh, common.Close)
+	case input.CancelledMsg:
+		m.rename = nil
+		m.renaming = ""
+		return nil
 	case tea.KeyMsg:
+		if m.confirmation != nil {
+			return m.confirmation.Update(msg)
+		}
+		if m.rename != nil {
+			return m.rename.Update(msg)
+		}
 		if m.menu.List.SettingFilter() {
+			// act on the highlighted bookmark without having to accept the filter first
+			if key.Matches(msg, m.keymap.Apply) && len(m.menu.List.VisibleItems()) > 0 {
+				return m.apply(m.menu.List.SelectedItem().(item))
+			}
 			break
 		}
 		switch {
@@ -193,12 +278,22 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			if m.menu.List.SelectedItem() == nil {
 				break
 			}
-			action := m.menu.List.SelectedItem().(item)
-			return m.context.RunCommand(action.args, common.Refresh, common.Close)
+			return m.apply(m.menu.List.SelectedItem().(item))
 		case key.Matches(msg, m.keymap.Bookmark.Move) && m.menu.Filter != "move":
 			return m.filtered("move")
+		case key.Matches(msg, m.keymap.ToggleSelect):
+			return m.toggleChecked()
+		case key.Matches(msg, m.keymap.Bookmark.Delete) && len(m.checkedBookmarks()) > 0:
+			return m.confirmDelete()
 		case key.Matches(msg, m.keymap.Bookmark.Delete) && m.menu.Filter != "delete":
 			return m.filtered("delete")
+		case key.Matches(msg, m.keymap.Bookmark.Rename):
+			if selected, ok := m.menu.List.SelectedItem().(item); ok && selected.bookmark != "" {
+				m.renaming = selected.bookmark
+				m.rename = input.NewWithTitle(fmt.Sprintf("Rename '%s'", selected.bookmark), "New name: ")
+				m.rename.SetValue(selected.bookmark)
+				return m.rename.Init()
+			}
 		case key.Matches(msg, m.keymap.Bookmark.Forget) && m.menu.Filter != "forget":
 			return m.filtered("forget")
 		case key.Matches(msg, m.keymap.Bookmark.Track) && m.menu.Filter != "track":
@@ -208,20 +303,102 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		default:
 			for _, listItem := range m.menu.List.Items() {
 				if item, ok := listItem.(item); ok && m.menu.Filter != "" && item.key == msg.String() {
-					return m.context.RunCommand(jj.Args(item.args...), common.Refresh, common.Close)
+					return m.apply(item)
 				}
 			}
 		}
 	case updateItemsMsg:
 		m.menu.Items = append(m.menu.Items, msg.items...)
 		slices.SortFunc(m.menu.Items, itemSorter)
+		if m.menu.Filter != "" {
+			return m.filtered(m.menu.Filter)
+		}
 		return m.menu.List.SetItems(m.menu.Items)
+	case reloadItemsMsg:
+		m.menu.Items = nil
+		return tea.Batch(m.menu.List.SetItems(nil), m.Init())
 	}
 	var cmd tea.Cmd
 	m.menu.List, cmd = m.menu.List.Update(msg)
 	return cmd
 }
 
+// apply runs the action and closes the overlay, except for tracking and untracking which keep it
+// open to show the new state of the remote bookmark. Untracking is confirmed first as jj stops
+// pushing the local bookmark to the remote.
+func (m *Model) apply(action item) tea.Cmd {
+	switch action.priority {
+	case trackCommand:
+		return m.context.RunCommand(jj.Args(action.args...), common.Refresh, m.reload)
+	case untrackCommand:
+		m.confirmation = confirmation.New(
+			[]string{fmt.Sprintf("Are you sure you want to %s?", action.name), "It will not be pushed or updated with the remote anymore."},
+			confirmation.WithStylePrefix("bookmarks"),
+			confirmation.WithDefaultNo(),
+			confirmation.WithOption("Yes",
+				m.context.RunCommand(jj.Args(action.args...), common.Refresh, confirmation.Close, m.reload),
+				key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+			confirmation.WithOption("No",
+				confirmation.Close,
+				key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+		)
+		return m.confirmation.Init()
+	}
+	return m.context.RunCommand(jj.Args(action.args...), common.Refresh, common.Close)
+}
+
+func (m *Model) reload() tea.Msg {
+	return reloadItemsMsg{}
+}
+
+// toggleChecked checks the bookmark of the highlighted delete action so that
+// several bookmarks can be deleted at once
+func (m *Model) toggleChecked() tea.Cmd {
+	selected, ok := m.menu.List.SelectedItem().(item)
+	if !ok || selected.priority != deleteCommand {
+		return nil
+	}
+	selected.checked = !selected.checked
+	bookmark := context.SelectedBookmark{Name: selected.bookmark}
+	if selected.checked {
+		m.context.AddCheckedItem(bookmark)
+	} else {
+		m.context.RemoveCheckedItem(bookmark)
+	}
+	if index := slices.IndexFunc(m.menu.Items, func(i list.Item) bool { return i.(item).name == selected.name }); index >= 0 {
+		m.menu.Items[index] = selected
+	}
+	cmd := m.menu.List.SetItem(m.menu.List.Index(), selected)
+	m.menu.List.CursorDown()
+	return cmd
+}
+
+func (m *Model) checkedBookmarks() []string {
+	var names []string
+	for _, checked := range m.context.CheckedItems {
+		if bookmark, ok := checked.(context.SelectedBookmark); ok {
+			names = append(names, bookmark.Name)
+		}
+	}
+	return names
+}
+
+func (m *Model) confirmDelete() tea.Cmd {
+	names := m.checkedBookmarks()
+	m.confirmation = confirmation.New(
+		[]string{fmt.Sprintf("Are you sure you want to delete %s?", strings.Join(names, ", "))},
+		confirmation.WithStylePrefix("bookmarks"),
+		confirmation.WithDefaultNo(),
+		confirmation.WithOption("Yes",
+			m.context.RunCommand(jj.BookmarkDelete(names...), common.Refresh, common.Close),
+			key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+		confirmation.WithOption("No",
+			confirmation.Close,
+			key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+	)
+	return m.confirmation.Init()
+}
+
 func itemSorter(a list.Item, b list.Item) int {
 	ia := a.(item)
 	ib := b.(item)
@@ -243,7 +420,16 @@ func itemSorter(a list.Item, b list.Item) int {
 func (m *Model) View() string {
 	pw, ph := m.Parent.Width, m.Parent.Height
 	m.menu.SetFrame(cellbuf.Rect(0, 0, min(pw, 80), min(ph, 40)).Inset(2))
-	v := m.menu.View()
+	var v string
+	switch {
+	case m.confirmation != nil:
+		v = m.confirmation.View()
+	case m.rename != nil:
+		m.rename.Parent = m.ViewNode
+		v = m.rename.View()
+	default:
+		v = m.menu.View()
+	}
 	w, h := lipgloss.Size(v)
 	sx := (pw - w) / 2
 	sy := (ph - h) / 2
@@ -268,6 +454,9 @@ func NewModel(c *context.MainContext, current *jj.Commit, commitIds []string) *M
 		return strings.HasPrefix(i.FilterValue(), filter)
 	}
 
+	// bookmarks checked in a previous session of the overlay are not shown anymore
+	c.ClearCheckedItems(reflect.TypeFor[context.SelectedBookmark]())
+
 	m := &Model{
 		ViewNode:    common.NewViewNode(0, 0),
 		context:     c,
@@ -5,6 +5,10 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,3 +53,124 @@ func Test_Sorting_MixedCommands(t *testing.T) {
 	}
 	assert.Equal(t, []string{"move main", "move very-old-feature", "delete main", "delete very-old-feature"}, sorted)
 }
+
+func Test_FilterAppliesHighlightedBookmark(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkDelete("feature"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: "abc", CommitId: "123"}, nil)
+	model.Parent = common.NewViewNode(100, 40)
+	test.SimulateModel(model, func() tea.Msg {
+		return updateItemsMsg{items: []list.Item{
+			item{name: "delete 'main'", priority: deleteCommand, args: jj.BookmarkDelete("main")},
+			item{name: "delete 'feature'", priority: deleteCommand, args: jj.BookmarkDelete("feature")},
+		}}
+	})
+	model.View()
+
+	test.SimulateModel(model, test.Type("/featre"))
+	assert.Len(t, model.menu.List.VisibleItems(), 1)
+	test.SimulateModel(model, test.Press(tea.KeyEnter))
+}
+
+func Test_FilterClearRestoresAllBookmarks(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: "abc", CommitId: "123"}, nil)
+	model.Parent = common.NewViewNode(100, 40)
+	test.SimulateModel(model, func() tea.Msg {
+		return updateItemsMsg{items: []list.Item{
+			item{name: "delete 'main'", priority: deleteCommand, args: jj.BookmarkDelete("main")},
+			item{name: "delete 'feature'", priority: deleteCommand, args: jj.BookmarkDelete("feature")},
+		}}
+	})
+	model.View()
+
+	test.SimulateModel(model, test.Type("/main"))
+	assert.Len(t, model.menu.List.VisibleItems(), 1)
+	test.SimulateModel(model, test.Press(tea.KeyEsc))
+	assert.Len(t, model.menu.List.VisibleItems(), 2)
+}
+
+func Test_DeletesCheckedBookmarks(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkDelete("feature", "main"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: "abc", CommitId: "123"}, nil)
+	model.Parent = common.NewViewNode(100, 40)
+	test.SimulateModel(model, func() tea.Msg {
+		return updateItemsMsg{items: []list.Item{
+			item{name: "delete 'main'", bookmark: "main", priority: deleteCommand, args: jj.BookmarkDelete("main")},
+			item{name: "delete 'feature'", bookmark: "feature", priority: deleteCommand, args: jj.BookmarkDelete("feature")},
+		}}
+	})
+	model.View()
+
+	test.SimulateModel(model, test.Type("  d"))
+	assert.NotNil(t, model.confirmation)
+	assert.Contains(t, model.View(), "delete feature, main?")
+	test.SimulateModel(model, test.Type("y"))
+}
+
+func Test_RenamesBookmark(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkRename("main", "trunk"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: "abc", CommitId: "123"}, nil)
+	model.Parent = common.NewViewNode(100, 40)
+	test.SimulateModel(model, func() tea.Msg {
+		return updateItemsMsg{items: []list.Item{
+			item{name: "delete 'main'", bookmark: "main", priority: deleteCommand, args: jj.BookmarkDelete("main")},
+		}}
+	})
+	model.View()
+
+	test.SimulateModel(model, test.Type("r"))
+	assert.NotNil(t, model.rename)
+	model.rename.SetValue("trunk")
+	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	assert.Nil(t, model.rename)
+}
+
+func Test_UntrackIsConfirmedAndReloadsBookmarks(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkUntrack("main@origin"))
+	commandRunner.Expect(jj.BookmarkListAll()).SetOutput([]byte("main;.;false;false;false;1\nmain;origin;false;false;false;1\n"))
+	commandRunner.Expect(jj.BookmarkListMovable("abc"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: "abc", CommitId: "123"}, nil)
+	model.Parent = common.NewViewNode(100, 40)
+	test.SimulateModel(model, func() tea.Msg {
+		return updateItemsMsg{items: []list.Item{
+			item{name: "untrack 'main@origin'", bookmark: "main", kind: trackedBookmark, priority: untrackCommand, args: jj.BookmarkUntrack("main@origin")},
+		}}
+	})
+	assert.Contains(t, model.View(), "[tracked] bookmark untrack main@origin")
+
+	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	assert.Contains(t, model.View(), "untrack 'main@origin'?")
+	test.SimulateModel(model, test.Type("y"))
+	assert.Nil(t, model.confirmation)
+	assert.Contains(t, model.View(), "[remote] bookmark track main@origin")
+}
+
+func Test_TrackKeyIsOnlyOfferedForRemoteBookmarks(t *testing.T) {
+	model := NewModel(test.NewTestContext(test.NewTestCommandRunner(t)), &jj.Commit{ChangeId: "abc", CommitId: "123"}, nil)
+	model.Update(updateItemsMsg{items: []list.Item{
+		item{name: "delete 'main'", bookmark: "main", kind: localBookmark, priority: deleteCommand, args: jj.BookmarkDelete("main")},
+	}})
+
+	var enabled []string
+	for _, binding := range model.ShortHelp() {
+		if binding.Enabled() {
+			enabled = append(enabled, binding.Help().Desc)
+		}
+	}
+	assert.NotContains(t, enabled, model.keymap.Bookmark.Track.Help().Desc)
+	assert.NotContains(t, enabled, model.keymap.Bookmark.Untrack.Help().Desc)
+}
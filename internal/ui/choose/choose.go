@@ -1,6 +1,8 @@
 package choose
 
 import (
+	"slices"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -79,6 +81,18 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+func (m *Model) Scroll(delta int) tea.Cmd {
+	m.move(delta)
+	return nil
+}
+
+// Select moves the cursor to the given option if it exists
+func (m *Model) Select(value string) {
+	if i := slices.Index(m.options, value); i >= 0 {
+		m.selected = i
+	}
+}
+
 func (m *Model) move(delta int) {
 	if len(m.options) == 0 {
 		return
@@ -1,6 +1,7 @@
 package autocompletion
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -23,6 +24,8 @@ type AutoCompletionInput struct {
 	lastCompletedValue     string
 	currentSuggestionIndex int
 	firstTabPressed        bool
+	maxSuggestions         int
+	maxWidth               int
 	Styles                 *AutoCompleteStyles
 }
 
@@ -56,6 +59,20 @@ func WithStylePrefix(prefix string) Option {
 	}
 }
 
+// WithMaxSuggestions limits how many suggestions are shown at once
+func WithMaxSuggestions(count int) Option {
+	return func(m *AutoCompletionInput) {
+		m.maxSuggestions = count
+	}
+}
+
+// WithMaxWidth limits the width of the line the suggestions are rendered on
+func WithMaxWidth(width int) Option {
+	return func(m *AutoCompletionInput) {
+		m.maxWidth = width
+	}
+}
+
 func New(provider CompletionProvider, options ...Option) *AutoCompletionInput {
 	ti := textinput.New()
 	ti.Focus()
@@ -111,6 +128,13 @@ func (ac *AutoCompletionInput) Update(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if keyMsg.Paste {
+			// suggestions are only offered for what is typed, not for pasted text
+			ac.tabCompletionActive = false
+			ac.TextInput, cmd = ac.TextInput.Update(common.SingleLinePaste(keyMsg))
+			ac.clearCompletions()
+			return cmd
+		}
 		switch keyMsg.Type {
 		case tea.KeyTab:
 			ac.tabCompletionActive = true
@@ -166,6 +190,16 @@ func (ac *AutoCompletionInput) cycleCompletion(direction int) {
 	ac.currentSuggestionIndex = newIndex
 }
 
+func (ac *AutoCompletionInput) clearCompletions() {
+	ac.previousValue = ac.TextInput.Value()
+	ac.Suggestions = nil
+	ac.currentSuggestionIndex = 0
+	ac.firstTabPressed = false
+	ac.SignatureHelp = ""
+	ac.currentCompletions = nil
+	ac.TextInput.SetSuggestions(nil)
+}
+
 func (ac *AutoCompletionInput) updateCompletions() {
 	value := ac.TextInput.Value()
 	ac.previousValue = value
@@ -210,6 +244,47 @@ func findMatchedPrefix(input, suggestion string) string {
 	return ""
 }
 
+// visibleRange returns the range of completions that fit in the configured
+// limits, keeping the currently selected completion in view.
+func (ac *AutoCompletionInput) visibleRange() (int, int) {
+	total := len(ac.currentCompletions)
+	if ac.maxSuggestions <= 0 && ac.maxWidth <= 0 {
+		return 0, total
+	}
+	// leave room for the "+N more" indicator
+	moreWidth := len(fmt.Sprintf(" +%d more", total))
+	fits := func(start, end int) bool {
+		count := end - start
+		if ac.maxSuggestions > 0 && count > ac.maxSuggestions {
+			return false
+		}
+		if ac.maxWidth <= 0 || count <= 1 {
+			return true
+		}
+		width := count - 1
+		for _, completion := range ac.currentCompletions[start:end] {
+			width += lipgloss.Width(completion.FullText)
+		}
+		if count < total {
+			width += moreWidth
+		}
+		return width <= ac.maxWidth
+	}
+
+	start := 0
+	end := 0
+	for end < total && fits(start, end+1) {
+		end++
+	}
+	for end <= ac.currentSuggestionIndex && end < total {
+		end++
+		for start < end-1 && !fits(start, end) {
+			start++
+		}
+	}
+	return start, max(end, min(start+1, total))
+}
+
 func (ac *AutoCompletionInput) View() string {
 	var builder strings.Builder
 
@@ -219,8 +294,8 @@ func (ac *AutoCompletionInput) View() string {
 	if len(ac.Suggestions) > 0 {
 		builder.WriteString("\n")
 
-		visibleCount := len(ac.currentCompletions)
-		for i := 0; i < visibleCount; i++ {
+		start, end := ac.visibleRange()
+		for i := start; i < end; i++ {
 			completion := ac.currentCompletions[i]
 
 			if i == ac.currentSuggestionIndex {
@@ -232,14 +307,13 @@ func (ac *AutoCompletionInput) View() string {
 				builder.WriteString(restPart)
 			}
 
-			if i < visibleCount-1 {
+			if i < end-1 {
 				builder.WriteString(ac.Styles.Text.Render(" "))
 			}
 		}
 
-		if len(ac.currentCompletions) > visibleCount {
-			builder.WriteString(" +" +
-				ac.Styles.Text.Render(string(rune('0'+len(ac.currentCompletions)-visibleCount))+" more"))
+		if hidden := len(ac.currentCompletions) - (end - start); hidden > 0 {
+			builder.WriteString(ac.Styles.Dimmed.Render(fmt.Sprintf(" +%d more", hidden)))
 		}
 	} else if ac.SignatureHelp != "" {
 		builder.WriteString("\n")
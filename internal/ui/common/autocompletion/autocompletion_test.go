@@ -0,0 +1,64 @@
+package autocompletion
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticProvider []string
+
+func (p staticProvider) GetCompletions(string) []string          { return p }
+func (p staticProvider) GetSignatureHelp(string) string          { return "" }
+func (p staticProvider) GetLastToken(input string) (int, string) { return 0, input }
+
+func TestView_LimitsSuggestionCount(t *testing.T) {
+	ac := New(staticProvider{"one", "two", "three", "four"}, WithMaxSuggestions(2))
+	ac.SetValue("o")
+
+	view := test.Stripped(ac.View())
+	assert.Contains(t, view, "one two +2 more")
+	assert.NotContains(t, view, "three")
+}
+
+func TestView_LimitsSuggestionWidth(t *testing.T) {
+	ac := New(staticProvider{"aaaa", "bbbb", "cccc", "dddd"}, WithMaxWidth(20))
+	ac.SetValue("x")
+
+	view := test.Stripped(ac.View())
+	assert.Contains(t, view, "aaaa bbbb +2 more")
+}
+
+func TestView_KeepsSelectedSuggestionVisible(t *testing.T) {
+	ac := New(staticProvider{"one", "two", "three", "four"}, WithMaxSuggestions(2))
+	ac.SetValue("o")
+	for range 4 {
+		ac.Update(tea.KeyMsg{Type: tea.KeyTab})
+	}
+
+	view := test.Stripped(ac.View())
+	assert.Contains(t, view, "four")
+	assert.Contains(t, view, "+2 more")
+}
+
+func TestView_ShowsAllSuggestionsWithoutLimits(t *testing.T) {
+	ac := New(staticProvider{"one", "two", "three"})
+	ac.SetValue("o")
+
+	view := test.Stripped(ac.View())
+	assert.Contains(t, view, "one two three")
+	assert.NotContains(t, view, "more")
+}
+
+func TestUpdate_PasteDoesNotSuggest(t *testing.T) {
+	ac := New(staticProvider{"one", "two"})
+	ac.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("mine()\n| @\n"), Paste: true})
+
+	assert.Equal(t, "mine() | @", ac.Value())
+	assert.Empty(t, ac.Suggestions)
+
+	ac.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	assert.Equal(t, []string{"one", "two"}, ac.Suggestions)
+}
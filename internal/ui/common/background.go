@@ -0,0 +1,48 @@
+package common
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Terminals that support colour palette update notifications (mode 2031) report
+// CSI ? 997 ; 1 n after switching to a dark theme and CSI ? 997 ; 2 n after switching to a light one.
+// OSC 11 replies would be more widely supported but Bubble Tea v1 splits them into key presses.
+const (
+	EnableBackgroundReports  = "\x1b[?2031h"
+	DisableBackgroundReports = "\x1b[?2031l"
+
+	darkBackgroundReport  = "\x1b[?997;1n"
+	lightBackgroundReport = "\x1b[?997;2n"
+)
+
+// BackgroundChangedMsg is sent when the terminal switches between a dark and a light background
+type BackgroundChangedMsg struct {
+	Dark bool
+}
+
+// ThemeChangedMsg is sent to every view after DefaultPalette is replaced, so that they read their styles again
+type ThemeChangedMsg struct{}
+
+// ParseBackgroundReport converts a mode 2031 report into a BackgroundChangedMsg.
+// Bubble Tea passes the sequences it doesn't recognise as an unexported type,
+// which is only told apart by the way it prints them.
+func ParseBackgroundReport(msg tea.Msg) (BackgroundChangedMsg, bool) {
+	sequence, ok := msg.(fmt.Stringer)
+	if !ok {
+		return BackgroundChangedMsg{}, false
+	}
+	switch sequence.String() {
+	case unknownSequence(darkBackgroundReport):
+		return BackgroundChangedMsg{Dark: true}, true
+	case unknownSequence(lightBackgroundReport):
+		return BackgroundChangedMsg{Dark: false}, true
+	}
+	return BackgroundChangedMsg{}, false
+}
+
+// unknownSequence prints a CSI sequence the way Bubble Tea prints the ones it doesn't recognise
+func unknownSequence(sequence string) string {
+	return fmt.Sprintf("?CSI%+v?", []byte(sequence)[2:])
+}
@@ -0,0 +1,33 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// csiSequence stands in for the unexported type Bubble Tea uses for unknown CSI sequences
+type csiSequence []byte
+
+func (c csiSequence) String() string {
+	return fmt.Sprintf("?CSI%+v?", []byte(c)[2:])
+}
+
+func TestParseBackgroundReport(t *testing.T) {
+	msg, ok := ParseBackgroundReport(csiSequence("\x1b[?997;1n"))
+	assert.True(t, ok)
+	assert.Equal(t, BackgroundChangedMsg{Dark: true}, msg)
+
+	msg, ok = ParseBackgroundReport(csiSequence("\x1b[?997;2n"))
+	assert.True(t, ok)
+	assert.Equal(t, BackgroundChangedMsg{Dark: false}, msg)
+
+	_, ok = ParseBackgroundReport(csiSequence("\x1b[?1;2c"))
+	assert.False(t, ok)
+	_, ok = ParseBackgroundReport(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.False(t, ok)
+	_, ok = ParseBackgroundReport(nil)
+	assert.False(t, ok)
+}
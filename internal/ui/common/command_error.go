@@ -0,0 +1,31 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommandError is returned when jj exits with a non-zero code, the message is what jj wrote to stderr
+type CommandError struct {
+	Args     []string
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+func (e *CommandError) Error() string {
+	return e.Stderr
+}
+
+// Title names the failed command and its exit code
+func (e *CommandError) Title() string {
+	return fmt.Sprintf("jj %s (exit code %d)", strings.Join(e.Args, " "), e.ExitCode)
+}
+
+// Output is everything jj wrote, stdout followed by stderr
+func (e *CommandError) Output() string {
+	if strings.TrimSpace(e.Stdout) == "" {
+		return e.Stderr
+	}
+	return strings.TrimRight(e.Stdout, "\n") + "\n" + e.Stderr
+}
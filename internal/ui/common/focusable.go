@@ -17,6 +17,11 @@ type Overlay interface {
 	IsOverlay() bool
 }
 
+// Scrollable is implemented by stacked models that can be scrolled with the mouse wheel
+type Scrollable interface {
+	Scroll(delta int) tea.Cmd
+}
+
 type IMouseAware interface {
 	Update(msg tea.Msg) tea.Cmd
 	ClickAt(x, y int) tea.Cmd
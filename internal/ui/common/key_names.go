@@ -0,0 +1,137 @@
+package common
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyByName returns the key that key bindings refer to by the given name, e.g. "enter",
+// "ctrl+s" or "alt+d". Plain characters are not names, they are typed as runes.
+func KeyByName(name string) (tea.Key, bool) {
+	if k, ok := keyNames[name]; ok {
+		return k, true
+	}
+	rest, alt := strings.CutPrefix(name, "alt+")
+	if !alt {
+		return tea.Key{}, false
+	}
+	if k, ok := keyNames[rest]; ok {
+		k.Alt = true
+		return k, true
+	}
+	if runes := []rune(rest); len(runes) == 1 {
+		return tea.Key{Type: tea.KeyRunes, Runes: runes, Alt: true}, true
+	}
+	return tea.Key{}, false
+}
+
+// From bubbletea's key.go. So that we can identify by their string.
+// Notable Exception: tea.KeyRunes. Because we create them.
+var keyTypes = []tea.KeyType{
+	// Control keys.
+	tea.KeyTab,
+	tea.KeyEnter,
+	tea.KeyEsc,
+	tea.KeyBackspace,
+
+	tea.KeyCtrlAt,
+	tea.KeyCtrlA,
+	tea.KeyCtrlB,
+	tea.KeyCtrlC,
+	tea.KeyCtrlD,
+	tea.KeyCtrlE,
+	tea.KeyCtrlF,
+	tea.KeyCtrlG,
+	tea.KeyCtrlH,
+	tea.KeyCtrlJ,
+	tea.KeyCtrlK,
+	tea.KeyCtrlL,
+	tea.KeyCtrlN,
+	tea.KeyCtrlO,
+	tea.KeyCtrlP,
+	tea.KeyCtrlQ,
+	tea.KeyCtrlR,
+	tea.KeyCtrlS,
+	tea.KeyCtrlT,
+	tea.KeyCtrlU,
+	tea.KeyCtrlV,
+	tea.KeyCtrlW,
+	tea.KeyCtrlX,
+	tea.KeyCtrlY,
+	tea.KeyCtrlZ,
+
+	tea.KeyCtrlCloseBracket,
+	tea.KeyCtrlCaret,
+	tea.KeyCtrlUnderscore,
+	tea.KeyCtrlBackslash,
+
+	// Other keys.
+	tea.KeyUp,
+	tea.KeyDown,
+	tea.KeyRight,
+	tea.KeySpace,
+	tea.KeyLeft,
+	tea.KeyShiftTab,
+	tea.KeyHome,
+	tea.KeyEnd,
+	tea.KeyCtrlHome,
+	tea.KeyCtrlEnd,
+	tea.KeyShiftHome,
+	tea.KeyShiftEnd,
+	tea.KeyCtrlShiftHome,
+	tea.KeyCtrlShiftEnd,
+	tea.KeyPgUp,
+	tea.KeyPgDown,
+	tea.KeyCtrlPgUp,
+	tea.KeyCtrlPgDown,
+	tea.KeyDelete,
+	tea.KeyInsert,
+	tea.KeyCtrlUp,
+	tea.KeyCtrlDown,
+	tea.KeyCtrlRight,
+	tea.KeyCtrlLeft,
+	tea.KeyShiftUp,
+	tea.KeyShiftDown,
+	tea.KeyShiftRight,
+	tea.KeyShiftLeft,
+	tea.KeyCtrlShiftUp,
+	tea.KeyCtrlShiftDown,
+	tea.KeyCtrlShiftLeft,
+	tea.KeyCtrlShiftRight,
+	tea.KeyF1,
+	tea.KeyF2,
+	tea.KeyF3,
+	tea.KeyF4,
+	tea.KeyF5,
+	tea.KeyF6,
+	tea.KeyF7,
+	tea.KeyF8,
+	tea.KeyF9,
+	tea.KeyF10,
+	tea.KeyF11,
+	tea.KeyF12,
+	tea.KeyF13,
+	tea.KeyF14,
+	tea.KeyF15,
+	tea.KeyF16,
+	tea.KeyF17,
+	tea.KeyF18,
+	tea.KeyF19,
+	tea.KeyF20,
+}
+
+func keysFromTypes() map[string]tea.Key {
+	m := map[string]tea.Key{}
+	set := func(t tea.KeyType) {
+		m[t.String()] = tea.Key{
+			Type: t,
+		}
+	}
+	for _, t := range keyTypes {
+		set(t)
+	}
+	return m
+}
+
+var keyNames = keysFromTypes()
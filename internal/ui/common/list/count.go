@@ -0,0 +1,77 @@
+package list
+
+import (
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// countTimeout is how long a numeric prefix waits for the key it applies to
+const countTimeout = 2 * time.Second
+
+type PrefixResult int
+
+const (
+	// PrefixNone means the key is not part of a prefix and should be handled as usual
+	PrefixNone PrefixResult = iota
+	// PrefixPending means the key was consumed while building up the prefix
+	PrefixPending
+	// PrefixTop means the key completed a gg
+	PrefixTop
+)
+
+// CountPrefix accumulates vim style numeric prefixes (e.g. 5j) and the g of gg.
+// The accumulated count is dropped on any other key or when it is not used within countTimeout.
+type CountPrefix struct {
+	count    int
+	pendingG bool
+	updated  time.Time
+}
+
+// Update feeds a key press into the prefix.
+func (c *CountPrefix) Update(msg tea.KeyMsg) PrefixResult {
+	if c.expired() {
+		c.Reset()
+	}
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 || msg.Alt {
+		c.pendingG = false
+		return PrefixNone
+	}
+	r := msg.Runes[0]
+	switch {
+	case r >= '0' && r <= '9' && (r != '0' || c.count > 0) && !c.pendingG:
+		digit, _ := strconv.Atoi(string(r))
+		c.count = min(c.count*10+digit, 99999)
+		c.updated = time.Now()
+		return PrefixPending
+	case r == 'g' && c.pendingG:
+		c.Reset()
+		return PrefixTop
+	case r == 'g':
+		c.pendingG = true
+		c.updated = time.Now()
+		return PrefixPending
+	}
+	c.pendingG = false
+	return PrefixNone
+}
+
+// Take returns the accumulated count, or 1 when there is none, and resets the prefix.
+func (c *CountPrefix) Take() int {
+	count := c.count
+	if c.expired() || count == 0 {
+		count = 1
+	}
+	c.Reset()
+	return count
+}
+
+func (c *CountPrefix) Reset() {
+	c.count = 0
+	c.pendingG = false
+}
+
+func (c *CountPrefix) expired() bool {
+	return (c.count > 0 || c.pendingG) && time.Since(c.updated) > countTimeout
+}
@@ -0,0 +1,45 @@
+package list
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func keyRune(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestCountPrefix_AccumulatesDigits(t *testing.T) {
+	var c CountPrefix
+	assert.Equal(t, PrefixPending, c.Update(keyRune('1')))
+	assert.Equal(t, PrefixPending, c.Update(keyRune('0')))
+	assert.Equal(t, PrefixNone, c.Update(keyRune('j')))
+	assert.Equal(t, 10, c.Take())
+	assert.Equal(t, 1, c.Take())
+}
+
+func TestCountPrefix_LeadingZeroIsNotACount(t *testing.T) {
+	var c CountPrefix
+	assert.Equal(t, PrefixNone, c.Update(keyRune('0')))
+	assert.Equal(t, 1, c.Take())
+}
+
+func TestCountPrefix_DoubleG(t *testing.T) {
+	var c CountPrefix
+	assert.Equal(t, PrefixPending, c.Update(keyRune('g')))
+	assert.Equal(t, PrefixTop, c.Update(keyRune('g')))
+
+	assert.Equal(t, PrefixPending, c.Update(keyRune('g')))
+	assert.Equal(t, PrefixNone, c.Update(keyRune('j')))
+	assert.Equal(t, PrefixPending, c.Update(keyRune('g')))
+}
+
+func TestCountPrefix_ExpiresAfterTimeout(t *testing.T) {
+	var c CountPrefix
+	c.Update(keyRune('5'))
+	c.updated = time.Now().Add(-2 * countTimeout)
+	assert.Equal(t, 1, c.Take())
+}
@@ -95,6 +95,17 @@ func NewMenu(items []list.Item, keyMap config.KeyMappings[key.Binding], options
 	return m
 }
 
+// Scroll moves the selection by delta items
+func (m *Menu) Scroll(delta int) tea.Cmd {
+	for ; delta < 0; delta++ {
+		m.List.CursorUp()
+	}
+	for ; delta > 0; delta-- {
+		m.List.CursorDown()
+	}
+	return nil
+}
+
 func (m *Menu) ShowShortcuts(show bool) {
 	m.List.SetDelegate(MenuItemDelegate{ShowShortcuts: show, styles: m.styles})
 }
@@ -66,11 +66,17 @@ func (l MenuItemDelegate) Render(w io.Writer, m list.Model, index int, item list
 		shortcutStyle = shortcutStyle.Background(l.styles.selected.GetBackground())
 	}
 
+	renderedTitle := titleStyle.Render(title)
+	if matches := m.MatchesForItem(index); len(matches) > 0 && title == item.FilterValue() {
+		matchedStyle := l.styles.matched.Background(titleStyle.GetBackground())
+		renderedTitle = lipgloss.StyleRunes(title, matches, matchedStyle, titleStyle)
+	}
+
 	titleLine := ""
 	if shortcut != "" {
-		titleLine = lipgloss.JoinHorizontal(0, shortcutStyle.PaddingLeft(1).Render(shortcut), titleStyle.PaddingLeft(1).Render(title))
+		titleLine = lipgloss.JoinHorizontal(0, shortcutStyle.PaddingLeft(1).Render(shortcut), titleStyle.Render(" "), renderedTitle)
 	} else {
-		titleLine = titleStyle.PaddingLeft(1).Render(title)
+		titleLine = lipgloss.JoinHorizontal(0, titleStyle.Render(" "), renderedTitle)
 	}
 	titleLine = lipgloss.PlaceHorizontal(m.Width()+2, 0, titleLine, lipgloss.WithWhitespaceBackground(titleStyle.GetBackground()))
 
@@ -1,9 +1,11 @@
 package common
 
 import (
+	"errors"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 )
 
@@ -17,7 +19,11 @@ type (
 		SelectedRevision string
 		KeepSelections   bool
 	}
-	ShowDiffMsg              string
+	ShowDiffMsg   string
+	ShowOutputMsg struct {
+		Title  string
+		Output string
+	}
 	UpdateRevisionsFailedMsg struct {
 		Output string
 		Err    error
@@ -30,14 +36,22 @@ type (
 	CommandRunningMsg   string
 	CommandCompletedMsg struct {
 		Output string
+		Stdout string
 		Err    error
 	}
+	// CommandResultMsg hands the result of the command it follows to Handle
+	CommandResultMsg struct {
+		Handle func(CommandCompletedMsg) tea.Cmd
+		Result CommandCompletedMsg
+	}
 	SelectionChangedMsg struct{}
 	QuickSearchMsg      string
 	UpdateRevSetMsg     string
 	ExecMsg             struct {
 		Line string
 		Mode ExecMode
+		// Interactive programs like editors take over the terminal, jjui comes back as soon as they exit
+		Interactive bool
 	}
 	ShowChooseMsg struct {
 		Options []string
@@ -47,6 +61,10 @@ type (
 		Title  string
 		Prompt string
 	}
+	ShowReorderMsg struct {
+		Title     string
+		Revisions jj.SelectedRevisions
+	}
 	ExecProcessCompletedMsg struct {
 		Err error
 		Msg ExecMsg
@@ -56,11 +74,17 @@ type (
 		PreviewShown bool
 		Commit       *jj.Commit
 		RawFileOut   []byte // raw output from `jj file list`
+		Workspace    bool   // searching the files of the working copy instead of the selected revision
 	}
 	ShowPreview     bool
 	RunLuaScriptMsg struct {
 		Script string
 	}
+	// ShowDiffInPreviewMsg shows the preview following the file under the cursor, FullScreen is
+	// run instead when the preview is already shown
+	ShowDiffInPreviewMsg struct {
+		FullScreen tea.Cmd
+	}
 	TogglePasswordMsg struct {
 		Prompt   string
 		Password chan []byte
@@ -81,6 +105,9 @@ const (
 	Error
 )
 
+// OpenFileDetailsMsg shows the revisions changing a file and opens the details of the latest one at the file
+type OpenFileDetailsMsg string
+
 func Close() tea.Msg {
 	return CloseViewMsg{}
 }
@@ -103,6 +130,65 @@ func RefreshAndKeepSelections() tea.Msg {
 	return RefreshMsg{KeepSelections: true}
 }
 
+// OnCommandResult is a continuation handing the result of the command it follows to handle
+func OnCommandResult(handle func(CommandCompletedMsg) tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		return CommandResultMsg{Handle: handle}
+	}
+}
+
+// WithCommandResult returns run followed by the continuations, the ones made with
+// OnCommandResult are handed the result of run
+func WithCommandResult(run func() CommandCompletedMsg, continuations ...tea.Cmd) []tea.Cmd {
+	var result CommandCompletedMsg
+	cmds := []tea.Cmd{func() tea.Msg {
+		result = run()
+		return result
+	}}
+	for _, continuation := range continuations {
+		if continuation == nil {
+			continue
+		}
+		cmds = append(cmds, func() tea.Msg {
+			msg := continuation()
+			if resultMsg, ok := msg.(CommandResultMsg); ok {
+				resultMsg.Result = result
+				return resultMsg
+			}
+			return msg
+		})
+	}
+	return cmds
+}
+
+// CombinedOutput is everything the command wrote, stdout followed by stderr
+func (m CommandCompletedMsg) CombinedOutput() string {
+	var commandErr *CommandError
+	if errors.As(m.Err, &commandErr) {
+		return commandErr.Output()
+	}
+	output := m.Output
+	if m.Err != nil {
+		output = m.Err.Error()
+	}
+	if strings.TrimSpace(m.Stdout) == "" {
+		return output
+	}
+	return strings.TrimRight(m.Stdout, "\n") + "\n" + output
+}
+
+// AfterCommand returns the command to run once a command completes as configured in post_command
+func AfterCommand(postCommand config.PostCommand) tea.Cmd {
+	switch postCommand {
+	case config.PostCommandKeepSelections:
+		return RefreshAndKeepSelections
+	case config.PostCommandNone:
+		return nil
+	default:
+		return Refresh
+	}
+}
+
 func Refresh() tea.Msg {
 	return RefreshMsg{}
 }
@@ -135,6 +221,18 @@ func FileSearch(revset string, preview bool, commit *jj.Commit, rawFileOut []byt
 	}
 }
 
+func WorkspaceFileSearch(revset string, preview bool, workingCopy *jj.Commit, rawFileOut []byte) tea.Cmd {
+	return func() tea.Msg {
+		return FileSearchMsg{
+			Commit:       workingCopy,
+			RawFileOut:   rawFileOut,
+			Revset:       revset,
+			PreviewShown: preview,
+			Workspace:    true,
+		}
+	}
+}
+
 type ExecMode struct {
 	Mode   string
 	Prompt string
@@ -0,0 +1,20 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAfterCommand(t *testing.T) {
+	assert.Equal(t, RefreshMsg{}, AfterCommand(config.PostCommandRefresh)())
+	assert.Equal(t, RefreshMsg{KeepSelections: true}, AfterCommand(config.PostCommandKeepSelections)())
+	assert.Nil(t, AfterCommand(config.PostCommandNone))
+}
+
+func TestCommandCompletedMsg_CombinedOutput(t *testing.T) {
+	assert.Equal(t, "log\nWorking copy now at: abc", CommandCompletedMsg{Stdout: "log\n", Output: "Working copy now at: abc"}.CombinedOutput())
+	assert.Equal(t, "Error: boom", CommandCompletedMsg{Output: "ignored", Err: errors.New("Error: boom")}.CombinedOutput())
+}
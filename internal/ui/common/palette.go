@@ -63,6 +63,21 @@ func (p *Palette) get(fields ...string) lipgloss.Style {
 	return current.style
 }
 
+// ApplyTheme replaces DefaultPalette with one built from the theme for the terminal background,
+// the colours taken from jj's config and ui.colors, in that order
+func ApplyTheme(dark bool, jjColors map[string]config.Color) error {
+	theme, err := config.ResolveTheme(dark)
+	if err != nil {
+		return err
+	}
+	palette := NewPalette()
+	palette.Update(theme)
+	palette.Update(jjColors)
+	palette.Update(config.Current.UI.Colors)
+	DefaultPalette = palette
+	return nil
+}
+
 func (p *Palette) Update(styleMap map[string]config.Color) {
 	for key, color := range styleMap {
 		p.add(key, createStyleFrom(color))
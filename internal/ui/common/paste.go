@@ -0,0 +1,33 @@
+package common
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SingleLinePaste prepares a bracketed paste for a single line input, see SingleLine
+func SingleLinePaste(msg tea.KeyMsg) tea.KeyMsg {
+	if !msg.Paste {
+		return msg
+	}
+	msg.Runes = []rune(SingleLine(string(msg.Runes)))
+	return msg
+}
+
+// SingleLine puts pasted text on a single line. Line breaks and the whitespace around them
+// are collapsed into a single space, and the whitespace surrounding the text (e.g. the
+// trailing new line of a copied line) is dropped.
+func SingleLine(text string) string {
+	lines := strings.FieldsFunc(text, func(r rune) bool {
+		return r == '\n' || r == '\r'
+	})
+	var parts []string
+	for _, line := range lines {
+		if line = strings.TrimFunc(line, unicode.IsSpace); line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleLinePaste(t *testing.T) {
+	tests := []struct {
+		name     string
+		pasted   string
+		expected string
+	}{
+		{name: "single line", pasted: "trunk()..@", expected: "trunk()..@"},
+		{name: "trailing new line", pasted: "trunk()..@\n", expected: "trunk()..@"},
+		{name: "multiple lines", pasted: "mine()\n  & ~empty()\r\n| @", expected: "mine() & ~empty() | @"},
+		{name: "blank lines", pasted: "\n\nmine()\n\n\n@\n", expected: "mine() @"},
+		{name: "keeps inner spaces", pasted: "author(x)  &  @", expected: "author(x)  &  @"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := SingleLinePaste(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.pasted), Paste: true})
+			assert.Equal(t, tt.expected, string(msg.Runes))
+			assert.True(t, msg.Paste)
+		})
+	}
+}
+
+func TestSingleLinePaste_IgnoresTypedKeys(t *testing.T) {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
+	assert.Equal(t, msg, SingleLinePaste(msg))
+}
@@ -0,0 +1,268 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/screen"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// Model keeps the state of an in-content search: the prompt used to enter the
+// pattern and the lines of the content that match it.
+type Model struct {
+	input   textinput.Model
+	editing bool
+	regex   bool
+	pattern *regexp.Regexp
+	err     error
+	lines   []string
+	matches []int
+	current int
+	keymap  config.KeyMappings[key.Binding]
+	styles  styles
+}
+
+type styles struct {
+	matched lipgloss.Style
+	text    lipgloss.Style
+	dimmed  lipgloss.Style
+	error   lipgloss.Style
+}
+
+func New() *Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	return &Model{
+		input:   ti,
+		current: -1,
+		keymap:  config.Current.GetKeyMap(),
+		styles: styles{
+			matched: common.DefaultPalette.Get("search matched"),
+			text:    common.DefaultPalette.Get("search text"),
+			dimmed:  common.DefaultPalette.Get("search dimmed"),
+			error:   common.DefaultPalette.Get("search error"),
+		},
+	}
+}
+
+// Start opens the prompt for entering a new pattern
+func (m *Model) Start() tea.Cmd {
+	m.editing = true
+	m.err = nil
+	m.input.SetValue("")
+	return m.input.Focus()
+}
+
+func (m *Model) Editing() bool {
+	return m.editing
+}
+
+// Active reports whether a pattern has been applied
+func (m *Model) Active() bool {
+	return m.pattern != nil
+}
+
+func (m *Model) Regex() bool {
+	return m.regex
+}
+
+// Clear removes the applied pattern and its matches
+func (m *Model) Clear() {
+	m.pattern = nil
+	m.matches = nil
+	m.current = -1
+}
+
+// Update handles the keys typed into the prompt. It reports true when a new
+// pattern has been applied, so the owner can re-render its content and jump
+// to the first match.
+func (m *Model) Update(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case msg.Type == tea.KeyEnter:
+		m.editing = false
+		m.input.Blur()
+		if m.input.Value() == "" {
+			m.Clear()
+			return nil, true
+		}
+		if err := m.compile(m.input.Value()); err != nil {
+			m.err = err
+			return nil, false
+		}
+		m.find()
+		return nil, true
+	case msg.Type == tea.KeyEsc:
+		m.editing = false
+		m.input.Blur()
+		return nil, false
+	case key.Matches(msg, m.keymap.ContentSearch.ToggleRegex):
+		m.regex = !m.regex
+		return nil, false
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd, false
+}
+
+func (m *Model) compile(pattern string) error {
+	if !m.regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	m.pattern = re
+	return nil
+}
+
+// SetContent sets the content to search in, keeping the applied pattern
+func (m *Model) SetContent(content string) {
+	m.lines = strings.Split(content, "\n")
+	m.find()
+}
+
+func (m *Model) find() {
+	m.matches = nil
+	m.current = -1
+	if m.pattern == nil {
+		return
+	}
+	for i, line := range m.lines {
+		if m.pattern.MatchString(stripAnsi(line)) {
+			m.matches = append(m.matches, i)
+		}
+	}
+}
+
+// Matches returns the indices of the lines containing a match
+func (m *Model) Matches() []int {
+	return m.matches
+}
+
+// Next returns the first matching line after the given line, wrapping around
+// to the top of the content.
+func (m *Model) Next(line int) (int, bool) {
+	if len(m.matches) == 0 {
+		return 0, false
+	}
+	m.current = 0
+	for i, match := range m.matches {
+		if match > line {
+			m.current = i
+			break
+		}
+	}
+	return m.matches[m.current], true
+}
+
+// Prev returns the last matching line before the given line, wrapping around
+// to the bottom of the content.
+func (m *Model) Prev(line int) (int, bool) {
+	if len(m.matches) == 0 {
+		return 0, false
+	}
+	m.current = len(m.matches) - 1
+	for i := len(m.matches) - 1; i >= 0; i-- {
+		if m.matches[i] < line {
+			m.current = i
+			break
+		}
+	}
+	return m.matches[m.current], true
+}
+
+// Highlight renders the content with every match emphasised
+func (m *Model) Highlight() string {
+	if m.pattern == nil || len(m.matches) == 0 {
+		return strings.Join(m.lines, "\n")
+	}
+	lines := make([]string, len(m.lines))
+	copy(lines, m.lines)
+	for _, i := range m.matches {
+		lines[i] = m.highlightLine(lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// highlightLine splits the styled segments of the line at the match
+// boundaries so that the rest of the line keeps its original colours.
+func (m *Model) highlightLine(line string) string {
+	segments := screen.Parse([]byte(line))
+	var plain strings.Builder
+	for _, segment := range segments {
+		plain.WriteString(segment.Text)
+	}
+	ranges := m.pattern.FindAllStringIndex(plain.String(), -1)
+
+	var sb strings.Builder
+	offset := 0
+	for _, segment := range segments {
+		segmentStart := offset
+		start, end := offset, offset+len(segment.Text)
+		offset = end
+		for start < end {
+			next, matched := end, false
+			for _, r := range ranges {
+				if r[0] <= start && start < r[1] {
+					next, matched = min(r[1], end), true
+					break
+				}
+				if start < r[0] && r[0] < next {
+					next = r[0]
+				}
+			}
+			text := segment.Text[start-segmentStart : next-segmentStart]
+			if matched {
+				sb.WriteString(m.styles.matched.Render(text))
+			} else {
+				sb.WriteString(segment.Style.Render(text))
+			}
+			start = next
+		}
+	}
+	return sb.String()
+}
+
+func (m *Model) View() string {
+	mode := "literal"
+	if m.regex {
+		mode = "regex"
+	}
+	var status string
+	switch {
+	case m.err != nil:
+		status = m.styles.error.Render(m.err.Error())
+	case m.editing:
+		status = m.styles.dimmed.Render(fmt.Sprintf("[%s] %s", mode, m.keymap.ContentSearch.ToggleRegex.Help().Key))
+	case len(m.matches) == 0:
+		status = m.styles.dimmed.Render(fmt.Sprintf("[%s] no matches", mode))
+	case m.current < 0:
+		status = m.styles.dimmed.Render(fmt.Sprintf("[%s] %d matches", mode, len(m.matches)))
+	default:
+		status = m.styles.dimmed.Render(fmt.Sprintf("[%s] %d/%d", mode, m.current+1, len(m.matches)))
+	}
+	prompt := m.styles.text.Render("/" + m.input.Value())
+	if m.editing {
+		prompt = m.input.View()
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, prompt, " ", status)
+}
+
+func stripAnsi(line string) string {
+	if !strings.Contains(line, "\x1b") {
+		return line
+	}
+	var sb strings.Builder
+	for _, segment := range screen.Parse([]byte(line)) {
+		sb.WriteString(segment.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func apply(m *Model, pattern string) bool {
+	m.Start()
+	for _, r := range pattern {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, applied := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return applied
+}
+
+func TestSearch_LiteralPatternMatchesLines(t *testing.T) {
+	m := New()
+	m.SetContent("foo.go\nbar\nfooxgo")
+
+	assert.True(t, apply(m, "foo.go"))
+	assert.Equal(t, []int{0}, m.Matches())
+}
+
+func TestSearch_RegexPattern(t *testing.T) {
+	m := New()
+	m.SetContent("foo.go\nbar\nfooxgo")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	assert.True(t, m.Regex())
+
+	assert.True(t, apply(m, "foo.go"))
+	assert.Equal(t, []int{0, 2}, m.Matches())
+}
+
+func TestSearch_InvalidRegexKeepsPrompt(t *testing.T) {
+	m := New()
+	m.SetContent("foo")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+
+	assert.False(t, apply(m, "("))
+	assert.False(t, m.Active())
+	assert.Contains(t, m.View(), "invalid pattern")
+}
+
+func TestSearch_NextAndPrevWrap(t *testing.T) {
+	m := New()
+	m.SetContent("a\nb\na\nb\na")
+	apply(m, "a")
+
+	line, ok := m.Next(0)
+	assert.True(t, ok)
+	assert.Equal(t, 2, line)
+	line, _ = m.Next(4)
+	assert.Equal(t, 0, line)
+	line, _ = m.Prev(0)
+	assert.Equal(t, 4, line)
+	line, _ = m.Prev(4)
+	assert.Equal(t, 2, line)
+}
+
+func TestSearch_HighlightKeepsText(t *testing.T) {
+	m := New()
+	m.SetContent("\x1b[31mhello world\x1b[0m\nother")
+	apply(m, "lo w")
+
+	assert.Equal(t, "hello world\nother", test.Stripped(m.Highlight()))
+}
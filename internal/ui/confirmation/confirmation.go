@@ -1,6 +1,8 @@
 package confirmation
 
 import (
+	"cmp"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -8,6 +10,13 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+// the placeholders of the templates that the jj package doesn't define
+const (
+	FilesPlaceholder     = "$files"
+	FileCountPlaceholder = "$file_count"
 )
 
 var (
@@ -22,6 +31,19 @@ type option struct {
 	cmd        tea.Cmd
 	keyBinding key.Binding
 	altCmd     tea.Cmd
+	// onSelect runs before the command of the option is returned
+	onSelect func()
+}
+
+// run returns the command of the option, the alternative one when alt is set
+func (o option) run(alt bool) tea.Cmd {
+	if o.onSelect != nil {
+		o.onSelect()
+	}
+	if alt {
+		return o.altCmd
+	}
+	return o.cmd
 }
 
 type Styles struct {
@@ -39,6 +61,7 @@ type Model struct {
 	Styles      Styles
 	messages    []string
 	stylePrefix string
+	defaultNo   bool
 }
 
 func (m *Model) ShortHelp() []key.Binding {
@@ -80,16 +103,60 @@ func WithStylePrefix(prefix string) Option {
 // WithOption adds an option to the confirmation dialog
 func WithOption(label string, cmd tea.Cmd, keyBinding key.Binding) Option {
 	return func(m *Model) {
-		m.options = append(m.options, option{label, cmd, keyBinding, cmd})
+		m.options = append(m.options, option{label: label, cmd: cmd, keyBinding: keyBinding, altCmd: cmd})
+	}
+}
+
+// WithDefaultNo highlights the negative option initially regardless of ui.confirm_default,
+// meant for dialogs that destroy work
+func WithDefaultNo() Option {
+	return func(m *Model) {
+		m.defaultNo = true
 	}
 }
 
 func WithAltOption(label string, cmd tea.Cmd, altCmd tea.Cmd, keyBinding key.Binding) Option {
 	return func(m *Model) {
-		m.options = append(m.options, option{label, cmd, keyBinding, altCmd})
+		m.options = append(m.options, option{label: label, cmd: cmd, keyBinding: keyBinding, altCmd: altCmd})
 	}
 }
 
+// WithDontAskAgain adds an option that runs cmd and stops asking the confirmation tagged with id
+// for the rest of the session, callers skip the dialog once context.IsConfirmationSuppressed(id)
+func WithDontAskAgain(ctx *context.MainContext, id string, cmd tea.Cmd) Option {
+	return func(m *Model) {
+		m.options = append(m.options, option{
+			label:      "Yes, don't ask again",
+			cmd:        cmd,
+			keyBinding: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "yes, don't ask again")),
+			altCmd:     cmd,
+			onSelect:   func() { ctx.SuppressConfirmation(id) },
+		})
+	}
+}
+
+// Message returns the template of the named confirmation from ui.confirmations with its
+// placeholders replaced, the longer placeholders first so that e.g. $files isn't taken for $file.
+// The fallback is returned when the template is missing or renders empty.
+func Message(name string, fallback string, replacements map[string]string) string {
+	placeholders := make([]string, 0, len(replacements))
+	for placeholder := range replacements {
+		placeholders = append(placeholders, placeholder)
+	}
+	slices.SortFunc(placeholders, func(a, b string) int {
+		return cmp.Or(len(b)-len(a), strings.Compare(a, b))
+	})
+	var oldNew []string
+	for _, placeholder := range placeholders {
+		oldNew = append(oldNew, placeholder, replacements[placeholder])
+	}
+	message := strings.NewReplacer(oldNew...).Replace(config.Current.UI.Confirmations[name])
+	if strings.TrimSpace(message) == "" {
+		return fallback
+	}
+	return message
+}
+
 func (m *Model) Init() tea.Cmd {
 	return nil
 }
@@ -108,18 +175,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 				m.selected++
 			}
 		case key.Matches(msg, km.ForceApply):
-			selectedOption := m.options[m.selected]
-			return selectedOption.altCmd
+			return m.options[m.selected].run(true)
 		case key.Matches(msg, km.Apply):
-			selectedOption := m.options[m.selected]
-			return selectedOption.cmd
+			return m.options[m.selected].run(false)
 		default:
 			for _, option := range m.options {
 				if key.Matches(msg, option.keyBinding) {
-					if msg.Alt {
-						return option.altCmd
-					}
-					return option.cmd
+					return option.run(msg.Alt)
 				}
 			}
 		}
@@ -168,6 +230,11 @@ func New(messages []string, opts ...Option) *Model {
 		opt(&m)
 	}
 
+	// the negative option is always the last one
+	if defaultNo, _ := config.GetConfirmDefaultNo(config.Current); (defaultNo || m.defaultNo) && len(m.options) > 0 {
+		m.selected = len(m.options) - 1
+	}
+
 	// Set styles after options are applied so stylePrefix is considered
 	m.Styles = Styles{
 		Border:   common.DefaultPalette.GetBorder(m.getStyleKey("confirmation border"), lipgloss.RoundedBorder()),
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -100,3 +101,74 @@ func TestLegacyAddOption(t *testing.T) {
 	}
 	assert.True(t, cmdCalled)
 }
+
+func TestConfirmationDefaultOption(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+
+	options := func(extra ...Option) []Option {
+		return append([]Option{
+			WithOption("Yes", nil, key.NewBinding(key.WithKeys("y"))),
+			WithOption("No", nil, key.NewBinding(key.WithKeys("n"))),
+		}, extra...)
+	}
+
+	config.Current.UI.ConfirmDefault = "yes"
+	assert.Equal(t, 0, New([]string{"Test message"}, options()...).selected)
+	assert.Equal(t, 1, New([]string{"Test message"}, options(WithDefaultNo())...).selected)
+
+	config.Current.UI.ConfirmDefault = "no"
+	assert.Equal(t, 1, New([]string{"Test message"}, options()...).selected)
+}
+
+func TestConfirmationWithDontAskAgain(t *testing.T) {
+	var cmdCalled bool
+	testCmd := func() tea.Msg {
+		cmdCalled = true
+		return nil
+	}
+
+	ctx := &context.MainContext{}
+	model := New(
+		[]string{"Test message"},
+		WithOption("Yes", testCmd, key.NewBinding(key.WithKeys("y"))),
+		WithDontAskAgain(ctx, "test", testCmd),
+		WithOption("No", nil, key.NewBinding(key.WithKeys("n"))),
+	)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})()
+	assert.True(t, cmdCalled)
+	assert.False(t, ctx.IsConfirmationSuppressed("test"))
+
+	cmdCalled = false
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})()
+	assert.True(t, cmdCalled)
+	assert.True(t, ctx.IsConfirmationSuppressed("test"))
+	assert.False(t, ctx.IsConfirmationSuppressed("other"))
+}
+
+func TestMessage(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Confirmations = map[string]string{"restore": "Restore $file_count files ($files) instead of $file?"}
+
+	message := Message("restore", "", map[string]string{
+		"$file":              "a.txt",
+		FilesPlaceholder:     "b.txt, c.txt",
+		FileCountPlaceholder: "2",
+	})
+	assert.Equal(t, "Restore 2 files (b.txt, c.txt) instead of a.txt?", message)
+}
+
+func TestMessage_Defaults(t *testing.T) {
+	assert.Equal(t, "Are you sure you want to split the selected files?", Message("split", "", nil))
+}
+
+func TestMessage_Fallback(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Confirmations = map[string]string{"split": "$files "}
+
+	assert.Equal(t, "Split?", Message("split", "Split?", map[string]string{FilesPlaceholder: ""}))
+	assert.Equal(t, "Absorb?", Message("absorb", "Absorb?", nil))
+}
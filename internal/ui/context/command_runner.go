@@ -6,46 +6,95 @@ import (
 	"errors"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"runtime"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/askpass"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 )
 
 type CommandRunner interface {
 	RunCommandImmediate(args []string) ([]byte, error)
+	RunCommandImmediateContext(ctx context.Context, args []string) ([]byte, error)
 	RunCommandStreaming(ctx context.Context, args []string) (*StreamingCommand, error)
 	RunCommand(args []string, continuations ...tea.Cmd) tea.Cmd
 	RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd
 }
 
 type MainCommandRunner struct {
-	Location string
-	Askpass  *askpass.Server
+	Location    string
+	Askpass     *askpass.Server
+	Logger      *slog.Logger
+	AtOperation string // Operation the repository is loaded at, empty for the latest one
+	SignCommits bool   // Signs the commits written by the jj commands changing the repository, including the ones run immediately
+}
+
+func (a *MainCommandRunner) logger() *slog.Logger {
+	if a.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return a.Logger
+}
+
+// logCompleted records a finished jj invocation, at error level when it failed
+func (a *MainCommandRunner) logCompleted(level slog.Level, args []string, start time.Time, err error) {
+	if err != nil {
+		a.logger().Error("jj command failed", "args", args, "duration", time.Since(start), "error", err)
+		return
+	}
+	a.logger().Log(context.Background(), level, "jj command completed", "args", args, "duration", time.Since(start))
+}
+
+// jjArgs adds the arguments every jj invocation is run with
+func (a *MainCommandRunner) jjArgs(args []string) []string {
+	// the commands reading the repository are left alone, their snapshots aren't signed over and over
+	sign := a.SignCommits && jj.IsMutating(args)
+	return jj.WithGlobalArgs(jj.WithSigning(sign, jj.WithAtOperation(a.AtOperation, args)))
 }
 
 func (a *MainCommandRunner) RunCommandImmediate(args []string) ([]byte, error) {
-	c := exec.Command("jj", args...)
+	return a.RunCommandImmediateContext(context.Background(), args)
+}
+
+// RunCommandImmediateContext is a variant of RunCommandImmediate that can be
+// cancelled; cancelling the context interrupts jj and returns context.Canceled
+func (a *MainCommandRunner) RunCommandImmediateContext(ctx context.Context, args []string) ([]byte, error) {
+	start := time.Now()
+	c := exec.CommandContext(ctx, "jj", a.jjArgs(args)...)
 	c.Dir = a.Location
-	if output, err := c.Output(); err != nil {
+	c.Cancel = func() error {
+		return c.Process.Signal(os.Interrupt)
+	}
+	c.WaitDelay = cancelWaitDelay
+	output, err := c.Output()
+	if err != nil {
 		var exitError *exec.ExitError
-		if errors.As(err, &exitError) {
-			return nil, errors.New(string(exitError.Stderr))
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		} else if errors.As(err, &exitError) {
+			err = &common.CommandError{Args: args, ExitCode: exitError.ExitCode(), Stdout: string(output), Stderr: string(exitError.Stderr)}
 		}
+		a.logCompleted(slog.LevelDebug, args, start, err)
 		return nil, err
-	} else {
-		return bytes.Trim(output, "\n"), nil
 	}
+	a.logCompleted(slog.LevelDebug, args, start, nil)
+	return bytes.Trim(output, "\n"), nil
 }
 
+// cancelWaitDelay is how long an interrupted jj is given to exit before it is killed
+const cancelWaitDelay = 2 * time.Second
+
 func (a *MainCommandRunner) RunCommandStreaming(ctx context.Context, args []string) (*StreamingCommand, error) {
-	c := exec.CommandContext(ctx, "jj", args...)
+	a.logger().Debug("jj command streaming", "args", args)
+	c := exec.CommandContext(ctx, "jj", a.jjArgs(args)...)
 	c.Dir = a.Location
 	pipe, err := c.StdoutPipe()
 	if err != nil {
@@ -67,60 +116,71 @@ func (a *MainCommandRunner) RunCommandStreaming(ctx context.Context, args []stri
 }
 
 func (a *MainCommandRunner) RunCommand(args []string, continuations ...tea.Cmd) tea.Cmd {
-	commands := make([]tea.Cmd, 0)
-	commands = append(commands,
-		func() tea.Msg {
-			started, cancel, env := a.Askpass.NewSubprocess(strings.Join(args, " "))
-			defer cancel()
-			if !slices.Contains(args, "--color") {
-				args = append([]string{"--color", "always"}, args...)
-			}
-			c := exec.Command("jj", args...)
-			c.Dir = a.Location
-			c.Env = append(os.Environ(), env...)
-			var output bytes.Buffer
-			c.Stderr = &output
-			if err := c.Start(); err != nil {
-				return common.CommandCompletedMsg{
-					Err: err,
-				}
+	run := func() common.CommandCompletedMsg {
+		started, cancel, env := a.Askpass.NewSubprocess(strings.Join(args, " "))
+		defer cancel()
+		if !slices.Contains(args, "--color") {
+			args = append([]string{"--color", "always"}, args...)
+		}
+		c := exec.Command("jj", a.jjArgs(args)...)
+		c.Dir = a.Location
+		c.Env = append(os.Environ(), env...)
+		var output, stdout bytes.Buffer
+		c.Stderr = &output
+		c.Stdout = &stdout
+		start := time.Now()
+		if err := c.Start(); err != nil {
+			a.logCompleted(slog.LevelInfo, args, start, err)
+			return common.CommandCompletedMsg{
+				Err: err,
 			}
-			started(c.Process.Pid)
+		}
+		started(c.Process.Pid)
 
-			err := c.Wait()
-			if err != nil {
-				var exitError *exec.ExitError
-				if errors.As(err, &exitError) {
-					msg := output.String()
-					if len(env) == 0 && slices.Contains([]string{"linux", "darwin"}, runtime.GOOS) {
-						msg += "\nHint: enable ssh.hijack_askpass if you expected a password prompt (e.g. ssh passphrase)"
-					}
-					err = errors.New(msg)
+		err := c.Wait()
+		if err != nil {
+			var exitError *exec.ExitError
+			if errors.As(err, &exitError) {
+				msg := output.String()
+				if len(env) == 0 && slices.Contains([]string{"linux", "darwin"}, runtime.GOOS) {
+					msg += "\nHint: enable ssh.hijack_askpass if you expected a password prompt (e.g. ssh passphrase)"
 				}
+				err = &common.CommandError{Args: args, ExitCode: exitError.ExitCode(), Stdout: stdout.String(), Stderr: msg}
 			}
-			return common.CommandCompletedMsg{
-				Output: output.String(),
-				Err:    err,
-			}
-		})
-	commands = append(commands, continuations...)
+		}
+		a.logCompleted(slog.LevelInfo, args, start, err)
+		return common.CommandCompletedMsg{
+			Output: output.String(),
+			Stdout: stdout.String(),
+			Err:    err,
+		}
+	}
 	return tea.Batch(
 		common.CommandRunning(args),
-		tea.Sequence(commands...),
+		tea.Sequence(common.WithCommandResult(run, continuations...)...),
 	)
 }
 
 func (a *MainCommandRunner) RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd {
-	c := exec.Command("jj", args...)
+	c := exec.Command("jj", a.jjArgs(args)...)
 	errBuffer := &bytes.Buffer{}
 	c.Stderr = errBuffer
 	c.Dir = a.Location
+	start := time.Now()
 	return tea.Batch(
 		common.CommandRunning(args),
-		tea.ExecProcess(c, func(err error) tea.Msg {
+		tea.Exec(interactiveCommand{c}, func(err error) tea.Msg {
 			if err != nil {
-				return common.CommandCompletedMsg{Err: errors.New(errBuffer.String())}
+				exitCode := -1
+				var exitError *exec.ExitError
+				if errors.As(err, &exitError) {
+					exitCode = exitError.ExitCode()
+				}
+				err = &common.CommandError{Args: args, ExitCode: exitCode, Stderr: errBuffer.String()}
+				a.logCompleted(slog.LevelInfo, args, start, err)
+				return common.CommandCompletedMsg{Err: err}
 			}
+			a.logCompleted(slog.LevelInfo, args, start, nil)
 			return tea.Batch(continuation, func() tea.Msg {
 				return common.CommandCompletedMsg{Err: nil}
 			})()
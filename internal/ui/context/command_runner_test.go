@@ -0,0 +1,22 @@
+package context
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMainCommandRunner_LogsFailedCommands(t *testing.T) {
+	var buf bytes.Buffer
+	runner := &MainCommandRunner{
+		Location: t.TempDir(),
+		Logger:   slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError})),
+	}
+
+	_, err := runner.RunCommandImmediate([]string{"--no-such-flag"})
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), `msg="jj command failed"`)
+	assert.Contains(t, buf.String(), "args=[--no-such-flag]")
+}
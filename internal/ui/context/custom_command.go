@@ -21,11 +21,20 @@ type LabeledCommand interface {
 	Label() string
 }
 
+// GroupedCommand is listed under a named section of the menus, ordered by its position
+// within the section. Commands without a section fall into the default one.
+type GroupedCommand interface {
+	Section() string
+	Position() int
+}
+
 type CustomCommandBase struct {
 	Name        string
 	Desc        string   `toml:"desc"`
 	Key         []string `toml:"key"`
 	KeySequence []string `toml:"key_sequence"`
+	Group       string   `toml:"group"`
+	Order       int      `toml:"order"`
 }
 
 func (c CustomCommandBase) Binding() key.Binding {
@@ -51,6 +60,14 @@ func (c CustomCommandBase) Sequence() []key.Binding {
 	return bindings
 }
 
+func (c CustomCommandBase) Section() string {
+	return c.Group
+}
+
+func (c CustomCommandBase) Position() int {
+	return c.Order
+}
+
 func (c CustomCommandBase) Label() string {
 	if strings.TrimSpace(c.Desc) != "" {
 		return c.Desc
@@ -15,10 +15,11 @@ func TestLoad_CustomCommands(t *testing.T) {
 "resolve vscode" = { key = ["ctrl+r"],  args = ["resolve", "--tool", "vscode"], show = "interactive" }
 "update revset" = { key = ["M"],  revset = "::$change_id" }
 "sequence command" = { key_sequence = ["g", "s"], args = ["status"], desc = "status for change" }
+"show evolog" = { key = ["E"], args = ["evolog", "-r", "$change_id"], show_output = true }
 `
 	registry, err := LoadCustomCommands(content)
 	assert.NoError(t, err)
-	assert.Len(t, registry, 5)
+	assert.Len(t, registry, 6)
 
 	testCases := []struct {
 		name        string
@@ -89,6 +90,16 @@ func TestLoad_CustomCommands(t *testing.T) {
 				assert.Len(t, seq, 2)
 			},
 		},
+		{
+			name:        "show output command",
+			commandName: "show evolog",
+			testFunc: func(t *testing.T, cmd CustomCommand) {
+				runCmd, ok := cmd.(CustomRunCommand)
+				assert.True(t, ok, "Command should be CustomRunCommand")
+				assert.Equal(t, []string{"evolog", "-r", "$change_id"}, runCmd.Args)
+				assert.True(t, runCmd.ShowOutput)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
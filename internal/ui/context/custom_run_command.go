@@ -13,8 +13,9 @@ import (
 
 type CustomRunCommand struct {
 	CustomCommandBase
-	Args []string          `toml:"args"`
-	Show config.ShowOption `toml:"show"`
+	Args       []string          `toml:"args"`
+	Show       config.ShowOption `toml:"show"`
+	ShowOutput bool              `toml:"show_output"`
 }
 
 func (c CustomRunCommand) IsApplicableTo(item SelectedItem) bool {
@@ -46,6 +47,17 @@ func (c CustomRunCommand) Description(ctx *MainContext) string {
 
 func (c CustomRunCommand) Prepare(ctx *MainContext) tea.Cmd {
 	replacements := ctx.CreateReplacements()
+	postCommand, _ := config.Current.PostCommand.GetCustomCommand()
+	afterCommand := common.AfterCommand(postCommand)
+	if c.ShowOutput {
+		args := jj.TemplatedArgs(c.Args, replacements)
+		showOutput := common.OnCommandResult(func(result common.CommandCompletedMsg) tea.Cmd {
+			return func() tea.Msg {
+				return common.ShowOutputMsg{Title: fmt.Sprintf("jj %s", strings.Join(args, " ")), Output: result.CombinedOutput()}
+			}
+		})
+		return ctx.RunCommand(args, showOutput, afterCommand)
+	}
 	switch c.Show {
 	case config.ShowOptionDiff:
 		return func() tea.Msg {
@@ -53,8 +65,8 @@ func (c CustomRunCommand) Prepare(ctx *MainContext) tea.Cmd {
 			return common.ShowDiffMsg(output)
 		}
 	case config.ShowOptionInteractive:
-		return ctx.RunInteractiveCommand(jj.TemplatedArgs(c.Args, replacements), common.Refresh)
+		return ctx.RunInteractiveCommand(jj.TemplatedArgs(c.Args, replacements), afterCommand)
 	default:
-		return ctx.RunCommand(jj.TemplatedArgs(c.Args, replacements), common.Refresh)
+		return ctx.RunCommand(jj.TemplatedArgs(c.Args, replacements), afterCommand)
 	}
 }
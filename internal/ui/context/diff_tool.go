@@ -0,0 +1,45 @@
+package context
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// RunDiffTool runs the named diff tool against the selected revision or file
+func (ctx *MainContext) RunDiffTool(name string) tea.Cmd {
+	tool, ok := config.Current.Diff.GetTool(name)
+	if !ok {
+		return func() tea.Msg {
+			return common.CommandCompletedMsg{Err: fmt.Errorf("diff tool %q is not configured", name)}
+		}
+	}
+
+	replacements := ctx.CreateReplacements()
+	replacements[jj.WidthPlaceholder] = strconv.Itoa(ctx.ScreenWidth)
+	var templated []string
+	for _, arg := range tool.Command {
+		// drop the file argument when a revision is selected so that the whole revision is diffed
+		if _, hasFile := replacements[jj.FilePlaceholder]; !hasFile && arg == jj.FilePlaceholder {
+			continue
+		}
+		templated = append(templated, arg)
+	}
+	args := jj.TemplatedArgs(templated, replacements)
+
+	if tool.Show == config.ShowOptionInteractive {
+		// diffs don't change the repository, so they are shown in the dry-run mode too
+		return ctx.CommandRunner.RunInteractiveCommand(args, common.Refresh)
+	}
+	return func() tea.Msg {
+		output, err := ctx.RunCommandImmediate(args)
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		return common.ShowDiffMsg(output)
+	}
+}
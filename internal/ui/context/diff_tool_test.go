@@ -0,0 +1,29 @@
+package context_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
+)
+
+func TestRunDiffTool_ReportsFailure(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Diff.Tools = map[string]config.DiffTool{"stat": {Command: []string{"diff", "--stat", "-r", "$change_id"}}}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect([]string{"diff", "--stat", "-r", "abc"}).SetError(errors.New("Error: No such revision"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+
+	msg, ok := ctx.RunDiffTool("stat")().(common.CommandCompletedMsg)
+	assert.True(t, ok)
+	assert.EqualError(t, msg.Err, "Error: No such revision")
+}
@@ -0,0 +1,82 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// RunCommand runs a command changing the repository. In the dry-run mode the command line of a
+// command that would change the repository is shown instead, the continuations still run so that the operation asking for the command is
+// closed as usual. In the read-only mode, or while a past operation is viewed, the command fails
+// without being run when it would change the repository.
+func (ctx *MainContext) RunCommand(args []string, continuations ...tea.Cmd) tea.Cmd {
+	if err := ctx.refusal(args); err != nil {
+		return ctx.refused(args, err, continuations...)
+	}
+	if !ctx.DryRun || !jj.IsMutating(args) {
+		return ctx.CommandRunner.RunCommand(args, continuations...)
+	}
+	return ctx.dryRun(args, continuations...)
+}
+
+// RunInteractiveCommand is the interactive variant of RunCommand
+func (ctx *MainContext) RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd {
+	if err := ctx.refusal(args); err != nil {
+		// a failed interactive command doesn't run its continuation either
+		return ctx.refused(args, err)
+	}
+	if !ctx.DryRun || !jj.IsMutating(args) {
+		return ctx.CommandRunner.RunInteractiveCommand(args, continuation)
+	}
+	return ctx.dryRun(args, continuation)
+}
+
+// refusal returns the reason the command can't change the repository now, nil when it can or
+// when it only reads the repository
+func (ctx *MainContext) refusal(args []string) error {
+	if ctx.ReadOnly && jj.IsMutating(args) {
+		return fmt.Errorf("read-only mode, %s is not run", commandLine(args))
+	}
+	if ctx.AtOperation != "" && jj.IsMutating(args) {
+		return fmt.Errorf("viewing operation %s, %s is not run", ctx.AtOperation, commandLine(args))
+	}
+	return nil
+}
+
+// refused fails the command with the given error, the continuations run as they do after a failed command
+func (ctx *MainContext) refused(args []string, err error, continuations ...tea.Cmd) tea.Cmd {
+	run := func() common.CommandCompletedMsg {
+		return common.CommandCompletedMsg{Err: err}
+	}
+	return tea.Batch(
+		common.CommandRunning(args),
+		tea.Sequence(common.WithCommandResult(run, continuations...)...),
+	)
+}
+
+func (ctx *MainContext) dryRun(args []string, continuations ...tea.Cmd) tea.Cmd {
+	run := func() common.CommandCompletedMsg {
+		return common.CommandCompletedMsg{Output: "dry run: " + commandLine(args)}
+	}
+	return tea.Batch(
+		common.CommandRunning(args),
+		tea.Sequence(common.WithCommandResult(run, continuations...)...),
+	)
+}
+
+// commandLine returns the jj command line running the arguments, quoting the ones a shell would split
+func commandLine(args []string) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, "jj")
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`|&;<>()*?[]#~!{}") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,12 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandLine(t *testing.T) {
+	assert.Equal(t, "jj bookmark set -r abc main", commandLine([]string{"bookmark", "set", "-r", "abc", "main"}))
+	assert.Equal(t, `jj describe -m 'it'\''s done' ''`, commandLine([]string{"describe", "-m", "it's done", ""}))
+}
@@ -0,0 +1,96 @@
+package context
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/clipboard"
+)
+
+// interactiveProcesses keeps track of the processes that have been handed the
+// terminal, so that they can be terminated when jjui is shutting down.
+var interactiveProcesses = &processTracker{running: make(map[*exec.Cmd]struct{})}
+
+var errShuttingDown = errors.New("jjui is shutting down")
+
+type processTracker struct {
+	mu           sync.Mutex
+	running      map[*exec.Cmd]struct{}
+	shuttingDown bool
+}
+
+func (t *processTracker) start(cmd *exec.Cmd) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shuttingDown {
+		return errShuttingDown
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	t.running[cmd] = struct{}{}
+	return nil
+}
+
+func (t *processTracker) done(cmd *exec.Cmd) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.running, cmd)
+}
+
+func (t *processTracker) terminate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.shuttingDown = true
+	for cmd := range t.running {
+		_ = cmd.Process.Kill()
+	}
+}
+
+// RunInteractive runs the command to completion and makes sure it is killed
+// if jjui is asked to shut down in the meantime.
+func RunInteractive(cmd *exec.Cmd) error {
+	if err := interactiveProcesses.start(cmd); err != nil {
+		return err
+	}
+	defer interactiveProcesses.done(cmd)
+	return cmd.Wait()
+}
+
+// TerminateInteractiveProcesses kills the interactive processes that are still
+// running and prevents new ones from being started.
+func TerminateInteractiveProcesses() {
+	interactiveProcesses.terminate()
+}
+
+// interactiveCommand is a tea.ExecCommand that is tracked while it is running
+type interactiveCommand struct {
+	*exec.Cmd
+}
+
+var _ tea.ExecCommand = interactiveCommand{}
+
+func (c interactiveCommand) Run() error {
+	return RunInteractive(c.Cmd)
+}
+
+func (c interactiveCommand) SetStdin(r io.Reader) {
+	if c.Stdin == nil {
+		c.Stdin = r
+	}
+}
+
+func (c interactiveCommand) SetStdout(w io.Writer) {
+	if c.Stdout == nil {
+		c.Stdout = clipboard.ProcessOutput(w)
+	}
+}
+
+func (c interactiveCommand) SetStderr(w io.Writer) {
+	if c.Stderr == nil {
+		c.Stderr = w
+	}
+}
@@ -0,0 +1,33 @@
+package context
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessTracker_TerminateKillsRunningProcesses(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep is not available")
+	}
+	tracker := &processTracker{running: make(map[*exec.Cmd]struct{})}
+	cmd := exec.Command("sleep", "10")
+	assert.NoError(t, tracker.start(cmd))
+
+	finished := make(chan error, 1)
+	go func() {
+		finished <- cmd.Wait()
+		tracker.done(cmd)
+	}()
+	tracker.terminate()
+
+	select {
+	case err := <-finished:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("process was not terminated")
+	}
+	assert.ErrorIs(t, tracker.start(exec.Command("sleep", "10")), errShuttingDown)
+}
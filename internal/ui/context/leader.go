@@ -1,10 +1,14 @@
 package context
 
 import (
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/idursun/jjui/internal/config"
 )
 
 type LeaderMap = map[string]*Leader
@@ -14,13 +18,22 @@ type Leader struct {
 	Send    []string
 	Context []string
 	Nest    LeaderMap
+	Action  string // Name of the built-in action the entry invokes, resolved into Send
+	Command string // Name of the custom command the entry runs
+	Order   int    // Position of the entry in the menu, entries with the same order are sorted by key
 }
 
+// LoadLeader builds the leader menu from the [leader] table, where each entry either sends keys,
+// invokes a built-in action by the path of its binding under [keys] or runs a custom command.
+// The actions and commands that don't exist are left unresolved and reported by LeaderWarnings.
 func LoadLeader(content string) (LeaderMap, error) {
 	type leaderTomlEntry struct {
 		Help    string
 		Send    []string
 		Context []string
+		Action  string
+		Command string
+		Order   int
 	}
 	type leaderToml struct {
 		Leader map[string]leaderTomlEntry
@@ -39,8 +52,23 @@ func LoadLeader(content string) (LeaderMap, error) {
 			if i == len(ks)-1 {
 				m.Send = v.Send
 				m.Context = v.Context
-				if len(v.Help) > 0 {
-					m.Bind.SetHelp(k, v.Help)
+				m.Action = v.Action
+				m.Command = v.Command
+				m.Order = v.Order
+				help := v.Help
+				if v.Action != "" {
+					if presses, binding, ok := config.ActionKeys(config.Current.GetKeyMap(), v.Action); ok {
+						m.Send = presses
+						if help == "" {
+							help = binding.Help().Desc
+						}
+					}
+				}
+				if help == "" {
+					help = v.Command
+				}
+				if len(help) > 0 {
+					m.Bind.SetHelp(k, help)
 				}
 			}
 			at = m.Nest
@@ -65,3 +93,34 @@ func checkExists(at LeaderMap, k string) *Leader {
 		return m
 	}
 }
+
+// Unresolved reports whether the entry refers to an action or a custom command that doesn't exist
+func (l *Leader) Unresolved(commands map[string]CustomCommand) bool {
+	if l.Action != "" && len(l.Send) == 0 {
+		return true
+	}
+	_, ok := commands[l.Command]
+	return l.Command != "" && !ok
+}
+
+// LeaderWarnings describes the entries of the leader menu referring to actions or custom commands
+// that don't exist, so that they are reported instead of silently doing nothing
+func LeaderWarnings(leader LeaderMap, commands map[string]CustomCommand) []string {
+	var warnings []string
+	var walk func(prefix string, at LeaderMap)
+	walk = func(prefix string, at LeaderMap) {
+		for _, k := range slices.Sorted(maps.Keys(at)) {
+			l := at[k]
+			if l.Unresolved(commands) {
+				if l.Action != "" {
+					warnings = append(warnings, fmt.Sprintf("leader.%s: unknown action %q", prefix+k, l.Action))
+				} else {
+					warnings = append(warnings, fmt.Sprintf("leader.%s: unknown custom command %q", prefix+k, l.Command))
+				}
+			}
+			walk(prefix+k, l.Nest)
+		}
+	}
+	walk("", leader)
+	return warnings
+}
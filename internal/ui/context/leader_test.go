@@ -2,6 +2,8 @@ package context
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 const exampleLeaderToml = `
@@ -79,3 +81,40 @@ func TestLoadLeader(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadLeader_ActionsAndCommands(t *testing.T) {
+	lm, err := LoadLeader(`
+[leader.a]
+action = "abandon"
+order = 2
+
+[leader.p]
+help = "Push"
+action = "git.push"
+order = 1
+
+[leader.x]
+command = "show diff"
+
+[leader.u]
+action = "no_such_action"
+
+[leader.v]
+command = "no such command"
+`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"a"}, lm["a"].Send)
+	assert.Equal(t, "abandon", lm["a"].Bind.Help().Desc)
+	assert.Equal(t, 2, lm["a"].Order)
+	assert.Equal(t, []string{"g", "p"}, lm["p"].Send)
+	assert.Equal(t, "Push", lm["p"].Bind.Help().Desc)
+	assert.Equal(t, "show diff", lm["x"].Bind.Help().Desc)
+
+	commands := map[string]CustomCommand{"show diff": CustomRunCommand{}}
+	assert.False(t, lm["x"].Unresolved(commands))
+	assert.Equal(t, []string{
+		`leader.u: unknown action "no_such_action"`,
+		`leader.v: unknown custom command "no such command"`,
+	}, LeaderWarnings(lm, commands))
+}
@@ -1,6 +1,9 @@
 package context
 
 import (
+	"log/slog"
+	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
@@ -42,6 +45,17 @@ func (s SelectedFile) Equal(other SelectedItem) bool {
 	return false
 }
 
+type SelectedBookmark struct {
+	Name string
+}
+
+func (s SelectedBookmark) Equal(other SelectedItem) bool {
+	if o, ok := other.(SelectedBookmark); ok {
+		return s.Name == o.Name
+	}
+	return false
+}
+
 type SelectedOperation struct {
 	OperationId string
 }
@@ -58,13 +72,24 @@ type MainContext struct {
 	SelectedItem   SelectedItem   // Single item where cursor is hover.
 	CheckedItems   []SelectedItem // Items checked ✓ by the user.
 	Location       string
+	Workspace      string // Name of the workspace at Location, empty when the repository has a single workspace
 	CustomCommands map[string]CustomCommand
 	Leader         LeaderMap
 	JJConfig       *config.JJConfig
 	DefaultRevset  string
 	CurrentRevset  string
 	Histories      *config.Histories
-	ScreenWidth    int // Current screen width for $width substitution
+	State          *config.State
+	Logger         *slog.Logger
+	ScreenWidth    int           // Current screen width for $width substitution
+	IdType         config.IdType // Identifier shown first in the revisions view
+	ReadOnly       bool          // Disables the operations that change the repository
+	DarkBackground bool          // Whether the palette is resolved for a dark terminal background
+	AtOperation    string        // Operation the repository is viewed at, empty for the latest one
+	DryRun         bool          // Shows the jj commands that change the repository instead of running them
+	SignCommits    bool          // Signs the commits written by the jj commands, set with SetSignCommits
+	// suppressedConfirmations holds the ids of the confirmations not asked again in this session
+	suppressedConfirmations map[string]bool
 }
 
 func NewAppContext(location string, aps *askpass.Server) *MainContext {
@@ -75,6 +100,8 @@ func NewAppContext(location string, aps *askpass.Server) *MainContext {
 		},
 		Location:  location,
 		Histories: config.NewHistories(),
+		State:     config.LoadState(),
+		Logger:    slog.New(slog.DiscardHandler),
 	}
 
 	m.JJConfig = &config.JJConfig{}
@@ -84,6 +111,139 @@ func NewAppContext(location string, aps *askpass.Server) *MainContext {
 	return m
 }
 
+// SetLogger sets the logger used for troubleshooting, including the one
+// recording the jj commands
+func (ctx *MainContext) SetLogger(logger *slog.Logger) {
+	ctx.Logger = logger
+	if runner, ok := ctx.CommandRunner.(*MainCommandRunner); ok {
+		runner.Logger = logger
+	}
+}
+
+// SetAtOperation makes the following jj commands load the repository as it was at the
+// given operation, an empty id goes back to the latest operation
+func (ctx *MainContext) SetAtOperation(operationId string) {
+	ctx.AtOperation = operationId
+	if runner, ok := ctx.CommandRunner.(*MainCommandRunner); ok {
+		runner.AtOperation = operationId
+	}
+}
+
+// SetSignCommits makes the command runner sign the commits every following jj command writes
+func (ctx *MainContext) SetSignCommits(sign bool) {
+	ctx.SignCommits = sign
+	if runner, ok := ctx.CommandRunner.(*MainCommandRunner); ok {
+		runner.SignCommits = sign
+	}
+}
+
+// SwitchLocation binds the context to the repository at the given location,
+// dropping the state that belongs to the previous repository
+func (ctx *MainContext) SwitchLocation(location string) {
+	ctx.Location = location
+	ctx.Workspace = ""
+	if runner, ok := ctx.CommandRunner.(*MainCommandRunner); ok {
+		runner.Location = location
+	}
+	ctx.SelectedItem = nil
+	ctx.ClearCheckedItems(nil)
+	ctx.SetAtOperation("")
+
+	previousLogRevset := ctx.JJConfig.Revsets.Log
+	ctx.JJConfig = &config.JJConfig{}
+	if output, err := ctx.RunCommandImmediate(jj.ConfigListAll()); err == nil {
+		ctx.JJConfig, _ = config.DefaultConfig(output)
+	}
+	// the default revset only follows the repository when it was not set explicitly
+	if ctx.DefaultRevset == previousLogRevset {
+		ctx.DefaultRevset = ctx.JJConfig.Revsets.Log
+	}
+	ctx.CurrentRevset = ctx.DefaultRevset
+	if ctx.State != nil {
+		ctx.State.AddRecentRepository(location)
+	}
+}
+
+// SuppressConfirmation stops asking the confirmation with the given id until jjui is restarted
+func (ctx *MainContext) SuppressConfirmation(id string) {
+	if ctx.suppressedConfirmations == nil {
+		ctx.suppressedConfirmations = make(map[string]bool)
+	}
+	ctx.suppressedConfirmations[id] = true
+}
+
+// IsConfirmationSuppressed reports whether the confirmation with the given id is skipped
+func (ctx *MainContext) IsConfirmationSuppressed(id string) bool {
+	return ctx.suppressedConfirmations[id]
+}
+
+// Workspace is a workspace of the repository and the directory it is checked out in
+type Workspace struct {
+	Name string
+	Root string
+}
+
+// Workspaces lists the workspaces of the repository, the roots are only looked up
+// when there is more than one workspace
+func (ctx *MainContext) Workspaces() ([]Workspace, error) {
+	output, err := ctx.RunCommandImmediate(jj.WorkspaceList())
+	if err != nil {
+		return nil, err
+	}
+	var workspaces []Workspace
+	for name := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			workspaces = append(workspaces, Workspace{Name: name})
+		}
+	}
+	if len(workspaces) < 2 {
+		return workspaces, nil
+	}
+	for i := range workspaces {
+		root, err := ctx.RunCommandImmediate(jj.WorkspaceRoot(workspaces[i].Name))
+		if err != nil {
+			return nil, err
+		}
+		workspaces[i].Root = filepath.Clean(strings.TrimSpace(string(root)))
+	}
+	return workspaces, nil
+}
+
+// CurrentWorkspace returns the name of the workspace checked out at Location,
+// empty when there is a single workspace
+func (ctx *MainContext) CurrentWorkspace(workspaces []Workspace) string {
+	if len(workspaces) < 2 {
+		return ""
+	}
+	location := filepath.Clean(ctx.Location)
+	for _, w := range workspaces {
+		if w.Root == location {
+			return w.Name
+		}
+	}
+	return ""
+}
+
+// RecentRepositories returns the recently opened repositories other than the
+// current one, forgetting the ones that no longer exist
+func (ctx *MainContext) RecentRepositories() []string {
+	if ctx.State == nil {
+		return nil
+	}
+	var repositories []string
+	for _, location := range slices.Clone(ctx.State.RecentRepositories) {
+		if location == ctx.Location {
+			continue
+		}
+		if s, err := os.Stat(filepath.Join(location, ".jj")); err != nil || !s.IsDir() {
+			ctx.State.RemoveRecentRepository(location)
+			continue
+		}
+		repositories = append(repositories, location)
+	}
+	return repositories
+}
+
 func (ctx *MainContext) ClearCheckedItems(ofType reflect.Type) {
 	ctx.CheckedItems = slices.DeleteFunc(ctx.CheckedItems, func(i SelectedItem) bool {
 		return ofType == nil || ofType == reflect.TypeOf(i)
@@ -0,0 +1,84 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/idursun/jjui/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMainContext_RecentRepositories(t *testing.T) {
+	current := t.TempDir()
+	other := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(other, ".jj"), 0755))
+	removed := filepath.Join(t.TempDir(), "removed")
+
+	ctx := &MainContext{
+		Location: current,
+		State:    &config.State{RecentRepositories: []string{current, removed, other}},
+	}
+
+	assert.Equal(t, []string{other}, ctx.RecentRepositories())
+	assert.Equal(t, []string{current, other}, ctx.State.RecentRepositories)
+}
+
+func TestMainContext_SwitchLocation(t *testing.T) {
+	runner := &MainCommandRunner{Location: "/old"}
+	ctx := &MainContext{
+		CommandRunner: runner,
+		Location:      "/old",
+		JJConfig:      &config.JJConfig{},
+		SelectedItem:  SelectedRevision{ChangeId: "abc"},
+		CheckedItems:  []SelectedItem{SelectedRevision{ChangeId: "def"}},
+		CurrentRevset: "mine()",
+		State:         &config.State{},
+		AtOperation:   "abc123",
+	}
+
+	ctx.SwitchLocation("/new")
+
+	assert.Equal(t, "/new", ctx.Location)
+	assert.Equal(t, "/new", runner.Location)
+	assert.Nil(t, ctx.SelectedItem)
+	assert.Empty(t, ctx.CheckedItems)
+	assert.Equal(t, ctx.DefaultRevset, ctx.CurrentRevset)
+	assert.Equal(t, []string{"/new"}, ctx.State.RecentRepositories)
+	assert.Empty(t, ctx.AtOperation)
+}
+
+func TestMainContext_SetAtOperation(t *testing.T) {
+	runner := &MainCommandRunner{Location: "/repo"}
+	ctx := &MainContext{CommandRunner: runner, Location: "/repo"}
+
+	ctx.SetAtOperation("abc123")
+	assert.Equal(t, "abc123", ctx.AtOperation)
+	assert.Equal(t, []string{"--at-operation", "abc123", "log"}, runner.jjArgs([]string{"log"}))
+
+	ctx.SetAtOperation("")
+	assert.Empty(t, ctx.AtOperation)
+	assert.Equal(t, []string{"log"}, runner.jjArgs([]string{"log"}))
+}
+
+func TestMainContext_SetSignCommits(t *testing.T) {
+	runner := &MainCommandRunner{Location: "/repo"}
+	ctx := &MainContext{CommandRunner: runner, Location: "/repo"}
+
+	ctx.SetSignCommits(true)
+	assert.True(t, ctx.SignCommits)
+	assert.Equal(t, []string{"--config", "signing.behavior=force", "describe", "-r", "@"}, runner.jjArgs([]string{"describe", "-r", "@"}))
+
+	assert.Equal(t, []string{"log"}, runner.jjArgs([]string{"log"}))
+
+	ctx.SetSignCommits(false)
+	assert.False(t, ctx.SignCommits)
+	assert.Equal(t, []string{"describe", "-r", "@"}, runner.jjArgs([]string{"describe", "-r", "@"}))
+}
+
+func TestMainContext_CurrentWorkspace(t *testing.T) {
+	ctx := &MainContext{Location: "/repo/"}
+
+	assert.Empty(t, ctx.CurrentWorkspace([]Workspace{{Name: "default"}}))
+	assert.Equal(t, "default", ctx.CurrentWorkspace([]Workspace{{Name: "second", Root: "/second"}, {Name: "default", Root: "/repo"}}))
+}
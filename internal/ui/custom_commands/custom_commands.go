@@ -18,6 +18,7 @@ import (
 
 type item struct {
 	name        string
+	group       string
 	desc        string
 	command     tea.Cmd
 	key         key.Binding
@@ -37,7 +38,7 @@ func (i item) ShortCut() string {
 }
 
 func (i item) FilterValue() string {
-	return i.name
+	return i.group + " " + i.name
 }
 
 func (i item) Title() string {
@@ -45,22 +46,96 @@ func (i item) Title() string {
 }
 
 func (i item) Description() string {
+	if i.group != "" {
+		return i.group + ": " + i.desc
+	}
 	return i.desc
 }
 
 var _ common.Model = (*Model)(nil)
 
-// SortedCustomCommands returns commands ordered by name for deterministic iteration.
-func SortedCustomCommands(ctx *context.MainContext) []context.CustomCommand {
+// RanMsg reports the custom command that was run last so that it can be repeated
+type RanMsg struct {
+	Command context.CustomCommand
+}
+
+// Run prepares the command for the current selection. Revset commands only move
+// around the log, the others are reported with RanMsg when they run.
+func Run(ctx *context.MainContext, command context.CustomCommand) tea.Cmd {
+	cmd := command.Prepare(ctx)
+	if _, ok := command.(context.CustomRevsetCommand); ok || cmd == nil {
+		return cmd
+	}
+	return tea.Batch(cmd, func() tea.Msg {
+		return RanMsg{Command: command}
+	})
+}
+
+// CommandGroup is a section of custom commands, the default section has no name
+type CommandGroup struct {
+	Name     string
+	Names    []string
+	Commands []context.CustomCommand
+}
+
+func placement(command context.CustomCommand) (string, int) {
+	if grouped, ok := command.(context.GroupedCommand); ok {
+		return grouped.Section(), grouped.Position()
+	}
+	return "", 0
+}
+
+// GroupedCustomCommands returns the custom commands by section. The default section comes
+// first, the named ones follow ordered by the lowest order of their commands and then by name.
+// Commands are ordered by their order and then by name within a section.
+func GroupedCustomCommands(ctx *context.MainContext) []CommandGroup {
 	names := make([]string, 0, len(ctx.CustomCommands))
 	for name := range ctx.CustomCommands {
 		names = append(names, name)
 	}
-	sort.Strings(names)
+	sort.SliceStable(names, func(i, j int) bool {
+		_, oi := placement(ctx.CustomCommands[names[i]])
+		_, oj := placement(ctx.CustomCommands[names[j]])
+		if oi != oj {
+			return oi < oj
+		}
+		return names[i] < names[j]
+	})
 
-	commands := make([]context.CustomCommand, 0, len(names))
+	var groups []CommandGroup
+	index := make(map[string]int)
 	for _, name := range names {
-		commands = append(commands, ctx.CustomCommands[name])
+		command := ctx.CustomCommands[name]
+		section, _ := placement(command)
+		i, ok := index[section]
+		if !ok {
+			i = len(groups)
+			index[section] = i
+			groups = append(groups, CommandGroup{Name: section})
+		}
+		groups[i].Names = append(groups[i].Names, name)
+		groups[i].Commands = append(groups[i].Commands, command)
+	}
+	// names are visited by order so the groups are already ordered by their lowest order
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Name == "" || groups[j].Name == "" {
+			return groups[i].Name == ""
+		}
+		_, oi := placement(groups[i].Commands[0])
+		_, oj := placement(groups[j].Commands[0])
+		if oi != oj {
+			return oi < oj
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// SortedCustomCommands returns the commands in the order they are listed in the menus
+func SortedCustomCommands(ctx *context.MainContext) []context.CustomCommand {
+	var commands []context.CustomCommand
+	for _, group := range GroupedCustomCommands(ctx) {
+		commands = append(commands, group.Commands...)
 	}
 	return commands
 }
@@ -85,6 +160,10 @@ func (m *Model) FullHelp() [][]key.Binding {
 	return [][]key.Binding{m.ShortHelp()}
 }
 
+func (m *Model) Scroll(delta int) tea.Cmd {
+	return m.menu.Scroll(delta)
+}
+
 func (m *Model) Init() tea.Cmd {
 	return nil
 }
@@ -133,21 +212,20 @@ func (m *Model) View() string {
 func NewModel(ctx *context.MainContext) *Model {
 	var items []list.Item
 
-	for name, command := range ctx.CustomCommands {
-		if command.IsApplicableTo(ctx.SelectedItem) {
-			cmd := command.Prepare(ctx)
+	for _, group := range GroupedCustomCommands(ctx) {
+		for i, command := range group.Commands {
+			if !command.IsApplicableTo(ctx.SelectedItem) {
+				continue
+			}
+			cmd := Run(ctx, command)
 			desc := command.Description(ctx)
 			if lc, ok := command.(context.LabeledCommand); ok {
 				desc = lc.Label()
 			}
-			items = append(items, item{name: name, desc: desc, command: cmd, key: command.Binding(), keySequence: command.Sequence()})
+			items = append(items, item{name: group.Names[i], group: group.Name, desc: desc, command: cmd, key: command.Binding(), keySequence: command.Sequence()})
 		}
 	}
 
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].(item).name < items[j].(item).name
-	})
-
 	keyMap := config.Current.GetKeyMap()
 	menu := menu.NewMenu(items, keyMap, menu.WithStylePrefix("custom_commands"))
 	menu.Title = "Custom Commands"
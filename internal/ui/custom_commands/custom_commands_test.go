@@ -0,0 +1,65 @@
+package customcommands
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_ReportsTheCommand(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	command := &stubCommand{CustomCommandBase: context.CustomCommandBase{Name: "stub"}, applicable: true}
+
+	var msgs []tea.Msg
+	test.SimulateModel(&msgRecorder{msgs: &msgs}, Run(ctx, command))
+	assert.Contains(t, msgs, executedMsg{name: "stub"})
+	assert.Contains(t, msgs, RanMsg{Command: command})
+}
+
+func TestRun_DoesNotReportRevsetCommands(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+	command := context.CustomRevsetCommand{Revset: "$change_id::"}
+
+	cmd := Run(ctx, command)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, common.UpdateRevSetMsg("abc::"), cmd())
+}
+
+type msgRecorder struct {
+	msgs *[]tea.Msg
+}
+
+func (r *msgRecorder) Update(msg tea.Msg) tea.Cmd {
+	*r.msgs = append(*r.msgs, msg)
+	return nil
+}
+
+func TestGroupedCustomCommands(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	registry, err := context.LoadCustomCommands(`
+[custom_commands]
+"zeta" = { args = ["status"] }
+"alpha" = { args = ["status"] }
+"push" = { args = ["git", "push"], group = "git", order = 2 }
+"fetch" = { args = ["git", "fetch"], group = "git", order = 1 }
+"rebase" = { args = ["rebase"], group = "history" }
+`)
+	assert.NoError(t, err)
+	ctx.CustomCommands = registry
+
+	groups := GroupedCustomCommands(ctx)
+	var names [][]string
+	var sections []string
+	for _, group := range groups {
+		sections = append(sections, group.Name)
+		names = append(names, group.Names)
+	}
+	assert.Equal(t, []string{"", "history", "git"}, sections)
+	assert.Equal(t, [][]string{{"alpha", "zeta"}, {"rebase"}, {"fetch", "push"}}, names)
+	assert.Len(t, SortedCustomCommands(ctx), 5)
+}
@@ -18,6 +18,12 @@ type SequenceEntry struct {
 	Remaining []string
 }
 
+// sequenceGroup collects the entries that are reachable by pressing the same next key.
+type sequenceGroup struct {
+	key     string
+	entries []SequenceEntry
+}
+
 type SequenceCandidate struct {
 	Command context.CustomCommand
 	Seq     []key.Binding
@@ -44,6 +50,7 @@ type SequenceOverlay struct {
 	shortcutStyle lipgloss.Style
 	matchedStyle  lipgloss.Style
 	textStyle     lipgloss.Style
+	dimmedStyle   lipgloss.Style
 	candidates    []SequenceCandidate
 	started       time.Time
 	typed         []string
@@ -58,6 +65,7 @@ func NewSequenceOverlay(ctx *context.MainContext) *SequenceOverlay {
 		shortcutStyle: common.DefaultPalette.Get("shortcut"),
 		matchedStyle:  common.DefaultPalette.Get("matched"),
 		textStyle:     common.DefaultPalette.Get("text"),
+		dimmedStyle:   common.DefaultPalette.Get("dimmed"),
 	}
 }
 
@@ -147,21 +155,44 @@ func (s *SequenceOverlay) HandleTimeout(msg SequenceTimeoutMsg) SequenceResult {
 	return s.handleTimeout(msg)
 }
 
-func (s *SequenceOverlay) View() string {
-	var view strings.Builder
-	for i, it := range s.items {
-		view.WriteString(s.matchedStyle.Render(s.prefix))
+// groups arranges the entries by the key that has to be pressed next, in the order the keys first appear.
+func (s *SequenceOverlay) groups() []sequenceGroup {
+	var groups []sequenceGroup
+	index := make(map[string]int)
+	for _, it := range s.items {
 		if len(it.Remaining) == 0 {
 			continue
 		}
-		for _, r := range it.Remaining {
-			view.WriteString(" → ")
-			view.WriteString(s.shortcutStyle.Render(r))
+		next := it.Remaining[0]
+		i, ok := index[next]
+		if !ok {
+			i = len(groups)
+			index[next] = i
+			groups = append(groups, sequenceGroup{key: next})
 		}
-		view.WriteString(" ")
-		view.WriteString(it.Name)
-		if i < len(s.items)-1 {
-			view.WriteString("\n")
+		groups[i].entries = append(groups[i].entries, it)
+	}
+	return groups
+}
+
+// View lists the keys that can be pressed next and the commands each of them leads to.
+// Commands that need more keys after the next one show the rest of their sequence dimmed.
+func (s *SequenceOverlay) View() string {
+	var view strings.Builder
+	view.WriteString(s.matchedStyle.Render(s.prefix))
+	view.WriteString(s.dimmedStyle.Render(" …"))
+	for _, g := range s.groups() {
+		view.WriteString("\n")
+		view.WriteString(s.shortcutStyle.Render(g.key))
+		view.WriteString(s.dimmedStyle.Render(" → "))
+		for i, it := range g.entries {
+			if i > 0 {
+				view.WriteString(s.dimmedStyle.Render(", "))
+			}
+			view.WriteString(s.textStyle.Render(it.Name))
+			if len(it.Remaining) > 1 {
+				view.WriteString(s.dimmedStyle.Render(" (" + strings.Join(it.Remaining[1:], " ") + ")"))
+			}
 		}
 	}
 	w := s.Parent.Frame.Dx()
@@ -191,7 +222,7 @@ func (s *SequenceOverlay) advance(msg tea.KeyMsg) ([]SequenceCandidate, Sequence
 		if key.Matches(msg, cand.Seq[cand.Index]) {
 			matched = true
 			if cand.Index+1 == len(cand.Seq) {
-				cmd := cand.Command.Prepare(s.ctx)
+				cmd := Run(s.ctx, cand.Command)
 				s.reset()
 				return nil, SequenceResult{Cmd: cmd, Handled: true, Active: false}
 			}
@@ -214,7 +245,7 @@ func (s *SequenceOverlay) maybeStart(msg tea.KeyMsg, now time.Time) SequenceResu
 		}
 		if key.Matches(msg, seq[0]) {
 			if len(seq) == 1 {
-				return SequenceResult{Cmd: command.Prepare(s.ctx), Handled: true, Active: false}
+				return SequenceResult{Cmd: Run(s.ctx, command), Handled: true, Active: false}
 			}
 			starters = append(starters, SequenceCandidate{
 				Command: command,
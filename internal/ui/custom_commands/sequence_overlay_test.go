@@ -7,6 +7,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -119,3 +120,39 @@ func TestHandleKey_executes_multi_key_sequence(t *testing.T) {
 	require.True(t, model.last.Handled, "expected second key to be handled")
 	require.False(t, model.last.Active, "overlay should deactivate after sequence completes")
 }
+
+func TestView_shows_next_keys_and_reachable_commands(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	for name, seq := range map[string][]string{
+		"GoRun":     {"g", "o"},
+		"GoRebase":  {"g", "r", "d"},
+		"GoRestore": {"g", "r", "e"},
+		"Unrelated": {"x", "y"},
+	} {
+		ctx.CustomCommands[name] = &stubCommand{
+			CustomCommandBase: context.CustomCommandBase{Name: name, KeySequence: seq},
+			applicable:        true,
+		}
+	}
+
+	overlay := NewSequenceOverlay(ctx)
+	overlay.ViewNode.Parent = common.NewViewNode(80, 24)
+	model := &overlayModel{overlay: overlay}
+
+	test.SimulateModel(model, test.Type("g"))
+	view := test.Stripped(overlay.View())
+	assert.Contains(t, view, "g …")
+	assert.Contains(t, view, "o → GoRun")
+	assert.Contains(t, view, "r → GoRebase (d), GoRestore (e)")
+	assert.NotContains(t, view, "Unrelated")
+
+	test.SimulateModel(model, test.Type("r"))
+	view = test.Stripped(overlay.View())
+	assert.Contains(t, view, "g r …")
+	assert.Contains(t, view, "d → GoRebase")
+	assert.Contains(t, view, "e → GoRestore")
+	assert.NotContains(t, view, "GoRun")
+
+	test.SimulateModel(model, test.Type("d"))
+	assert.False(t, overlay.Active())
+}
@@ -1,28 +1,73 @@
 package diff
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/common/search"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/input"
+	"github.com/idursun/jjui/internal/ui/intents"
 )
 
+const defaultExportFileName = "jjui.diff"
+
+// horizontalStep is the number of columns the content is scrolled by with the left/right keys
+const horizontalStep = 8
+
 var _ common.Model = (*Model)(nil)
 
 type Model struct {
 	*common.ViewNode
 	*common.MouseAware
-	view   viewport.Model
-	keymap config.KeyMappings[key.Binding]
+	view      viewport.Model
+	keymap    config.KeyMappings[key.Binding]
+	content   string
+	unified   string
+	fileName  string
+	location  string
+	exporting *input.Model
+	search    *search.Model
+	title     string
+	styles    styles
+	// sideBySide shows the removed and added lines next to each other
+	sideBySide bool
+	// gitFormat is set when the diff can be laid out side by side
+	gitFormat bool
+	// wordDiff emphasizes the changed words of paired removed and added lines
+	wordDiff bool
+	// layoutWidth is the width the side by side layout was split for
+	layoutWidth int
+	// xOffset is the first visible column, clamped so that the widest line stays on the screen
+	xOffset      int
+	contentWidth int
+}
+
+type styles struct {
+	title     lipgloss.Style
+	indicator lipgloss.Style
 }
 
 func (m *Model) ShortHelp() []key.Binding {
+	if m.exporting != nil {
+		return m.exporting.ShortHelp()
+	}
+	if m.search.Editing() {
+		return []key.Binding{m.keymap.Apply, m.keymap.ContentSearch.ToggleRegex, m.keymap.Cancel}
+	}
 	vkm := m.view.KeyMap
 	return []key.Binding{
-		vkm.Up, vkm.Down, vkm.HalfPageDown, vkm.HalfPageUp, vkm.PageDown, vkm.PageUp,
+		vkm.Up, vkm.Down, vkm.HalfPageDown, vkm.HalfPageUp, vkm.PageDown, vkm.PageUp, vkm.Left, vkm.Right,
+		m.keymap.ContentSearch.Start, m.keymap.ContentSearch.Next, m.keymap.ContentSearch.Prev,
+		m.keymap.DiffSideBySide, m.keymap.ExportDiff,
 		m.keymap.Cancel}
 }
 
@@ -47,23 +92,222 @@ func (m *Model) Scroll(delta int) tea.Cmd {
 	return nil
 }
 
+// ScrollHorizontally moves the visible columns by delta, negative values scroll to the left
+func (m *Model) ScrollHorizontally(delta int) {
+	m.setXOffset(m.xOffset + delta)
+}
+
+func (m *Model) setXOffset(offset int) {
+	m.xOffset = max(min(offset, m.contentWidth-m.view.Width), 0)
+	m.view.SetXOffset(m.xOffset)
+}
+
+// setContent shows the rendered content, keeping the applied search
+func (m *Model) setContent(rendered string) {
+	m.search.SetContent(rendered)
+	m.view.SetContent(m.search.Highlight())
+	m.contentWidth = 0
+	for line := range strings.SplitSeq(rendered, "\n") {
+		m.contentWidth = max(m.contentWidth, lipgloss.Width(line))
+	}
+	m.setXOffset(m.xOffset)
+}
+
+// toggleSideBySide switches between the unified and the side by side layouts, the diff stays
+// unified when it isn't in the git format
+func (m *Model) toggleSideBySide() tea.Cmd {
+	if !m.sideBySide && !m.gitFormat {
+		return intents.Invoke(intents.AddMessage{Text: "side by side needs a diff in the git format (jj diff --git)"})
+	}
+	m.sideBySide = !m.sideBySide
+	m.layout()
+	return nil
+}
+
+func (m *Model) layout() {
+	m.layoutWidth = m.Width
+	if m.sideBySide {
+		m.setContent(sideBySide(m.unified, m.styles.indicator.Render(" │ "), m.Width, m.styles.indicator))
+	} else {
+		m.setContent(m.unified)
+	}
+}
+
+// HighlightWords emphasizes the changed words of the diff, the output of other commands is shown as it is
+func (m *Model) HighlightWords() {
+	m.wordDiff = true
+	m.unified = highlightWords(m.content, newWordDiffStyles())
+	m.layout()
+}
+
+// SetTitle shows the given title above the content
+func (m *Model) SetTitle(title string) {
+	m.title = title
+}
+
+// SetLocation sets the directory relative export file names are resolved against
+func (m *Model) SetLocation(location string) {
+	m.location = location
+}
+
+// SetSelectedItem derives the suggested export file name from the item the diff belongs to
+func (m *Model) SetSelectedItem(item context.SelectedItem) {
+	switch item := item.(type) {
+	case context.SelectedRevision:
+		m.fileName = item.ChangeId + ".diff"
+	case context.SelectedFile:
+		m.fileName = item.ChangeId + ".diff"
+	default:
+		m.fileName = defaultExportFileName
+	}
+}
+
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.exporting != nil {
+			return m.exporting.Update(msg)
+		}
+		if m.search.Editing() {
+			cmd, applied := m.search.Update(msg)
+			if applied {
+				m.view.SetContent(m.search.Highlight())
+				m.jumpToMatch(m.search.Next(m.view.YOffset - 1))
+			}
+			return cmd
+		}
 		switch {
+		case key.Matches(msg, m.keymap.Cancel) && m.search.Active():
+			m.search.Clear()
+			m.view.SetContent(m.search.Highlight())
+			return nil
 		case key.Matches(msg, m.keymap.Cancel):
 			return common.Close
+		case key.Matches(msg, m.keymap.ContentSearch.Start):
+			return m.search.Start()
+		case key.Matches(msg, m.keymap.ContentSearch.Next):
+			m.jumpToMatch(m.search.Next(m.view.YOffset))
+			return nil
+		case key.Matches(msg, m.keymap.ContentSearch.Prev):
+			m.jumpToMatch(m.search.Prev(m.view.YOffset))
+			return nil
+		case key.Matches(msg, m.view.KeyMap.Left):
+			m.ScrollHorizontally(-horizontalStep)
+			return nil
+		case key.Matches(msg, m.view.KeyMap.Right):
+			m.ScrollHorizontally(horizontalStep)
+			return nil
+		case key.Matches(msg, m.keymap.DiffSideBySide):
+			return m.toggleSideBySide()
+		case key.Matches(msg, m.keymap.ExportDiff):
+			m.exporting = input.NewWithTitle("Export diff", "file: ")
+			m.exporting.SetValue(m.fileName)
+			return m.exporting.Init()
+		}
+	case input.SelectedMsg:
+		if m.exporting == nil {
+			return nil
+		}
+		m.exporting = nil
+		if fileName := strings.TrimSpace(msg.Value); fileName != "" {
+			return m.export(fileName)
 		}
+		return nil
+	case input.CancelledMsg:
+		m.exporting = nil
+		return nil
+	case common.ThemeChangedMsg:
+		m.styles = newStyles()
+		if m.wordDiff {
+			m.unified = highlightWords(m.content, newWordDiffStyles())
+		}
+		m.layout()
+		return nil
 	}
 	var cmd tea.Cmd
 	m.view, cmd = m.view.Update(msg)
 	return cmd
 }
 
+func (m *Model) jumpToMatch(line int, ok bool) {
+	if ok {
+		m.view.SetYOffset(line)
+	}
+}
+
+// export writes the diff without colours to the given file, a relative file name is resolved
+// against the location of the repository
+func (m *Model) export(fileName string) tea.Cmd {
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(m.location, fileName)
+	}
+	var sb strings.Builder
+	for line := range strings.SplitSeq(m.content, "\n") {
+		sb.WriteString(stripAnsi(line))
+		sb.WriteString("\n")
+	}
+	return func() tea.Msg {
+		if err := os.WriteFile(fileName, []byte(sb.String()), 0o644); err != nil {
+			err = fmt.Errorf("failed to export diff: %w", err)
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		return intents.AddMessage{Text: fmt.Sprintf("Diff exported to %s", fileName)}
+	}
+}
+
+// scrollIndicator tells which columns are visible when the content is wider than the screen
+func (m *Model) scrollIndicator() string {
+	if m.contentWidth <= m.view.Width {
+		return ""
+	}
+	left, right := " ", " "
+	if m.xOffset > 0 {
+		left = "◀"
+	}
+	if m.xOffset+m.view.Width < m.contentWidth {
+		right = "▶"
+	}
+	indicator := fmt.Sprintf("%s %d-%d/%d %s", left, m.xOffset+1, min(m.xOffset+m.view.Width, m.contentWidth), m.contentWidth, right)
+	return lipgloss.PlaceHorizontal(m.Width, lipgloss.Right, m.styles.indicator.Render(indicator))
+}
+
 func (m *Model) View() string {
 	m.view.Height = m.Height
 	m.view.Width = m.Width
-	return m.view.View()
+	if m.sideBySide && m.layoutWidth != m.Width {
+		m.layout()
+	}
+	// the width may have changed since the offset was set
+	m.setXOffset(m.xOffset)
+	var header, prompt string
+	if m.title != "" {
+		header = m.styles.title.Width(m.Width).MaxWidth(m.Width).Render(m.title)
+	}
+	switch {
+	case m.exporting != nil:
+		prompt = m.exporting.View()
+	case m.search.Editing() || m.search.Active():
+		prompt = m.search.View()
+	default:
+		prompt = m.scrollIndicator()
+	}
+	if header == "" && prompt == "" {
+		return m.view.View()
+	}
+	var rows []string
+	if header != "" {
+		m.view.Height -= lipgloss.Height(header)
+		rows = append(rows, header)
+	}
+	if prompt != "" {
+		m.view.Height -= lipgloss.Height(prompt)
+	}
+	m.view.Height = max(m.view.Height, 0)
+	rows = append(rows, m.view.View())
+	if prompt != "" {
+		rows = append(rows, prompt)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
 func New(output string) *Model {
@@ -72,11 +316,26 @@ func New(output string) *Model {
 	if content == "" {
 		content = "(empty)"
 	}
-	view.SetContent(content)
-	return &Model{
+	m := &Model{
 		ViewNode:   common.NewViewNode(0, 0),
 		MouseAware: common.NewMouseAware(),
 		view:       view,
 		keymap:     config.Current.GetKeyMap(),
+		content:    strings.TrimSuffix(content, "\n"),
+		unified:    content,
+		fileName:   defaultExportFileName,
+		search:     search.New(),
+		styles:     newStyles(),
+		gitFormat:  isGitFormat(content),
+	}
+	m.sideBySide = config.Current.Diff.SideBySide && m.gitFormat
+	m.layout()
+	return m
+}
+
+func newStyles() styles {
+	return styles{
+		title:     common.DefaultPalette.Get("diff title"),
+		indicator: common.DefaultPalette.Get("diff dimmed"),
 	}
 }
@@ -1,12 +1,20 @@
 package diff
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/input"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -44,3 +52,175 @@ func TestUpdate_CancelReturnsClose(t *testing.T) {
 
 	assert.Contains(t, msgs, common.CloseViewMsg{})
 }
+
+func TestHighlightWords_EmphasizesChangedWords(t *testing.T) {
+	styles := wordDiffStyles{
+		removedToken: lipgloss.NewStyle().Transform(strings.ToUpper),
+		addedToken:   lipgloss.NewStyle().Transform(strings.ToUpper),
+	}
+	content := "@@ -1 +1 @@\n-let value = old;\n+let value = new;\n context"
+	expected := "@@ -1 +1 @@\n-let value = OLD;\n+let value = NEW;\n context"
+	assert.Equal(t, expected, highlightWords(content, styles))
+}
+
+func TestNew_ShowsTheOutputAsItIs(t *testing.T) {
+	model := New("-let value = old;\n+let value = new;")
+	assert.False(t, model.wordDiff)
+	assert.Equal(t, "-let value = old;\n+let value = new;", model.unified)
+
+	model.HighlightWords()
+	assert.True(t, model.wordDiff)
+}
+
+func TestHighlightWords_LeavesUnpairedLinesUntouched(t *testing.T) {
+	styles := wordDiffStyles{
+		removedToken: lipgloss.NewStyle().Transform(strings.ToUpper),
+		addedToken:   lipgloss.NewStyle().Transform(strings.ToUpper),
+	}
+	content := "--- a/file\n+++ b/file\n-first\n-second\n+replacement\nBinary files differ"
+	assert.Equal(t, content, highlightWords(content, styles))
+}
+
+func TestUpdate_ExportsDiffToFile(t *testing.T) {
+	model := New("\x1b[31m-old\x1b[0m\n\x1b[32m+new\x1b[0m\n")
+	model.SetFrame(cellbuf.Rect(0, 0, 40, 10))
+	model.SetSelectedItem(context.SelectedRevision{ChangeId: "abc"})
+
+	test.SimulateModel(model, test.Type("w"))
+	assert.Contains(t, test.Stripped(model.View()), "abc.diff")
+
+	fileName := filepath.Join(t.TempDir(), "exported.diff")
+	var msgs []tea.Msg
+	test.SimulateModel(model, func() tea.Msg { return input.SelectedMsg{Value: fileName} }, func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Contains(t, msgs, intents.AddMessage{Text: "Diff exported to " + fileName})
+
+	content, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, "-old\n+new\n", string(content))
+}
+
+func TestUpdate_ExportsRelativePathToRepository(t *testing.T) {
+	location := t.TempDir()
+	model := New("+new\n")
+	model.SetLocation(location)
+
+	msg := model.export("relative.diff")()
+	fileName := filepath.Join(location, "relative.diff")
+	assert.Equal(t, intents.AddMessage{Text: "Diff exported to " + fileName}, msg)
+	content, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, "+new\n", string(content))
+}
+
+func TestSearch_JumpsBetweenMatches(t *testing.T) {
+	model := New("match\n1\n2\n3\nmatch\n4\n5\n6\n7")
+	model.SetFrame(cellbuf.Rect(0, 0, 20, 4))
+	model.View()
+
+	test.SimulateModel(model, test.Type("/match"))
+	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	assert.Equal(t, 0, model.view.YOffset)
+	assert.Contains(t, test.Stripped(model.View()), "1/2")
+
+	test.SimulateModel(model, test.Type("n"))
+	assert.Equal(t, 4, model.view.YOffset)
+
+	test.SimulateModel(model, test.Type("n"))
+	assert.Equal(t, 0, model.view.YOffset)
+
+	test.SimulateModel(model, test.Type("N"))
+	assert.Equal(t, 4, model.view.YOffset)
+}
+
+func TestView_ShowsTitle(t *testing.T) {
+	model := New("line1\nline2\nline3")
+	model.SetTitle("jj evolog")
+	model.SetFrame(cellbuf.Rect(0, 0, 20, 3))
+
+	assert.Equal(t, "jj evolog\nline1\nline2", test.Stripped(model.View()))
+}
+
+func TestUpdate_ScrollsHorizontallyWithinTheWidestLine(t *testing.T) {
+	model := New("0123456789abcdefghijklmn\nshort")
+	model.SetFrame(cellbuf.Rect(0, 0, 12, 3))
+	assert.Contains(t, test.Stripped(model.View()), "1-12/24 ▶")
+
+	test.SimulateModel(model, test.Type("l"))
+	assert.Equal(t, 8, model.xOffset)
+	test.SimulateModel(model, test.Type("l"))
+	assert.Equal(t, 12, model.xOffset, "the offset stops at the end of the widest line")
+	view := test.Stripped(model.View())
+	assert.True(t, strings.HasPrefix(view, "cdefghijklmn"), view)
+	assert.Contains(t, view, "◀ 13-24/24")
+
+	test.SimulateModel(model, test.Type("hh"))
+	assert.Equal(t, 0, model.xOffset)
+}
+
+func TestView_NoScrollIndicatorWhenContentFits(t *testing.T) {
+	model := New("line1\nline2")
+	model.SetFrame(cellbuf.Rect(0, 0, 20, 3))
+	assert.Equal(t, "line1\nline2", test.Stripped(model.View()))
+}
+
+func TestSideBySide_PairsRemovedAndAddedLines(t *testing.T) {
+	content := "diff --git a/f b/f\n@@ -1,3 +1,3 @@\n same\n-old\n-gone\n+new\n tail"
+	expected := "diff --git a/f b/f\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		"1  same | 1  same\n" +
+		"2 -old  | 2 +new\n" +
+		"3 -gone | \n" +
+		"4  tail | 3  tail"
+	assert.Equal(t, expected, sideBySide(content, " | ", 0, lipgloss.NewStyle()))
+}
+
+func TestSideBySide_WithoutHunkHeadersLinesAreNotNumbered(t *testing.T) {
+	content := "-old\n+new"
+	assert.Equal(t, "-old | +new", sideBySide(content, " | ", 0, lipgloss.NewStyle()))
+}
+
+func TestSideBySide_ColumnsAreSplitInTheMiddle(t *testing.T) {
+	content := "@@ -9,2 +9,2 @@\n-a\n+b\n c"
+	expected := "@@ -9,2 +9,2 @@\n" +
+		" 9 -a    |  9 +b\n" +
+		"10  c    | 10  c"
+	assert.Equal(t, expected, sideBySide(content, " | ", 20, lipgloss.NewStyle()))
+}
+
+func TestUpdate_TogglesSideBySide(t *testing.T) {
+	model := New("@@ -1 +1 @@\n-a\n+b")
+	model.SetFrame(cellbuf.Rect(0, 0, 20, 3))
+
+	test.SimulateModel(model, test.Type("s"))
+	assert.Equal(t, "@@ -1 +1 @@\n1 -a     │ 1 +b", test.Stripped(model.View()))
+
+	test.SimulateModel(model, test.Type("s"))
+	assert.Equal(t, "@@ -1 +1 @@\n-a\n+b", test.Stripped(model.View()))
+}
+
+func TestNew_StartsSideBySideWhenConfigured(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Diff.SideBySide = true
+
+	model := New("@@ -1 +1 @@\n-a\n+b")
+	model.SetFrame(cellbuf.Rect(0, 0, 20, 3))
+	assert.Equal(t, "@@ -1 +1 @@\n1 -a     │ 1 +b", test.Stripped(model.View()))
+}
+
+func TestUpdate_SideBySideNeedsGitFormat(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Diff.SideBySide = true
+
+	model := New("Modified regular file a.txt:\n   1    1: ab")
+	model.SetFrame(cellbuf.Rect(0, 0, 30, 3))
+	assert.Equal(t, "Modified regular file a.txt:\n1    1: ab", test.Stripped(model.View()))
+
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, intents.AddMessage{Text: "side by side needs a diff in the git format (jj diff --git)"}, cmd())
+	assert.False(t, model.sideBySide)
+}
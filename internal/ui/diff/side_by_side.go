@@ -0,0 +1,134 @@
+package diff
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// isGitFormat reports whether the diff is in the git format, the only one with the hunk headers
+// the side by side layout needs to pair the lines. jj's default color-words format has none.
+func isGitFormat(content string) bool {
+	for line := range strings.SplitSeq(content, "\n") {
+		if hunkHeader.MatchString(stripAnsi(line)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sideBySideCell is a line of one of the columns with its line number, 0 when it is not known
+type sideBySideCell struct {
+	line   string
+	number int
+}
+
+// sideBySideRow is either a pair of cells or a line that spans both columns (file and hunk headers)
+type sideBySideRow struct {
+	left, right *sideBySideCell
+	span        string
+}
+
+// sideBySide lays out a unified diff in two columns, removed lines on the left and added lines
+// on the right. Runs of removed lines are paired with the added lines following them, context
+// lines are shown in both columns and the other lines span both columns as they are.
+// Lines are numbered from the hunk headers and the left column takes at least half of the
+// given width so that the columns are split in the middle of the screen.
+func sideBySide(content string, separator string, width int, numberStyle lipgloss.Style) string {
+	var rows []sideBySideRow
+	var removed, added []*sideBySideCell
+	flush := func() {
+		for i := 0; i < max(len(removed), len(added)); i++ {
+			var row sideBySideRow
+			if i < len(removed) {
+				row.left = removed[i]
+			}
+			if i < len(added) {
+				row.right = added[i]
+			}
+			rows = append(rows, row)
+		}
+		removed, added = nil, nil
+	}
+
+	// the line numbers are unknown until the first hunk header
+	oldNumber, newNumber, maxNumber := 0, 0, 0
+	next := func(number *int) int {
+		if *number == 0 {
+			return 0
+		}
+		*number++
+		maxNumber = max(maxNumber, *number-1)
+		return *number - 1
+	}
+	for line := range strings.SplitSeq(content, "\n") {
+		plain := stripAnsi(line)
+		switch {
+		case strings.HasPrefix(plain, " "):
+			flush()
+			rows = append(rows, sideBySideRow{
+				left:  &sideBySideCell{line, next(&oldNumber)},
+				right: &sideBySideCell{line, next(&newNumber)},
+			})
+		case classify(plain) == removedLine:
+			if len(added) > 0 {
+				flush()
+			}
+			removed = append(removed, &sideBySideCell{line, next(&oldNumber)})
+		case classify(plain) == addedLine:
+			added = append(added, &sideBySideCell{line, next(&newNumber)})
+		default:
+			flush()
+			if match := hunkHeader.FindStringSubmatch(plain); match != nil {
+				oldNumber, _ = strconv.Atoi(match[1])
+				newNumber, _ = strconv.Atoi(match[2])
+			}
+			rows = append(rows, sideBySideRow{span: line})
+		}
+	}
+	flush()
+
+	numberWidth := 0
+	if maxNumber > 0 {
+		numberWidth = len(strconv.Itoa(maxNumber))
+	}
+	render := func(cell *sideBySideCell) string {
+		if cell == nil {
+			return ""
+		}
+		if numberWidth == 0 {
+			return cell.line
+		}
+		number := strings.Repeat(" ", numberWidth)
+		if cell.number > 0 {
+			number = strconv.Itoa(cell.number)
+			number = strings.Repeat(" ", numberWidth-len(number)) + number
+		}
+		return numberStyle.Render(number) + " " + cell.line
+	}
+
+	leftWidth := max((width-lipgloss.Width(separator))/2, 0)
+	cells := make([][2]string, len(rows))
+	for i, row := range rows {
+		if row.left == nil && row.right == nil {
+			continue
+		}
+		cells[i] = [2]string{render(row.left), render(row.right)}
+		leftWidth = max(leftWidth, lipgloss.Width(cells[i][0]))
+	}
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		if row.left == nil && row.right == nil {
+			lines[i] = row.span
+			continue
+		}
+		padding := strings.Repeat(" ", leftWidth-lipgloss.Width(cells[i][0]))
+		lines[i] = cells[i][0] + padding + separator + cells[i][1]
+	}
+	return strings.Join(lines, "\n")
+}
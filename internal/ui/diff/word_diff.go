@@ -0,0 +1,178 @@
+package diff
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/screen"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+type lineKind int
+
+const (
+	contextLine lineKind = iota
+	removedLine
+	addedLine
+)
+
+type wordDiffStyles struct {
+	removed      lipgloss.Style
+	removedToken lipgloss.Style
+	added        lipgloss.Style
+	addedToken   lipgloss.Style
+}
+
+func newWordDiffStyles() wordDiffStyles {
+	return wordDiffStyles{
+		removed:      common.DefaultPalette.Get("diff removed"),
+		removedToken: common.DefaultPalette.Get("diff removed token"),
+		added:        common.DefaultPalette.Get("diff added"),
+		addedToken:   common.DefaultPalette.Get("diff added token"),
+	}
+}
+
+// highlightWords emphasizes the changed words of paired removed/added lines in
+// a unified diff. Lines that cannot be paired are left as they are.
+func highlightWords(content string, styles wordDiffStyles) string {
+	lines := strings.Split(content, "\n")
+	plain := make([]string, len(lines))
+	kinds := make([]lineKind, len(lines))
+	for i, line := range lines {
+		plain[i] = stripAnsi(line)
+		kinds[i] = classify(plain[i])
+	}
+
+	for i := 0; i < len(lines); {
+		if kinds[i] != removedLine {
+			i++
+			continue
+		}
+		removedStart := i
+		for i < len(lines) && kinds[i] == removedLine {
+			i++
+		}
+		addedStart := i
+		for i < len(lines) && kinds[i] == addedLine {
+			i++
+		}
+		// only runs of equal length can be paired line by line
+		if addedStart-removedStart != i-addedStart {
+			continue
+		}
+		for j := 0; j < addedStart-removedStart; j++ {
+			r, a := removedStart+j, addedStart+j
+			lines[r], lines[a] = renderPair(plain[r][1:], plain[a][1:], styles)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func classify(line string) lineKind {
+	switch {
+	case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+		return contextLine
+	case strings.HasPrefix(line, "-"):
+		return removedLine
+	case strings.HasPrefix(line, "+"):
+		return addedLine
+	default:
+		return contextLine
+	}
+}
+
+func renderPair(removed string, added string, styles wordDiffStyles) (string, string) {
+	oldTokens := tokenize(removed)
+	newTokens := tokenize(added)
+	oldCommon, newCommon := commonTokens(oldTokens, newTokens)
+
+	var oldLine, newLine strings.Builder
+	oldLine.WriteString(styles.removed.Render("-"))
+	for i, token := range oldTokens {
+		if oldCommon[i] {
+			oldLine.WriteString(styles.removed.Render(token))
+		} else {
+			oldLine.WriteString(styles.removedToken.Render(token))
+		}
+	}
+	newLine.WriteString(styles.added.Render("+"))
+	for i, token := range newTokens {
+		if newCommon[i] {
+			newLine.WriteString(styles.added.Render(token))
+		} else {
+			newLine.WriteString(styles.addedToken.Render(token))
+		}
+	}
+	return oldLine.String(), newLine.String()
+}
+
+// tokenize splits a line into words, runs of whitespace and single punctuation characters
+func tokenize(line string) []string {
+	var tokens []string
+	runes := []rune(line)
+	for start := 0; start < len(runes); {
+		end := start + 1
+		switch {
+		case isWordRune(runes[start]):
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+		case unicode.IsSpace(runes[start]):
+			for end < len(runes) && unicode.IsSpace(runes[end]) {
+				end++
+			}
+		}
+		tokens = append(tokens, string(runes[start:end]))
+		start = end
+	}
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// commonTokens marks the tokens that are part of the longest common subsequence of both lines
+func commonTokens(a []string, b []string) ([]bool, []bool) {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	inA := make([]bool, len(a))
+	inB := make([]bool, len(b))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			inA[i], inB[j] = true, true
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return inA, inB
+}
+
+func stripAnsi(line string) string {
+	if !strings.Contains(line, "\x1b") {
+		return line
+	}
+	var sb strings.Builder
+	for _, segment := range screen.Parse([]byte(line)) {
+		sb.WriteString(segment.Text)
+	}
+	return sb.String()
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/clipboard"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
@@ -36,7 +37,7 @@ func ExecLine(ctx *context.MainContext, msg common.ExecMsg) tea.Cmd {
 	case common.ExecJJ:
 		args := strings.Fields(msg.Line)
 		args = jj.TemplatedArgs(args, replacements)
-		return execProgram("jj", args, ctx.Location, nil, msg)
+		return execProgram("jj", jj.WithGlobalArgs(args), ctx.Location, nil, msg)
 	case common.ExecShell:
 		// user input is run via `$SHELL -c` to support user specifying command lines
 		// that have pipes (eg, to a pager) or redirection.
@@ -55,6 +56,7 @@ func ExecLine(ctx *context.MainContext, msg common.ExecMsg) tea.Cmd {
 //
 // If program terminates in less than 5-secs we ask to press a key to return to JJUI.
 // This is useful for programs that would otherwise terminate quickly and just flash.
+// Interactive programs like editors are not followed by this prompt.
 //
 // Since programs are run interactively (without capturing stdio) users have
 // already seen output on the terminal, and we don't use the usual CommandRunning or
@@ -62,7 +64,7 @@ func ExecLine(ctx *context.MainContext, msg common.ExecMsg) tea.Cmd {
 // However, if the program fails we ask the user for confirmation before closing
 // and returning stdio back to jjui.
 func execProgram(program string, args []string, location string, env map[string]string, msg common.ExecMsg) tea.Cmd {
-	p := &process{program: program, args: args, env: env, location: location}
+	p := &process{program: program, args: args, env: env, location: location, interactive: msg.Interactive}
 	return tea.Exec(p, func(err error) tea.Msg {
 		return common.ExecProcessCompletedMsg{
 			Err: err,
@@ -72,13 +74,14 @@ func execProgram(program string, args []string, location string, env map[string]
 }
 
 type process struct {
-	program  string
-	args     []string
-	stdin    io.Reader
-	stdout   io.Writer
-	stderr   io.Writer
-	env      map[string]string
-	location string
+	program     string
+	args        []string
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
+	env         map[string]string
+	location    string
+	interactive bool
 }
 
 // Run This is a blocking call.
@@ -105,9 +108,9 @@ func (p *process) Run() error {
 		askUserClose = false
 	}()
 
-	err := cmd.Run()
+	err := context.RunInteractive(cmd)
 	// Dont auto-close on error.
-	if askUserClose || err != nil {
+	if !p.interactive && (askUserClose || err != nil) {
 		p.stderr.Write([]byte("\njjui: press enter to continue... "))
 		reader := bufio.NewReader(p.stdin)
 		reader.ReadByte()
@@ -120,7 +123,7 @@ func (p *process) SetStdin(stdin io.Reader) {
 
 }
 func (p *process) SetStdout(stdout io.Writer) {
-	p.stdout = stdout
+	p.stdout = clipboard.ProcessOutput(stdout)
 
 }
 func (p *process) SetStderr(stderr io.Writer) {
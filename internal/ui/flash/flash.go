@@ -1,12 +1,16 @@
 package flash
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/intents"
@@ -23,6 +27,8 @@ type flashMessage struct {
 	error   error
 	timeout int
 	id      uint64
+	// progressId is set for messages that stay until they are resolved
+	progressId string
 }
 
 type FlashMessageView struct {
@@ -38,6 +44,18 @@ type Model struct {
 	successStyle lipgloss.Style
 	errorStyle   lipgloss.Style
 	currentId    uint64
+	spinner      spinner.Model
+	expandKey    key.Binding
+}
+
+// AddProgress shows a message with a spinner that stays until it is resolved with the same id
+func AddProgress(id string, message string) tea.Cmd {
+	return intents.Invoke(intents.AddProgress{Id: id, Text: message})
+}
+
+// Resolve removes the progress message with the given id. A non-nil err replaces it with an error message.
+func Resolve(id string, err error) tea.Cmd {
+	return intents.Invoke(intents.ResolveProgress{Id: id, Err: err})
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -48,6 +66,9 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case intents.Intent:
 		return m.handleIntent(msg)
+	case common.ThemeChangedMsg:
+		m.reloadStyles()
+		return nil
 	case expireMessageMsg:
 		for i, message := range m.messages {
 			if message.id == msg.id {
@@ -66,6 +87,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		return nil
 	case common.UpdateRevisionsFailedMsg:
 		m.add(msg.Output, msg.Err)
+	case spinner.TickMsg:
+		if !m.hasProgress() {
+			return nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return cmd
 	}
 	return nil
 }
@@ -80,6 +108,30 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 			})
 		}
 		return nil
+	case intents.AddProgress:
+		wasSpinning := m.hasProgress()
+		m.messages = append(m.messages, flashMessage{
+			id:         m.nextId(),
+			text:       strings.TrimSpace(intent.Text),
+			progressId: intent.Id,
+		})
+		if wasSpinning {
+			return nil
+		}
+		return m.spinner.Tick
+	case intents.ResolveProgress:
+		for i, message := range m.messages {
+			if message.progressId != intent.Id {
+				continue
+			}
+			if intent.Err != nil {
+				m.messages[i] = flashMessage{id: message.id, error: intent.Err}
+			} else {
+				m.messages = append(m.messages[:i], m.messages[i+1:]...)
+			}
+			break
+		}
+		return nil
 	case intents.DismissOldest:
 		if len(m.messages) == 0 {
 			return nil
@@ -96,34 +148,29 @@ func (m *Model) View() []FlashMessageView {
 		return nil
 	}
 
-	y := m.Height - 1
-	var messageBoxes []FlashMessageView
-	// reserve padding and calculate max width for messages
-	maxWidth := m.Width - 4
-
-	for _, message := range messages {
-		var text string
-		var style lipgloss.Style
-		if message.error != nil {
-			text = message.error.Error()
-			style = m.errorStyle
-		} else {
-			text = message.text
-			style = m.successStyle
-		}
-
-		// first render without width to check natural size
-		naturalContent := style.Render(text)
-		naturalWidth, _ := lipgloss.Size(naturalContent)
-
-		var content string
-		if naturalWidth <= maxWidth {
-			content = naturalContent
-		} else {
-			// width doesn't fit within maxWidth, set Width for line wrap
-			content = style.Width(maxWidth).Render(text)
+	maxLines := config.Current.UI.Flash.MaxLines
+	contents := make([]string, len(messages))
+	height := 0
+	for i, message := range messages {
+		text, style := m.textOf(message)
+		lines := m.wrap(text, style)
+		if m.isCut(lines) {
+			hidden := len(lines) - maxLines + 1
+			notice := fmt.Sprintf("… %d more lines, %s to expand", hidden, m.expandKey.Help().Key)
+			lines = append(lines[:maxLines-1], notice)
 		}
+		contents[i] = style.Render(strings.Join(lines, "\n"))
+		height += lipgloss.Height(contents[i])
+	}
+	// leave out the oldest messages when they don't all fit on the screen
+	for len(contents) > 1 && height > m.Height-1 {
+		height -= lipgloss.Height(contents[0])
+		contents = contents[1:]
+	}
 
+	y := m.Height - 1
+	var messageBoxes []FlashMessageView
+	for _, content := range contents {
 		w, h := lipgloss.Size(content)
 		y -= h
 		messageBoxes = append(messageBoxes, FlashMessageView{
@@ -134,6 +181,69 @@ func (m *Model) View() []FlashMessageView {
 	return messageBoxes
 }
 
+func (m *Model) textOf(message flashMessage) (string, lipgloss.Style) {
+	if message.error != nil {
+		return message.error.Error(), m.errorStyle
+	}
+	text := message.text
+	if message.progressId != "" {
+		text = m.spinner.View() + " " + text
+	}
+	return text, m.successStyle
+}
+
+// maxWidth is the width of the widest message, padding included
+func (m *Model) maxWidth() int {
+	// reserve room for the border and the margin
+	maxWidth := m.Width - 4
+	if percentage := config.Current.UI.Flash.WidthPercentage; percentage > 0 {
+		maxWidth = min(maxWidth, int(float64(m.Width)*percentage/100))
+	}
+	return max(maxWidth, 1)
+}
+
+// wrap breaks the text into the lines shown within the max width
+func (m *Model) wrap(text string, style lipgloss.Style) []string {
+	maxWidth := m.maxWidth()
+	if lipgloss.Width(text)+style.GetHorizontalPadding() > maxWidth {
+		text = lipgloss.NewStyle().Width(max(maxWidth-style.GetHorizontalPadding(), 1)).Render(text)
+	}
+	return strings.Split(text, "\n")
+}
+
+func (m *Model) isCut(lines []string) bool {
+	maxLines := config.Current.UI.Flash.MaxLines
+	return maxLines > 0 && len(lines) > maxLines
+}
+
+// Expandable reports whether a message is cut at ui.flash.max_lines
+func (m *Model) Expandable() bool {
+	return m.newestCut() >= 0
+}
+
+// Expand removes the newest message that is cut and shows it in full
+func (m *Model) Expand() tea.Cmd {
+	i := m.newestCut()
+	if i < 0 {
+		return nil
+	}
+	text, _ := m.textOf(m.messages[i])
+	m.messages = append(m.messages[:i], m.messages[i+1:]...)
+	return func() tea.Msg {
+		return common.ShowOutputMsg{Title: "Message", Output: text}
+	}
+}
+
+func (m *Model) newestCut() int {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		text, style := m.textOf(m.messages[i])
+		if m.isCut(m.wrap(text, style)) {
+			return i
+		}
+	}
+	return -1
+}
+
 func (m *Model) add(text string, error error) uint64 {
 	text = strings.TrimSpace(text)
 	if text == "" && error == nil {
@@ -150,6 +260,15 @@ func (m *Model) add(text string, error error) uint64 {
 	return msg.id
 }
 
+func (m *Model) hasProgress() bool {
+	for _, message := range m.messages {
+		if message.progressId != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Model) Any() bool {
 	return len(m.messages) > 0
 }
@@ -164,14 +283,20 @@ func (m *Model) nextId() uint64 {
 }
 
 func New(context *context.MainContext) *Model {
-	fg := lipgloss.NewStyle().GetForeground()
-	successStyle := common.DefaultPalette.GetBorder("success", lipgloss.NormalBorder()).Foreground(fg).PaddingLeft(1).PaddingRight(1)
-	errorStyle := common.DefaultPalette.GetBorder("error", lipgloss.NormalBorder()).Foreground(fg).PaddingLeft(1).PaddingRight(1)
-	return &Model{
-		ViewNode:     common.NewViewNode(0, 0),
-		context:      context,
-		messages:     make([]flashMessage, 0),
-		successStyle: successStyle,
-		errorStyle:   errorStyle,
+	m := &Model{
+		ViewNode:  common.NewViewNode(0, 0),
+		context:   context,
+		messages:  make([]flashMessage, 0),
+		spinner:   spinner.New(spinner.WithSpinner(spinner.Dot)),
+		expandKey: config.Current.GetKeyMap().ExpandMessage,
 	}
+	m.reloadStyles()
+	return m
+}
+
+// reloadStyles reads the border colours of the messages from common.DefaultPalette
+func (m *Model) reloadStyles() {
+	fg := lipgloss.NewStyle().GetForeground()
+	m.successStyle = common.DefaultPalette.GetBorder("success", lipgloss.NormalBorder()).Foreground(fg).PaddingLeft(1).PaddingRight(1)
+	m.errorStyle = common.DefaultPalette.GetBorder("error", lipgloss.NormalBorder()).Foreground(fg).PaddingLeft(1).PaddingRight(1)
 }
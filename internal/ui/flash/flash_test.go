@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -97,3 +99,91 @@ func TestDeleteOldest_RemovesFirstMessage(t *testing.T) {
 		assert.Equal(t, "second", m.messages[0].text)
 	}
 }
+
+func TestProgress_StaysUntilResolved(t *testing.T) {
+	m := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	m.successStyle = lipgloss.NewStyle()
+	m.SetWidth(40)
+	m.SetHeight(5)
+
+	cmd := m.Update(intents.AddProgress{Id: "push", Text: "pushing"})
+	assert.NotNil(t, cmd, "expected the spinner to start ticking")
+	if assert.Len(t, m.View(), 1) {
+		assert.Contains(t, m.View()[0].Content, "pushing")
+	}
+
+	m.Update(intents.ResolveProgress{Id: "push"})
+	assert.Empty(t, m.messages)
+}
+
+func TestProgress_ResolvedWithErrorBecomesErrorMessage(t *testing.T) {
+	m := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+
+	m.Update(intents.AddProgress{Id: "fetch", Text: "fetching"})
+	m.Update(intents.AddProgress{Id: "push", Text: "pushing"})
+	m.Update(intents.ResolveProgress{Id: "push", Err: errors.New("rejected")})
+
+	if assert.Len(t, m.messages, 2) {
+		assert.Equal(t, "fetching", m.messages[0].text)
+		assert.EqualError(t, m.messages[1].error, "rejected")
+		assert.Empty(t, m.messages[1].progressId)
+	}
+}
+
+func TestView_WrapsToWidthPercentage(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Flash = config.FlashConfig{WidthPercentage: 50}
+
+	m := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	m.errorStyle = lipgloss.NewStyle()
+	m.SetWidth(20)
+	m.SetHeight(10)
+
+	m.add("", errors.New("one two three four"))
+
+	views := m.View()
+	if assert.Len(t, views, 1) {
+		assert.Equal(t, "one two   \nthree four", views[0].Content)
+		assert.Equal(t, 10, views[0].Rect.Min.X)
+	}
+}
+
+func TestView_CutsLongMessagesAndExpandsThem(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Flash = config.FlashConfig{MaxLines: 3}
+
+	m := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	m.errorStyle = lipgloss.NewStyle()
+	m.SetWidth(60)
+	m.SetHeight(10)
+
+	m.add("", errors.New("1\n2\n3\n4\n5"))
+	assert.True(t, m.Expandable())
+
+	views := m.View()
+	if assert.Len(t, views, 1) {
+		assert.Equal(t, "1\n2\n… 3 more lines, ctrl+o to expand", views[0].Content)
+	}
+
+	cmd := m.Expand()
+	assert.Equal(t, common.ShowOutputMsg{Title: "Message", Output: "1\n2\n3\n4\n5"}, cmd())
+	assert.False(t, m.Any())
+}
+
+func TestView_LeavesOutOldestMessagesThatDontFit(t *testing.T) {
+	m := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	m.successStyle = lipgloss.NewStyle()
+	m.SetWidth(20)
+	m.SetHeight(4)
+
+	m.add("first\nmessage", nil)
+	m.add("second\nmessage", nil)
+
+	views := m.View()
+	if assert.Len(t, views, 1) {
+		assert.Equal(t, "second\nmessage", views[0].Content)
+		assert.Equal(t, 1, views[0].Rect.Min.Y)
+	}
+}
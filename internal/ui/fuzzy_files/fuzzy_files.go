@@ -29,6 +29,9 @@ type fuzzyFiles struct {
 	commit          *jj.Commit
 	wasPreviewShown bool
 
+	// the files of the working copy are searched and accepting opens the details at the file
+	workspace bool
+
 	cursor int
 	// enabled with ctrl+t again
 	// live preview of revset and rev-diff
@@ -36,10 +39,11 @@ type fuzzyFiles struct {
 	debounceTag   int
 
 	// search state
-	files   []string
-	max     int
-	matches fuzzy.Matches
-	styles  fuzzy_search.Styles
+	files     []string
+	max       int
+	matches   fuzzy.Matches
+	lastInput string
+	styles    fuzzy_search.Styles
 }
 
 var debounceDuration = 250 * time.Millisecond
@@ -155,6 +159,12 @@ func (fzf *fuzzyFiles) handleKey(msg tea.KeyMsg) tea.Cmd {
 			fzf.updateRevSet(),
 		)
 	case key.Matches(msg, fzfKm.Accept, fzf.inputKm.AcceptSuggestion):
+		if file := fzf.selectedFile(); fzf.workspace && file != "" {
+			return tea.Batch(
+				newCmd(common.OpenFileDetailsMsg(file)),
+				newCmd(common.ShowPreview(fzf.wasPreviewShown)),
+			)
+		}
 		return fzf.updateRevSet()
 	case fzf.isInputMovement(msg):
 		return skipSearch
@@ -203,21 +213,60 @@ func (fzf *fuzzyFiles) String(i int) string {
 	return fzf.files[i]
 }
 
+// selectedFile returns the path of the selected match without the quotes of fuzzy_search.SelectedMatch
+func (fzf *fuzzyFiles) selectedFile() string {
+	if fzf.cursor < 0 || fzf.cursor >= len(fzf.matches) {
+		return ""
+	}
+	return fzf.String(fzf.matches[fzf.cursor].Index)
+}
+
 func (fzf *fuzzyFiles) search(input string) {
-	src := &fuzzy_search.RefinedSource{Source: fzf}
 	fzf.cursor = 0
+	defer func() { fzf.lastInput = input }()
+	if narrowsLastInput(fzf.lastInput, input) {
+		// only the files matching the last input can match a longer one, which keeps
+		// typing responsive in working copies with many thousands of files
+		var matches fuzzy.Matches
+		for _, match := range fuzzy.FindFrom(strings.TrimSpace(input), previousMatches(fzf.matches)) {
+			match.Index = fzf.matches[match.Index].Index
+			matches = append(matches, match)
+		}
+		fzf.matches = matches
+		return
+	}
+	src := &fuzzy_search.RefinedSource{Source: fzf}
 	fzf.matches = src.Search(input, fzf.max)
 }
 
+// narrowsLastInput reports whether input only appends to a single word search
+func narrowsLastInput(last string, input string) bool {
+	last, input = strings.TrimSpace(last), strings.TrimSpace(input)
+	return last != "" && len(input) > len(last) && strings.HasPrefix(input, last) && !strings.ContainsAny(input, " \t")
+}
+
+type previousMatches fuzzy.Matches
+
+func (p previousMatches) Len() int {
+	return len(p)
+}
+
+func (p previousMatches) String(i int) string {
+	return p[i].Str
+}
+
 func (fzf *fuzzyFiles) View() string {
 	shown := len(fzf.matches)
+	location := "files present at revision " + fzf.commit.GetChangeId()
+	if fzf.workspace {
+		location = "files in the working copy"
+	}
 	title := fzf.styles.SelectedMatch.Render(
 		"  ",
 		strconv.Itoa(shown),
 		"of",
 		strconv.Itoa(len(fzf.files)),
-		"files present at revision",
-		fzf.commit.GetChangeId(),
+		location,
 		" ",
 	)
 	entries := fuzzy_search.View(fzf)
@@ -273,6 +322,7 @@ func NewModel(msg common.FileSearchMsg) (fuzzy_search.Model, editStatus) {
 		wasPreviewShown: msg.PreviewShown,
 		max:             30,
 		commit:          msg.Commit,
+		workspace:       msg.Workspace,
 		files:           strings.Split(string(msg.RawFileOut), "\n"),
 		styles:          fuzzy_search.NewStyles(),
 	}
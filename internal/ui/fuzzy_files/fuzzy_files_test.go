@@ -3,6 +3,8 @@ package fuzzy_files
 import (
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/fuzzy_search"
 	"github.com/sahilm/fuzzy"
@@ -109,3 +111,36 @@ func TestUpdateRevSet_EmptyMatches(t *testing.T) {
 	// when matches is empty, SelectedMatch returns empty string
 	assert.Equal(t, "@", string(updateMsg))
 }
+
+func TestSearch_NarrowsPreviousMatches(t *testing.T) {
+	files := []string{"cmd/main.go", "internal/config/config.go", "internal/ui/ui.go", "README.md", "go.mod"}
+	model := &fuzzyFiles{files: files, max: 30, styles: fuzzy_search.NewStyles()}
+	fresh := &fuzzyFiles{files: files, max: 30, styles: fuzzy_search.NewStyles()}
+
+	model.search("")
+	for _, input := range []string{"c", "co", "con", "conf"} {
+		model.search(input)
+	}
+	fresh.search("conf")
+
+	assert.Equal(t, fresh.matches, model.matches)
+	assert.Equal(t, "internal/config/config.go", model.selectedFile())
+}
+
+func TestAccept_InWorkspaceOpensFileDetails(t *testing.T) {
+	model := &fuzzyFiles{
+		keyMap:    config.Current.GetKeyMap(),
+		revset:    "all()",
+		workspace: true,
+		files:     []string{"file1.txt", "path/to/file2.go"},
+		matches:   fuzzy.Matches{{Index: 1, Str: "path/to/file2.go"}},
+		styles:    fuzzy_search.NewStyles(),
+	}
+
+	cmd := model.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	var msgs []tea.Msg
+	for _, c := range cmd().(tea.BatchMsg) {
+		msgs = append(msgs, c())
+	}
+	assert.Contains(t, msgs, common.OpenFileDetailsMsg("path/to/file2.go"))
+}
@@ -1,7 +1,9 @@
 package git
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -11,9 +13,12 @@ import (
 	"github.com/charmbracelet/x/cellbuf"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/choose"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/common/menu"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/flash"
+	"github.com/idursun/jjui/internal/ui/intents"
 )
 
 type itemCategory string
@@ -65,14 +70,19 @@ type Model struct {
 	remoteNames       []string
 	selectedRemoteIdx int
 	styles            styles
+	remotePicker      *choose.Model
 }
 
 func (m *Model) ShortHelp() []key.Binding {
+	if m.remotePicker != nil {
+		return m.remotePicker.ShortHelp()
+	}
 	return []key.Binding{
 		m.keymap.Cancel,
 		m.keymap.Apply,
 		m.keymap.Git.Push,
 		m.keymap.Git.Fetch,
+		m.keymap.Git.Remote,
 		m.menu.List.KeyMap.Filter,
 		key.NewBinding(
 			key.WithKeys("tab/shift+tab"),
@@ -84,6 +94,10 @@ func (m *Model) FullHelp() [][]key.Binding {
 	return [][]key.Binding{m.ShortHelp()}
 }
 
+func (m *Model) Scroll(delta int) tea.Cmd {
+	return m.menu.Scroll(delta)
+}
+
 func (m *Model) Init() tea.Cmd {
 	return nil
 }
@@ -93,13 +107,18 @@ func (m *Model) cycleRemotes(step int) tea.Cmd {
 		return nil
 	}
 
-	m.selectedRemoteIdx += step
-	if m.selectedRemoteIdx >= len(m.remoteNames) {
-		m.selectedRemoteIdx = 0
-	} else if m.selectedRemoteIdx < 0 {
-		m.selectedRemoteIdx = len(m.remoteNames) - 1
+	idx := m.selectedRemoteIdx + step
+	if idx >= len(m.remoteNames) {
+		idx = 0
+	} else if idx < 0 {
+		idx = len(m.remoteNames) - 1
 	}
+	return m.selectRemote(idx)
+}
 
+// selectRemote rebuilds the menu items so that the commands target the remote at idx
+func (m *Model) selectRemote(idx int) tea.Cmd {
+	m.selectedRemoteIdx = idx
 	m.menu.Subtitle = m.displayRemotes()
 	m.menu.Items = m.createMenuItems()
 	if m.menu.Filter != "" {
@@ -111,7 +130,22 @@ func (m *Model) cycleRemotes(step int) tea.Cmd {
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
+	case choose.SelectedMsg:
+		if m.remotePicker == nil {
+			return nil
+		}
+		m.remotePicker = nil
+		if idx := slices.Index(m.remoteNames, msg.Value); idx >= 0 {
+			return m.selectRemote(idx)
+		}
+		return nil
+	case choose.CancelledMsg:
+		m.remotePicker = nil
+		return nil
 	case tea.KeyMsg:
+		if m.remotePicker != nil {
+			return m.remotePicker.Update(msg)
+		}
 		if m.menu.List.SettingFilter() {
 			break
 		}
@@ -122,7 +156,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			return m.cycleRemotes(-1)
 		case key.Matches(msg, m.keymap.Apply):
 			action := m.menu.List.SelectedItem().(item)
-			return m.context.RunCommand(jj.Args(action.command...), common.Refresh, common.Close)
+			return m.run(action)
 		case key.Matches(msg, m.keymap.Cancel):
 			if m.menu.Filter != "" || m.menu.List.IsFiltered() {
 				m.menu.List.ResetFilter()
@@ -133,10 +167,14 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			return m.filtered(string(itemCategoryPush))
 		case key.Matches(msg, m.keymap.Git.Fetch) && m.menu.Filter != string(itemCategoryFetch):
 			return m.filtered(string(itemCategoryFetch))
+		case key.Matches(msg, m.keymap.Git.Remote) && len(m.remoteNames) > 1:
+			m.remotePicker = choose.NewWithTitle(m.remoteNames, "Remote")
+			m.remotePicker.Select(m.remoteNames[m.selectedRemoteIdx])
+			return m.remotePicker.Init()
 		default:
 			for _, listItem := range m.menu.List.Items() {
 				if item, ok := listItem.(item); ok && m.menu.Filter != "" && item.key == msg.String() {
-					return m.context.RunCommand(jj.Args(item.command...), common.Refresh, common.Close)
+					return m.run(item)
 				}
 			}
 		}
@@ -154,6 +192,9 @@ func (m *Model) View() string {
 	pw, ph := m.Parent.Width, m.Parent.Height
 	m.menu.SetFrame(cellbuf.Rect(0, 0, min(pw, 80), min(ph, 40)).Inset(2))
 	v := m.menu.View()
+	if m.remotePicker != nil {
+		v = m.remotePicker.View()
+	}
 	w, h := lipgloss.Size(v)
 	sx := (pw - w) / 2
 	sy := (ph - h) / 2
@@ -179,6 +220,67 @@ func (m *Model) displayRemotes() string {
 	return w.String()
 }
 
+// run executes the git command while a progress message is shown until it completes, the progress
+// message turns into an error when the command fails
+func (m *Model) run(action item) tea.Cmd {
+	id := strings.Join(action.command, " ")
+	resolve := common.OnCommandResult(func(result common.CommandCompletedMsg) tea.Cmd {
+		if result.Err != nil {
+			// the output of the command is shown with its own message
+			return flash.Resolve(id, fmt.Errorf("%s failed", action.desc))
+		}
+		return flash.Resolve(id, nil)
+	})
+	return tea.Batch(
+		flash.AddProgress(id, action.desc),
+		m.context.RunCommand(jj.Args(action.command...), resolve, common.Refresh, common.Close),
+	)
+}
+
+// FetchAll fetches every remote and reports how many of them failed in a single message.
+// The remotes are fetched one after the other so that their output doesn't interleave.
+func FetchAll(c *context.MainContext) tea.Cmd {
+	const id = "git fetch all remotes"
+	return tea.Sequence(
+		flash.AddProgress(id, "Fetching all remotes"),
+		func() tea.Msg {
+			return fetchRemotes(c, id, loadRemoteNames(c))()
+		},
+		common.Refresh,
+	)
+}
+
+// fetchRemotes fetches the remotes one after another and sums up how many of them failed, the
+// progress message with the given id is resolved with the failures
+func fetchRemotes(c *context.MainContext, progressId string, remotes []string) tea.Cmd {
+	if len(remotes) == 0 {
+		return tea.Batch(flash.Resolve(progressId, nil), intents.Invoke(intents.AddMessage{Text: "no remotes to fetch"}))
+	}
+	var errs []error
+	var cmds []tea.Cmd
+	for _, remote := range remotes {
+		cmds = append(cmds, c.RunCommand(jj.GitFetch("--remote", remote), common.OnCommandResult(func(msg common.CommandCompletedMsg) tea.Cmd {
+			if msg.Err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", remote, msg.Err))
+			}
+			return nil
+		})))
+	}
+	// the summary is handed a result too so that it is put together after the last fetch is counted
+	cmds = append(cmds, common.OnCommandResult(func(common.CommandCompletedMsg) tea.Cmd {
+		if c.DryRun && len(errs) == 0 {
+			// nothing was fetched, the command lines are already shown
+			return flash.Resolve(progressId, nil)
+		}
+		fetched := len(remotes) - len(errs)
+		if len(errs) > 0 {
+			return flash.Resolve(progressId, fmt.Errorf("fetched %d remotes, %d failed\n%w", fetched, len(errs), errors.Join(errs...)))
+		}
+		return tea.Batch(flash.Resolve(progressId, nil), intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("fetched %d remotes", fetched)}))
+	}))
+	return tea.Sequence(cmds...)
+}
+
 func loadBookmarks(c context.CommandRunner, changeId string) []jj.Bookmark {
 	bytes, _ := c.RunCommandImmediate(jj.BookmarkList(changeId))
 	bookmarks := jj.ParseBookmarkListOutput(string(bytes))
@@ -1,12 +1,15 @@
 package git
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/cellbuf"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -39,6 +42,25 @@ func Test_Fetch(t *testing.T) {
 	test.SimulateModel(op, test.Press(tea.KeyEnter))
 }
 
+func Test_PushToChosenRemote(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin https://example.com/origin.git\nupstream https://example.com/upstream.git\n"))
+	commandRunner.Expect(jj.GitPush("--remote", "upstream"))
+	defer commandRunner.Verify()
+
+	op := NewModel(test.NewTestContext(commandRunner), jj.NewSelectedRevisions())
+	op.SetFrame(cellbuf.Rect(0, 0, 100, 40))
+	op.Parent = common.NewViewNode(100, 40)
+	test.SimulateModel(op, op.Init())
+	test.SimulateModel(op, test.Type("r"))
+	assert.NotNil(t, op.remotePicker)
+	test.SimulateModel(op, test.Press(tea.KeyDown))
+	test.SimulateModel(op, test.Press(tea.KeyEnter))
+	assert.Nil(t, op.remotePicker)
+	assert.Equal(t, "upstream", op.remoteNames[op.selectedRemoteIdx])
+	test.SimulateModel(op, test.Press(tea.KeyEnter))
+}
+
 func Test_loadBookmarks(t *testing.T) {
 	const changeId = "changeid"
 	commandRunner := test.NewTestCommandRunner(t)
@@ -74,3 +96,108 @@ func Test_PushChange(t *testing.T) {
 	test.SimulateModel(op, test.Press(tea.KeyDown)) // Ensure first item is selected
 	test.SimulateModel(op, test.Press(tea.KeyEnter))
 }
+
+// runInOrder runs cmd depth first like the program does, handing each continuation asking
+// for a result the result of its command, and returns the flash messages and resolved progresses
+func runInOrder(cmd tea.Cmd) ([]intents.AddMessage, []intents.ResolveProgress) {
+	var messages []intents.AddMessage
+	var resolved []intents.ResolveProgress
+	var run func(cmd tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				run(c)
+			}
+		case common.CommandResultMsg:
+			run(msg.Handle(msg.Result))
+		case intents.AddMessage:
+			messages = append(messages, msg)
+		case intents.ResolveProgress:
+			resolved = append(resolved, msg)
+		default:
+			// tea.Sequence returns an unexported slice of commands
+			if v := reflect.ValueOf(msg); v.Kind() == reflect.Slice {
+				for i := 0; i < v.Len(); i++ {
+					run(v.Index(i).Interface().(tea.Cmd))
+				}
+			}
+		}
+	}
+	run(cmd)
+	return messages, resolved
+}
+
+func Test_run_ResolvesTheProgressWithTheFailure(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitPush("--all")).SetError(errors.New("rejected"))
+	defer commandRunner.Verify()
+
+	model := &Model{context: test.NewTestContext(commandRunner)}
+	_, resolved := runInOrder(model.run(item{desc: "git push --all", command: jj.GitPush("--all")}))
+	assert.Len(t, resolved, 1)
+	assert.Equal(t, "git push --all", resolved[0].Id)
+	assert.EqualError(t, resolved[0].Err, "git push --all failed")
+}
+
+func Test_run_ResolvesTheProgress(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitPush("--all"))
+	defer commandRunner.Verify()
+
+	model := &Model{context: test.NewTestContext(commandRunner)}
+	_, resolved := runInOrder(model.run(item{desc: "git push --all", command: jj.GitPush("--all")}))
+	assert.Len(t, resolved, 1)
+	assert.NoError(t, resolved[0].Err)
+}
+
+func Test_fetchRemotes(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch("--remote", "origin"))
+	commandRunner.Expect(jj.GitFetch("--remote", "upstream"))
+	defer commandRunner.Verify()
+
+	messages, resolved := runInOrder(fetchRemotes(test.NewTestContext(commandRunner), "fetch", []string{"origin", "upstream"}))
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "fetched 2 remotes", messages[0].Text)
+	assert.Equal(t, []intents.ResolveProgress{{Id: "fetch"}}, resolved)
+}
+
+func Test_fetchRemotes_ReportsFailures(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch("--remote", "origin")).SetError(errors.New("connection refused"))
+	commandRunner.Expect(jj.GitFetch("--remote", "upstream"))
+	defer commandRunner.Verify()
+
+	messages, resolved := runInOrder(fetchRemotes(test.NewTestContext(commandRunner), "fetch", []string{"origin", "upstream"}))
+	assert.Empty(t, messages)
+	assert.Len(t, resolved, 1)
+	assert.Error(t, resolved[0].Err)
+	assert.Contains(t, resolved[0].Err.Error(), "fetched 1 remotes, 1 failed")
+	assert.Contains(t, resolved[0].Err.Error(), "origin: connection refused")
+}
+
+func Test_fetchRemotes_ReadOnly(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.ReadOnly = true
+	_, resolved := runInOrder(fetchRemotes(ctx, "fetch", []string{"origin"}))
+	assert.Len(t, resolved, 1)
+	assert.ErrorContains(t, resolved[0].Err, "fetched 0 remotes, 1 failed")
+}
+
+func Test_fetchRemotes_DryRun(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.DryRun = true
+	messages, resolved := runInOrder(fetchRemotes(ctx, "fetch", []string{"origin", "upstream"}))
+	assert.Empty(t, messages)
+	assert.Equal(t, []intents.ResolveProgress{{Id: "fetch"}}, resolved)
+}
@@ -12,11 +12,15 @@ import (
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/sahilm/fuzzy"
 )
 
 type helpItem struct {
-	display    string
-	searchTerm string
+	display     string
+	highlighted string
+	searchTerm  string
+	// keys are pressed when the item is picked from the filtered list, items without keys can't be picked
+	keys []string
 }
 
 type itemGroup = []helpItem
@@ -40,6 +44,9 @@ type Model struct {
 	defaultMenu  helpMenu
 	filteredMenu helpMenu
 	searchQuery  textinput.Model
+	scrollOffset int
+	query        string
+	selected     int
 }
 
 type styles struct {
@@ -48,6 +55,7 @@ type styles struct {
 	text     lipgloss.Style
 	shortcut lipgloss.Style
 	dimmed   lipgloss.Style
+	selected lipgloss.Style
 }
 
 func (h *Model) ShortHelp() []key.Binding {
@@ -68,8 +76,24 @@ func (h *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case key.Matches(msg, h.keyMap.Cancel) && h.searchQuery.Value() != "":
+			h.searchQuery.SetValue("")
+			h.filterMenu()
+			return nil
 		case key.Matches(msg, h.keyMap.Help), key.Matches(msg, h.keyMap.Cancel):
 			return common.Close
+		case msg.Type == tea.KeyUp, msg.Type == tea.KeyDown:
+			if n := len(h.selectableItems()); n > 0 {
+				delta := 1
+				if msg.Type == tea.KeyUp {
+					delta = -1
+				}
+				h.selected = (h.selected + delta + n) % n
+				h.scrollToSelected()
+			}
+			return nil
+		case msg.Type == tea.KeyEnter:
+			return h.pickSelected()
 		}
 	}
 
@@ -78,6 +102,63 @@ func (h *Model) Update(msg tea.Msg) tea.Cmd {
 	return cmd
 }
 
+// Scroll moves the menu by delta lines when the shown menu doesn't fit into the screen
+func (h *Model) Scroll(delta int) tea.Cmd {
+	h.scrollOffset = max(0, min(h.scrollOffset+delta, menuHeight(h.filteredMenu)-h.visibleHeight()))
+	return nil
+}
+
+// scrollToSelected moves the menu just enough for the selected item to be visible, along with
+// the header of its group when both fit
+func (h *Model) scrollToSelected() {
+	line, header := h.selectedLine()
+	if line < 0 {
+		return
+	}
+	visible := h.visibleHeight()
+	if line >= h.scrollOffset+visible {
+		h.scrollOffset = line - visible + 1
+	}
+	h.scrollOffset = min(h.scrollOffset, line)
+	if line-header < visible {
+		h.scrollOffset = min(h.scrollOffset, header)
+	}
+}
+
+// selectedLine returns the line of the selected item in its column and the line of the header
+// of its group, -1 when nothing is selected
+func (h *Model) selectedLine() (int, int) {
+	if h.query == "" {
+		return -1, -1
+	}
+	selectable := 0
+	for _, column := range []menuColumn{h.filteredMenu.leftList, h.filteredMenu.middleList, h.filteredMenu.rightList} {
+		line := 0
+		for _, group := range column {
+			header := line
+			for _, item := range group {
+				if len(item.keys) > 0 {
+					if selectable == h.selected {
+						return line, header
+					}
+					selectable++
+				}
+				line++
+			}
+		}
+	}
+	return -1, -1
+}
+
+// visibleHeight is the number of menu lines that fit next to the search bar and the border
+func (h *Model) visibleHeight() int {
+	if h.Parent == nil || h.Parent.Height == 0 {
+		return h.defaultMenu.height
+	}
+	// border, padding and the search bar take 6 lines
+	return max(1, h.Parent.Height-6)
+}
+
 func (h *Model) View() string {
 	// NOTE: add new lines between search bar and help menu
 	content := "\n\n" + h.renderMenu()
@@ -91,8 +172,49 @@ func (h *Model) View() string {
 	return v
 }
 
+// selectableItems are the items of the filtered menu that can be picked, in the order they are
+// shown: column by column from left to right
+func (h *Model) selectableItems() []helpItem {
+	if h.query == "" {
+		return nil
+	}
+	var items []helpItem
+	for _, column := range []menuColumn{h.filteredMenu.leftList, h.filteredMenu.middleList, h.filteredMenu.rightList} {
+		for _, group := range column {
+			for _, item := range group {
+				if len(item.keys) > 0 {
+					items = append(items, item)
+				}
+			}
+		}
+	}
+	return items
+}
+
+// pickSelected closes the help page and presses the keys of the selected item
+func (h *Model) pickSelected() tea.Cmd {
+	items := h.selectableItems()
+	if h.selected >= len(items) {
+		return nil
+	}
+	cmds := []tea.Cmd{common.Close}
+	for _, k := range items[h.selected].keys {
+		pressed, ok := common.KeyByName(k)
+		if !ok {
+			pressed = tea.Key{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		cmds = append(cmds, func() tea.Msg { return tea.KeyMsg(pressed) })
+	}
+	return tea.Sequence(cmds...)
+}
+
 func (h *Model) filterMenu() {
 	query := strings.ToLower(strings.TrimSpace(h.searchQuery.Value()))
+	if query != h.query {
+		h.query = query
+		h.selected = 0
+		h.scrollOffset = 0
+	}
 
 	if query == "" {
 		h.filteredMenu = h.defaultMenu
@@ -115,21 +237,24 @@ func filterList(column menuColumn, query string) menuColumn {
 		}
 		// Check if header matches
 		header := group[0]
-		headerMatches := strings.Contains(header.searchTerm, query)
+		headerMatches := matches(header.searchTerm, query)
 		if headerMatches {
 			filtered = append(filtered, group)
 			continue
 		}
 
+		// the header is only shown for context, picking it would enter the mode instead
+		header.keys = nil
 		matchedItems := []helpItem{header}
 		for _, item := range group[1:] {
-			if strings.Contains(item.searchTerm, query) {
+			// the words of the query can be spread over the mode and the item, e.g. "rebase onto"
+			if item.searchTerm != "" && matches(header.searchTerm+" "+item.searchTerm, query) {
 				matchedItems = append(matchedItems, item)
 			}
 		}
 
 		if len(matchedItems) > 1 {
-			matchedItems = append(matchedItems, helpItem{"", ""})
+			matchedItems = append(matchedItems, helpItem{})
 			filtered = append(filtered, matchedItems)
 		}
 	}
@@ -137,6 +262,20 @@ func filterList(column menuColumn, query string) menuColumn {
 	return filtered
 }
 
+// matches fuzzy matches every word of the query on its own, the same way the fuzzy finders
+// refine their results on each space
+func matches(searchTerm string, query string) bool {
+	if searchTerm == "" {
+		return false
+	}
+	for _, word := range strings.Fields(query) {
+		if len(fuzzy.Find(word, []string{searchTerm})) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func New(context *context.MainContext) *Model {
 	styles := styles{
 		border:   common.DefaultPalette.GetBorder("help border", lipgloss.NormalBorder()).Padding(1),
@@ -144,6 +283,7 @@ func New(context *context.MainContext) *Model {
 		text:     common.DefaultPalette.Get("help text"),
 		dimmed:   common.DefaultPalette.Get("help dimmed").PaddingLeft(1),
 		shortcut: common.DefaultPalette.Get("help shortcut"),
+		selected: common.DefaultPalette.Get("help selected"),
 	}
 
 	filter := textinput.New()
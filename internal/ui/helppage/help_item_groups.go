@@ -2,10 +2,12 @@ package helppage
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
+	customcommands "github.com/idursun/jjui/internal/ui/custom_commands"
 )
 
 func (h *Model) printKeyBinding(k key.Binding) string {
@@ -22,6 +24,11 @@ func (h *Model) printMode(key key.Binding, name string) string {
 	return lipgloss.JoinHorizontal(0, h.styles.shortcut.Render(keyAligned), h.styles.title.Render(name))
 }
 
+// printHighlighted renders the item picked by the filter as a single selected line
+func (h *Model) printHighlighted(key string, desc string) string {
+	return h.styles.selected.Render(fmt.Sprintf("%9s %s", key, desc))
+}
+
 func (h *Model) newModeItem(binding *key.Binding, name string) helpItem {
 	if binding == nil {
 		return helpItem{
@@ -32,17 +39,43 @@ func (h *Model) newModeItem(binding *key.Binding, name string) helpItem {
 
 	help := binding.Help()
 	return helpItem{
-		display:    h.printMode(*binding, name),
-		searchTerm: normalizeSearch(help.Key, help.Desc, name),
+		display:     h.printMode(*binding, name),
+		highlighted: h.printHighlighted(help.Key, name),
+		searchTerm:  normalizeSearch(help.Key, help.Desc, name),
+		keys:        firstKey(*binding),
 	}
 }
 
 func (h *Model) newBindingItem(binding key.Binding) helpItem {
 	help := binding.Help()
 	return helpItem{
-		display:    h.printKeyBinding(binding),
-		searchTerm: normalizeSearch(help.Key, help.Desc),
+		display:     h.printKeyBinding(binding),
+		highlighted: h.printHighlighted(help.Key, help.Desc),
+		searchTerm:  normalizeSearch(help.Key, help.Desc),
+		keys:        firstKey(binding),
+	}
+}
+
+// modeGroup makes the items of the group press the key of the mode first, as their keys only
+// work once the mode is entered
+func (h *Model) modeGroup(group itemGroup) itemGroup {
+	mode := group[0].keys
+	if len(mode) == 0 {
+		return group
+	}
+	for i := 1; i < len(group); i++ {
+		if len(group[i].keys) > 0 {
+			group[i].keys = append(slices.Clone(mode), group[i].keys...)
+		}
 	}
+	return group
+}
+
+func firstKey(binding key.Binding) []string {
+	if keys := binding.Keys(); len(keys) > 0 {
+		return keys[:1]
+	}
+	return nil
 }
 
 func (h *Model) newKeyItem(key string, desc string) helpItem {
@@ -74,7 +107,7 @@ func (h *Model) setDefaultMenu() {
 	}
 	// TODO: 132 is an arbitrary width that allows all column to display properly
 	// update to use dynamic width based on column contents
-	h.defaultMenu.width, h.defaultMenu.height = 132, h.calculateMaxHeight()
+	h.defaultMenu.width, h.defaultMenu.height = 132, menuHeight(h.defaultMenu)
 	h.searchQuery.Width = len(h.searchQuery.Placeholder)
 }
 
@@ -93,71 +126,113 @@ func (h *Model) buildLeftGroups() menuColumn {
 			h.newBindingItem(h.keyMap.Cancel),
 			h.newBindingItem(h.keyMap.Quit),
 			h.newBindingItem(h.keyMap.Suspend),
+			h.newBindingItem(h.keyMap.ExpandMessage),
 			h.newBindingItem(h.keyMap.Revset),
+			h.newBindingItem(h.keyMap.SavedRevsets),
+			h.newBindingItem(h.keyMap.CopyRevset),
+			h.newBindingItem(h.keyMap.PasteRevset),
+			h.newBindingItem(h.keyMap.ResetRevset),
+			h.newBindingItem(h.keyMap.Repositories),
+			h.newBindingItem(h.keyMap.Workspaces),
 		},
 		itemGroup{
 			h.newModeItem(nil, "Exec"),
 			h.newBindingItem(h.keyMap.ExecJJ),
 			h.newBindingItem(h.keyMap.ExecShell),
+			h.newBindingItem(h.keyMap.OpenShell),
 		},
 		itemGroup{
 			h.newModeItem(nil, "Revisions"),
 			h.newKeyItem(jumpKeys, "jump to parent/child/working-copy"),
+			h.newBindingItem(h.keyMap.JumpToTop),
+			h.newBindingItem(h.keyMap.JumpToBottom),
 			h.newBindingItem(h.keyMap.ToggleSelect),
+			h.newBindingItem(h.keyMap.SelectRevset),
+			h.newBindingItem(h.keyMap.InvertSelection),
 			h.newBindingItem(h.keyMap.AceJump),
+			h.newBindingItem(h.keyMap.GotoChange),
 			h.newBindingItem(h.keyMap.QuickSearch),
 			h.newBindingItem(h.keyMap.QuickSearchCycle),
 			h.newBindingItem(h.keyMap.FileSearch.Toggle),
+			h.newBindingItem(h.keyMap.FileSearch.Workspace),
 			h.newBindingItem(h.keyMap.New),
+			h.newBindingItem(h.keyMap.NewDescribed),
+			h.newBindingItem(h.keyMap.NewMerge),
 			h.newBindingItem(h.keyMap.Commit),
 			h.newBindingItem(h.keyMap.Describe),
 			h.newBindingItem(h.keyMap.Edit),
 			h.newBindingItem(h.keyMap.Diff),
+			h.newBindingItem(h.keyMap.DiffTool),
+			h.newBindingItem(h.keyMap.CopyPatch),
+			h.newBindingItem(h.keyMap.ExportGraph),
 			h.newBindingItem(h.keyMap.Diffedit),
 			h.newBindingItem(h.keyMap.Split),
 			h.newBindingItem(h.keyMap.Abandon),
 			h.newBindingItem(h.keyMap.Absorb),
+			h.newBindingItem(h.keyMap.RebaseOntoWorkingCopy),
+			h.newBindingItem(h.keyMap.RebaseRevisionOntoWorkingCopy),
 			h.newBindingItem(h.keyMap.Undo),
+			h.newBindingItem(h.keyMap.UndoMany),
 			h.newBindingItem(h.keyMap.Redo),
 			h.newBindingItem(h.keyMap.Details.Mode),
 			h.newBindingItem(h.keyMap.Bookmark.Set),
+			h.newBindingItem(h.keyMap.Bookmark.Create),
+			h.newBindingItem(h.keyMap.Bookmark.SetMain),
 			h.newBindingItem(h.keyMap.InlineDescribe.Mode),
 			h.newBindingItem(h.keyMap.SetParents),
+			h.newBindingItem(h.keyMap.ToggleTimestamps),
+			h.newBindingItem(h.keyMap.ToggleIdType),
+			h.newBindingItem(h.keyMap.ToggleHidden),
+			h.newBindingItem(h.keyMap.ToggleGraph),
+			h.newBindingItem(h.keyMap.ExpandElided),
+			h.newBindingItem(h.keyMap.CollapseElided),
+			h.newBindingItem(h.keyMap.ToggleDryRun),
+			h.newBindingItem(h.keyMap.ToggleSigning),
 		},
 	}
 }
 
 func (h *Model) buildMiddleGroups() menuColumn {
 	return menuColumn{
-		itemGroup{
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.Details.Mode, "Details"),
 			h.newBindingItem(h.keyMap.Details.Close),
 			h.newBindingItem(h.keyMap.Details.ToggleSelect),
 			h.newBindingItem(h.keyMap.Details.Restore),
 			h.newBindingItem(h.keyMap.Details.Split),
+			h.newBindingItem(h.keyMap.Details.SplitInteractive),
 			h.newBindingItem(h.keyMap.Details.Squash),
 			h.newBindingItem(h.keyMap.Details.Diff),
 			h.newBindingItem(h.keyMap.Details.RevisionsChangingFile),
-			helpItem{"", ""},
-		},
-		itemGroup{
+			h.newBindingItem(h.keyMap.Details.OpenInEditor),
+			h.newBindingItem(h.keyMap.Details.NextConflict),
+			h.newBindingItem(h.keyMap.Details.PrevConflict),
+			h.newBindingItem(h.keyMap.Details.Resolve),
+			h.newBindingItem(h.keyMap.Details.RestoreDeleted),
+			h.newBindingItem(h.keyMap.Details.Follow),
+			h.newBindingItem(h.keyMap.Details.SplitSession),
+			h.newBindingItem(h.keyMap.Details.NextRevision),
+			h.newBindingItem(h.keyMap.Details.PrevRevision),
+			helpItem{},
+		}),
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.Evolog.Mode, "Evolog"),
 			h.newBindingItem(h.keyMap.Evolog.Diff),
 			h.newBindingItem(h.keyMap.Evolog.Restore),
-			helpItem{"", ""},
-		},
-		itemGroup{
+			helpItem{},
+		}),
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.Squash.Mode, "Squash"),
 			h.newBindingItem(h.keyMap.Squash.KeepEmptied),
 			h.newBindingItem(h.keyMap.Squash.UseDestinationMessage),
 			h.newBindingItem(h.keyMap.Squash.Interactive),
-			helpItem{"", ""},
-		},
-		itemGroup{
+			helpItem{},
+		}),
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.Revert.Mode, "Revert"),
-			helpItem{"", ""},
-		},
-		itemGroup{
+			helpItem{},
+		}),
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.Rebase.Mode, "Rebase"),
 			h.newBindingItem(h.keyMap.Rebase.Revision),
 			h.newBindingItem(h.keyMap.Rebase.Source),
@@ -166,21 +241,29 @@ func (h *Model) buildMiddleGroups() menuColumn {
 			h.newBindingItem(h.keyMap.Rebase.After),
 			h.newBindingItem(h.keyMap.Rebase.Onto),
 			h.newBindingItem(h.keyMap.Rebase.Insert),
-			helpItem{"", ""},
-		},
-		itemGroup{
+			h.newBindingItem(h.keyMap.Rebase.Reorder),
+			helpItem{},
+		}),
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.Duplicate.Mode, "Duplicate"),
 			h.newBindingItem(h.keyMap.Duplicate.Onto),
 			h.newBindingItem(h.keyMap.Duplicate.Before),
 			h.newBindingItem(h.keyMap.Duplicate.After),
-		},
+			h.newBindingItem(h.keyMap.Duplicate.Choose),
+			h.newBindingItem(h.keyMap.Duplicate.Reorder),
+		}),
 	}
 }
 
 func (h *Model) buildRightGroups() menuColumn {
-	customCommandItems := []helpItem{h.newModeItem(&h.keyMap.CustomCommands, "Custom Commands")}
-	for _, command := range h.context.CustomCommands {
-		customCommandItems = append(customCommandItems, h.newBindingItem(command.Binding()))
+	customCommandItems := []helpItem{h.newModeItem(&h.keyMap.CustomCommands, "Custom Commands"), h.newBindingItem(h.keyMap.RepeatCommand)}
+	for _, group := range customcommands.GroupedCustomCommands(h.context) {
+		if group.Name != "" {
+			customCommandItems = append(customCommandItems, h.newModeItem(nil, group.Name))
+		}
+		for _, command := range group.Commands {
+			customCommandItems = append(customCommandItems, h.newBindingItem(command.Binding()))
+		}
 	}
 
 	return menuColumn{
@@ -192,34 +275,44 @@ func (h *Model) buildRightGroups() menuColumn {
 			h.newBindingItem(h.keyMap.Preview.HalfPageUp),
 			h.newBindingItem(h.keyMap.Preview.Expand),
 			h.newBindingItem(h.keyMap.Preview.Shrink),
+			h.newBindingItem(h.keyMap.Preview.Search),
+			h.newBindingItem(h.keyMap.Preview.SearchNext),
+			h.newBindingItem(h.keyMap.Preview.SearchPrev),
+			h.newBindingItem(h.keyMap.Preview.Annotate),
 			h.newBindingItem(h.keyMap.Preview.ToggleBottom),
-			helpItem{"", ""},
+			helpItem{},
 		},
-		itemGroup{
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.Git.Mode, "Git"),
 			h.newBindingItem(h.keyMap.Git.Push),
 			h.newBindingItem(h.keyMap.Git.Fetch),
-			helpItem{"", ""},
-		},
-		itemGroup{
+			h.newBindingItem(h.keyMap.Git.FetchAll),
+			h.newBindingItem(h.keyMap.Git.Remote),
+			helpItem{},
+		}),
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.Bookmark.Mode, "Bookmarks"),
 			h.newBindingItem(h.keyMap.Bookmark.Move),
 			h.newBindingItem(h.keyMap.Bookmark.Delete),
 			h.newBindingItem(h.keyMap.Bookmark.Untrack),
 			h.newBindingItem(h.keyMap.Bookmark.Track),
 			h.newBindingItem(h.keyMap.Bookmark.Forget),
-			helpItem{"", ""},
-		},
-		itemGroup{
+			h.newBindingItem(h.keyMap.Bookmark.Rename),
+			helpItem{},
+		}),
+		h.modeGroup(itemGroup{
 			h.newModeItem(&h.keyMap.OpLog.Mode, "Oplog"),
 			h.newBindingItem(h.keyMap.Diff),
 			h.newBindingItem(h.keyMap.OpLog.Restore),
-			helpItem{"", ""},
-		},
+			h.newBindingItem(h.keyMap.OpLog.LoadMore),
+			h.newBindingItem(h.keyMap.OpLog.ViewAt),
+			h.newBindingItem(h.keyMap.OpLog.ViewLatest),
+			helpItem{},
+		}),
 
 		itemGroup{
 			h.newModeItem(&h.keyMap.Leader, "Leader"),
-			helpItem{"", ""},
+			helpItem{},
 		},
 		customCommandItems,
 	}
@@ -6,11 +6,12 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-func (h *Model) calculateMaxHeight() int {
+// menuHeight is the number of lines of the longest column of the menu
+func menuHeight(menu helpMenu) int {
 	return max(
-		getListHeight(h.defaultMenu.leftList),
-		getListHeight(h.defaultMenu.middleList),
-		getListHeight(h.defaultMenu.rightList),
+		getListHeight(menu.leftList),
+		getListHeight(menu.middleList),
+		getListHeight(menu.rightList),
 	)
 }
 
@@ -22,7 +23,9 @@ func getListHeight(list menuColumn) int {
 	return height
 }
 
-func (h *Model) renderColumn(column menuColumn) string {
+// renderColumn renders the items of the column, the selected-th item that can be picked is
+// highlighted. It returns the number of items that can be picked in the column.
+func (h *Model) renderColumn(column menuColumn, selected int) (string, int) {
 	// NOTE: read from defaultMenu so layout won't glitch while filtering menu
 	width := h.defaultMenu.width / 3
 	height := h.defaultMenu.height
@@ -35,9 +38,17 @@ func (h *Model) renderColumn(column menuColumn) string {
 		)
 	}
 
+	selectable := 0
 	for _, group := range column {
 		for _, item := range group {
-			lines = append(lines, formatLine(item.display))
+			display := item.display
+			if len(item.keys) > 0 {
+				if selectable == selected {
+					display = item.highlighted
+				}
+				selectable++
+			}
+			lines = append(lines, formatLine(display))
 		}
 	}
 
@@ -45,7 +56,9 @@ func (h *Model) renderColumn(column menuColumn) string {
 		lines = append(lines, formatLine(""))
 	}
 
-	return strings.Join(lines, "\n")
+	visible := min(height, h.visibleHeight())
+	offset := max(0, min(h.scrollOffset, height-visible))
+	return strings.Join(lines[offset:offset+visible], "\n"), selectable
 }
 
 func (h *Model) renderMenu() string {
@@ -53,9 +66,14 @@ func (h *Model) renderMenu() string {
 		h.filteredMenu = h.defaultMenu
 	}
 
-	left := h.renderColumn(h.filteredMenu.leftList)
-	middle := h.renderColumn(h.filteredMenu.middleList)
-	right := h.renderColumn(h.filteredMenu.rightList)
+	// nothing is highlighted until the menu is filtered
+	selected := -1
+	if h.query != "" {
+		selected = h.selected
+	}
+	left, n := h.renderColumn(h.filteredMenu.leftList, selected)
+	middle, m := h.renderColumn(h.filteredMenu.middleList, selected-n)
+	right, _ := h.renderColumn(h.filteredMenu.rightList, selected-n-m)
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, left, middle, right)
 }
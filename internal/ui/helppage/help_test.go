@@ -152,3 +152,90 @@ func TestHelpModelCloseCommands(t *testing.T) {
 		})
 	}
 }
+
+func TestHelpMenuScrollsWhenItDoesNotFit(t *testing.T) {
+	ctx := &appContext.MainContext{
+		CustomCommands: map[string]appContext.CustomCommand{},
+	}
+	model := helppage.New(ctx)
+	model.Parent = common.NewViewNode(140, 20)
+	test.SimulateModel(model, model.Init())
+
+	before := model.View()
+	assert.Equal(t, 20, lipgloss.Height(before))
+
+	model.Scroll(3)
+	assert.NotEqual(t, before, model.View())
+
+	model.Scroll(-3)
+	assert.Equal(t, before, model.View())
+}
+
+func TestHelpMenuPicksTheSelectedItemWithEnter(t *testing.T) {
+	ctx := &appContext.MainContext{
+		CustomCommands: map[string]appContext.CustomCommand{},
+	}
+	model := helppage.New(ctx)
+	model.Parent = common.NewViewNode(140, 60)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Type("rebase onto"))
+	assert.Contains(t, test.Stripped(model.View()), "onto")
+
+	var msgs []tea.Msg
+	test.SimulateModel(model, test.Press(tea.KeyEnter), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Equal(t, []tea.Msg{
+		tea.KeyMsg{Type: tea.KeyEnter},
+		common.CloseViewMsg{},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")},
+	}, msgs[:4])
+}
+
+func TestHelpMenuCancelClearsTheFilterFirst(t *testing.T) {
+	ctx := &appContext.MainContext{
+		CustomCommands: map[string]appContext.CustomCommand{},
+	}
+	model := helppage.New(ctx)
+	model.Parent = common.NewViewNode(140, 60)
+	test.SimulateModel(model, model.Init())
+	defaultView := model.View()
+
+	test.SimulateModel(model, test.Type("abandon"))
+	assert.NotEqual(t, defaultView, model.View())
+
+	var msgs []tea.Msg
+	test.SimulateModel(model, test.Press(tea.KeyEscape), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.NotContains(t, msgs, common.CloseViewMsg{})
+	assert.Equal(t, defaultView, model.View())
+
+	test.SimulateModel(model, test.Press(tea.KeyEscape), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Contains(t, msgs, common.CloseViewMsg{})
+}
+
+func TestHelpMenuScrollsToTheSelectedItem(t *testing.T) {
+	ctx := &appContext.MainContext{
+		CustomCommands: map[string]appContext.CustomCommand{},
+	}
+	model := helppage.New(ctx)
+	model.Parent = common.NewViewNode(140, 12)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Type("e"))
+	top := model.View()
+	for range 10 {
+		test.SimulateModel(model, test.Press(tea.KeyDown))
+	}
+	assert.NotEqual(t, top, model.View(), "the menu follows the selection down")
+
+	for range 10 {
+		test.SimulateModel(model, test.Press(tea.KeyUp))
+	}
+	assert.Equal(t, top, model.View(), "the menu follows the selection back up")
+}
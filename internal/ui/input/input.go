@@ -79,7 +79,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			return newCmd(CancelledMsg{})
 		default:
 			var cmd tea.Cmd
-			m.input, cmd = m.input.Update(msg)
+			m.input, cmd = m.input.Update(common.SingleLinePaste(msg))
 			return cmd
 		}
 	case common.CloseViewMsg:
@@ -88,6 +88,11 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// SetValue prefills the input, e.g. with a suggested value the user can edit
+func (m *Model) SetValue(value string) {
+	m.input.SetValue(value)
+}
+
 func (m *Model) selectCurrent() tea.Cmd {
 	value := m.input.Value()
 	return newCmd(SelectedMsg{Value: value})
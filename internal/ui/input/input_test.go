@@ -3,6 +3,7 @@ package input
 import (
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -81,3 +82,13 @@ func TestModel_SelectCurrent_Empty(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, "", selectedMsg.Value)
 }
+
+func TestModel_Paste_CollapsesLines(t *testing.T) {
+	model := New()
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("name: "))
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("first\nsecond\r\n"), Paste: true})
+
+	msg := model.selectCurrent()()
+	assert.Equal(t, SelectedMsg{Value: "name: first second"}, msg)
+}
@@ -11,3 +11,17 @@ func (AddMessage) isIntent() {}
 type DismissOldest struct{}
 
 func (DismissOldest) isIntent() {}
+
+type AddProgress struct {
+	Id   string
+	Text string
+}
+
+func (AddProgress) isIntent() {}
+
+type ResolveProgress struct {
+	Id  string
+	Err error
+}
+
+func (ResolveProgress) isIntent() {}
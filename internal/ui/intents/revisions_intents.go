@@ -6,13 +6,17 @@ import (
 	"github.com/idursun/jjui/internal/ui/operations/revert"
 )
 
-type OpenDetails struct{}
+type OpenDetails struct {
+	File string // the file the cursor is placed on once the files are loaded
+}
 
 func (OpenDetails) isIntent() {}
 
 type StartSquash struct {
 	Selected jj.SelectedRevisions
 	Files    []string
+	// DescribeDestination prompts for the description of the destination once the squash succeeds
+	DescribeDestination bool
 }
 
 func (StartSquash) isIntent() {}
@@ -25,6 +29,15 @@ type StartRebase struct {
 
 func (StartRebase) isIntent() {}
 
+// RebaseOntoWorkingCopy rebases the revision with its descendants onto @, or only the revision when
+// Source is rebase.SourceRevision
+type RebaseOntoWorkingCopy struct {
+	Selected *jj.Commit
+	Source   rebase.Source
+}
+
+func (RebaseOntoWorkingCopy) isIntent() {}
+
 type StartRevert struct {
 	Selected jj.SelectedRevisions
 	Target   revert.Target
@@ -56,6 +69,26 @@ type ShowDiff struct {
 
 func (ShowDiff) isIntent() {}
 
+type CopyPatch struct {
+	Selected jj.SelectedRevisions
+}
+
+func (CopyPatch) isIntent() {}
+
+// CreateBookmark creates a bookmark at the revision with a name generated from its description
+type CreateBookmark struct {
+	Selected *jj.Commit
+}
+
+func (CreateBookmark) isIntent() {}
+
+// SetMainBookmark moves git.main_bookmark to the revision after a confirmation
+type SetMainBookmark struct {
+	Selected *jj.Commit
+}
+
+func (SetMainBookmark) isIntent() {}
+
 type StartSplit struct {
 	Selected   *jj.Commit
 	IsParallel bool
@@ -71,6 +104,8 @@ const (
 	TargetParent
 	TargetChild
 	TargetWorkingCopy
+	TargetTop
+	TargetBottom
 )
 
 type Navigate struct {
@@ -86,11 +121,18 @@ type Navigate struct {
 func (Navigate) isIntent() {}
 
 type StartNew struct {
-	Selected jj.SelectedRevisions
+	Selected        jj.SelectedRevisions
+	WithDescription bool
 }
 
 func (StartNew) isIntent() {}
 
+type StartNewMerge struct {
+	Selected jj.SelectedRevisions
+}
+
+func (StartNewMerge) isIntent() {}
+
 type CommitWorkingCopy struct{}
 
 func (CommitWorkingCopy) isIntent() {}
@@ -102,6 +144,13 @@ type StartEdit struct {
 
 func (StartEdit) isIntent() {}
 
+// OpenShell edits the selected revision and opens ui.shell in the working copy
+type OpenShell struct {
+	Selected *jj.Commit
+}
+
+func (OpenShell) isIntent() {}
+
 type StartDiffEdit struct {
 	Selected *jj.Commit
 }
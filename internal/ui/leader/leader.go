@@ -3,18 +3,22 @@ package leader
 import (
 	"maps"
 	"slices"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	customcommands "github.com/idursun/jjui/internal/ui/custom_commands"
 )
 
 type Model struct {
-	cancel  key.Binding
-	shown   context.LeaderMap
-	context *context.MainContext
+	cancel     key.Binding
+	shown      context.LeaderMap
+	context    *context.MainContext
+	timeout    time.Duration
+	generation int
 }
 
 func New(ctx *context.MainContext) *Model {
@@ -22,18 +26,32 @@ func New(ctx *context.MainContext) *Model {
 	m := &Model{
 		context: ctx,
 		cancel:  keyMap.Cancel,
+		timeout: time.Duration(keyMap.LeaderTimeoutMs) * time.Millisecond,
 	}
 	return m
 }
 
 func (m *Model) ShortHelp() []key.Binding {
 	bindings := []key.Binding{m.cancel}
-	for m := range maps.Values(m.shown) {
-		bindings = append(bindings, *m.Bind)
+	for _, l := range ordered(m.shown) {
+		bindings = append(bindings, *l.Bind)
 	}
 	return bindings
 }
 
+// ordered returns the entries by their configured order, then by their keys
+func ordered(bnds context.LeaderMap) []*context.Leader {
+	names := slices.Sorted(maps.Keys(bnds))
+	slices.SortStableFunc(names, func(a, b string) int {
+		return bnds[a].Order - bnds[b].Order
+	})
+	entries := make([]*context.Leader, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, bnds[name])
+	}
+	return entries
+}
+
 func (m *Model) FullHelp() [][]key.Binding {
 	bindings := slices.Collect(slices.Chunk(m.ShortHelp(), 6))
 	return bindings
@@ -41,6 +59,13 @@ func (m *Model) FullHelp() [][]key.Binding {
 
 type initMsg struct{}
 
+// timeoutMsg is sent when the countdown of the menu is over, the menu it was scheduled by tells
+// apart the timeout of a menu that was already closed from the ones of the menu opened after it
+type timeoutMsg struct {
+	leader     *Model
+	generation int
+}
+
 func InitCmd() tea.Msg {
 	return initMsg{}
 }
@@ -53,6 +78,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case initMsg:
 		m.shown = contextEnabled(m.context, m.context.Leader)
+		return m.scheduleTimeout()
+	case timeoutMsg:
+		if msg.leader != m || msg.generation != m.generation {
+			return nil
+		}
+		m.shown = nil
+		return common.Close
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.cancel):
@@ -63,9 +95,12 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			if key.Matches(msg, *c.Bind) {
 				if len(c.Nest) > 0 {
 					m.shown = contextEnabled(m.context, c.Nest)
-					return nil
+					return m.scheduleTimeout()
 				}
 				m.shown = nil
+				if c.Command != "" {
+					return tea.Sequence(common.Close, customcommands.Run(m.context, m.context.CustomCommands[c.Command]))
+				}
 				cmds := sendCmds(c.Send)
 				return tea.Sequence(
 					common.Close,
@@ -77,11 +112,26 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// scheduleTimeout restarts the countdown after which the leader menu is dismissed
+func (m *Model) scheduleTimeout() tea.Cmd {
+	m.generation++
+	if m.timeout <= 0 {
+		return nil
+	}
+	generation := m.generation
+	return tea.Tick(m.timeout, func(time.Time) tea.Msg {
+		return timeoutMsg{leader: m, generation: generation}
+	})
+}
+
 func contextEnabled(ctx *context.MainContext, bnds context.LeaderMap) context.LeaderMap {
 	bnds = maps.Clone(bnds)
 	replacementKeys := slices.Collect(maps.Keys(ctx.CreateReplacements()))
 	maps.DeleteFunc(bnds, func(k string, v *context.Leader) bool {
-		if v == nil {
+		if v == nil || v.Unresolved(ctx.CustomCommands) {
+			return true
+		}
+		if command, ok := ctx.CustomCommands[v.Command]; ok && !command.IsApplicableTo(ctx.SelectedItem) {
 			return true
 		}
 		for _, key := range v.Context {
@@ -102,7 +152,7 @@ func sendCmds(strings []string) []tea.Cmd {
 		})
 	}
 	for _, s := range strings {
-		if k, ok := keyNames[s]; ok {
+		if k, ok := common.KeyByName(s); ok {
 			send(k)
 		} else {
 			for _, r := range s {
@@ -115,113 +165,3 @@ func sendCmds(strings []string) []tea.Cmd {
 	}
 	return cmds
 }
-
-// From bubbletea's key.go. So that we can identify by their string.
-// Notable Exception: tea.KeyRunes. Because we create them.
-var keyTypes = []tea.KeyType{
-	// Control keys.
-	tea.KeyTab,
-	tea.KeyEnter,
-	tea.KeyEsc,
-	tea.KeyBackspace,
-
-	tea.KeyCtrlAt,
-	tea.KeyCtrlA,
-	tea.KeyCtrlB,
-	tea.KeyCtrlC,
-	tea.KeyCtrlD,
-	tea.KeyCtrlE,
-	tea.KeyCtrlF,
-	tea.KeyCtrlG,
-	tea.KeyCtrlH,
-	tea.KeyCtrlJ,
-	tea.KeyCtrlK,
-	tea.KeyCtrlL,
-	tea.KeyCtrlN,
-	tea.KeyCtrlO,
-	tea.KeyCtrlP,
-	tea.KeyCtrlQ,
-	tea.KeyCtrlR,
-	tea.KeyCtrlS,
-	tea.KeyCtrlT,
-	tea.KeyCtrlU,
-	tea.KeyCtrlV,
-	tea.KeyCtrlW,
-	tea.KeyCtrlX,
-	tea.KeyCtrlY,
-	tea.KeyCtrlZ,
-
-	tea.KeyCtrlCloseBracket,
-	tea.KeyCtrlCaret,
-	tea.KeyCtrlUnderscore,
-	tea.KeyCtrlBackslash,
-
-	// Other keys.
-	tea.KeyUp,
-	tea.KeyDown,
-	tea.KeyRight,
-	tea.KeySpace,
-	tea.KeyLeft,
-	tea.KeyShiftTab,
-	tea.KeyHome,
-	tea.KeyEnd,
-	tea.KeyCtrlHome,
-	tea.KeyCtrlEnd,
-	tea.KeyShiftHome,
-	tea.KeyShiftEnd,
-	tea.KeyCtrlShiftHome,
-	tea.KeyCtrlShiftEnd,
-	tea.KeyPgUp,
-	tea.KeyPgDown,
-	tea.KeyCtrlPgUp,
-	tea.KeyCtrlPgDown,
-	tea.KeyDelete,
-	tea.KeyInsert,
-	tea.KeyCtrlUp,
-	tea.KeyCtrlDown,
-	tea.KeyCtrlRight,
-	tea.KeyCtrlLeft,
-	tea.KeyShiftUp,
-	tea.KeyShiftDown,
-	tea.KeyShiftRight,
-	tea.KeyShiftLeft,
-	tea.KeyCtrlShiftUp,
-	tea.KeyCtrlShiftDown,
-	tea.KeyCtrlShiftLeft,
-	tea.KeyCtrlShiftRight,
-	tea.KeyF1,
-	tea.KeyF2,
-	tea.KeyF3,
-	tea.KeyF4,
-	tea.KeyF5,
-	tea.KeyF6,
-	tea.KeyF7,
-	tea.KeyF8,
-	tea.KeyF9,
-	tea.KeyF10,
-	tea.KeyF11,
-	tea.KeyF12,
-	tea.KeyF13,
-	tea.KeyF14,
-	tea.KeyF15,
-	tea.KeyF16,
-	tea.KeyF17,
-	tea.KeyF18,
-	tea.KeyF19,
-	tea.KeyF20,
-}
-
-func keysFromTypes() map[string]tea.Key {
-	m := map[string]tea.Key{}
-	set := func(t tea.KeyType) {
-		m[t.String()] = tea.Key{
-			Type: t,
-		}
-	}
-	for _, t := range keyTypes {
-		set(t)
-	}
-	return m
-}
-
-var keyNames = keysFromTypes()
@@ -2,10 +2,12 @@ package leader
 
 import (
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	customcommands "github.com/idursun/jjui/internal/ui/custom_commands"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -167,3 +169,149 @@ send = ["?"]
 		t.Errorf("expected CloseViewMsg, got %T", msgOut)
 	}
 }
+
+func TestUpdate_timeout_closes_menu(t *testing.T) {
+	content := `[leader.h]
+help = "Help"
+send = ["?"]
+`
+	lm, err := context.LoadLeader(content)
+	if err != nil {
+		t.Fatalf("LoadLeader failed: %v", err)
+	}
+	model := New(&context.MainContext{Leader: lm})
+	model.timeout = time.Millisecond
+	cmd := model.Update(initMsg{})
+	if cmd == nil {
+		t.Fatal("expected a timeout to be scheduled")
+	}
+	msg := cmd()
+	assert.IsType(t, timeoutMsg{}, msg)
+	cmd = model.Update(msg)
+	if cmd == nil {
+		t.Fatal("expected a command returned from Update")
+	}
+	assert.Equal(t, common.CloseViewMsg{}, cmd())
+	assert.Empty(t, model.shown)
+}
+
+func TestUpdate_stale_timeout_is_ignored(t *testing.T) {
+	content := `[leader.g]
+help = "Git"
+
+[leader.gf]
+help = "Git Fetch"
+send = ["gf"]
+`
+	lm, err := context.LoadLeader(content)
+	if err != nil {
+		t.Fatalf("LoadLeader failed: %v", err)
+	}
+	model := New(&context.MainContext{Leader: lm})
+	model.timeout = time.Millisecond
+	stale := model.Update(initMsg{})()
+	_ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+
+	assert.Nil(t, model.Update(stale))
+	assert.NotEmpty(t, model.shown)
+}
+
+func TestUpdate_timeout_of_closed_menu_is_ignored(t *testing.T) {
+	content := `[leader.h]
+help = "Help"
+send = ["?"]
+`
+	lm, err := context.LoadLeader(content)
+	if err != nil {
+		t.Fatalf("LoadLeader failed: %v", err)
+	}
+	ctx := &context.MainContext{Leader: lm}
+	closed := New(ctx)
+	closed.timeout = time.Millisecond
+	stale := closed.Update(initMsg{})()
+	assert.Equal(t, common.CloseViewMsg{}, closed.Update(tea.KeyMsg{Type: tea.KeyEsc})())
+
+	reopened := New(ctx)
+	reopened.timeout = time.Millisecond
+	_ = reopened.Update(initMsg{})
+
+	assert.Nil(t, reopened.Update(stale))
+	assert.NotEmpty(t, reopened.shown)
+}
+
+func TestUpdate_zero_timeout_is_disabled(t *testing.T) {
+	model := New(&context.MainContext{})
+	model.timeout = 0
+	assert.Nil(t, model.Update(initMsg{}))
+}
+
+func TestShortHelp_orders_entries(t *testing.T) {
+	lm, err := context.LoadLeader(`
+[leader.a]
+help = "A"
+
+[leader.b]
+help = "B"
+order = -1
+
+[leader.c]
+help = "C"
+
+[leader.d]
+command = "missing"
+`)
+	assert.NoError(t, err)
+	model := New(&context.MainContext{Leader: lm})
+	_ = model.Update(initMsg{})
+
+	var descriptions []string
+	for _, binding := range model.ShortHelp()[1:] {
+		descriptions = append(descriptions, binding.Help().Desc)
+	}
+	assert.Equal(t, []string{"B", "A", "C"}, descriptions)
+}
+
+func TestUpdate_runs_custom_command(t *testing.T) {
+	lm, err := context.LoadLeader(`
+[leader.m]
+command = "mine"
+`)
+	assert.NoError(t, err)
+	ctx := &context.MainContext{
+		Leader:         lm,
+		SelectedItem:   context.SelectedRevision{ChangeId: "abc"},
+		CustomCommands: map[string]context.CustomCommand{"mine": context.CustomRevsetCommand{Revset: "$change_id::"}},
+	}
+	model := New(ctx)
+	_ = model.Update(initMsg{})
+	assert.Equal(t, "mine", model.ShortHelp()[1].Help().Desc)
+
+	var msgs []tea.Msg
+	test.SimulateModel(model, model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}}), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Contains(t, msgs, common.CloseViewMsg{})
+	assert.Contains(t, msgs, common.UpdateRevSetMsg("abc::"))
+}
+
+func TestUpdate_reports_the_custom_command_it_ran(t *testing.T) {
+	lm, err := context.LoadLeader(`
+[leader.f]
+command = "flash"
+`)
+	assert.NoError(t, err)
+	command := context.CustomLuaCommand{Script: `flash("hi")`}
+	ctx := &context.MainContext{
+		Leader:         lm,
+		CustomCommands: map[string]context.CustomCommand{"flash": command},
+	}
+	model := New(ctx)
+	_ = model.Update(initMsg{})
+
+	var msgs []tea.Msg
+	test.SimulateModel(model, model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}}), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Contains(t, msgs, common.RunLuaScriptMsg{Script: `flash("hi")`})
+	assert.Contains(t, msgs, customcommands.RanMsg{Command: command})
+}
@@ -91,6 +91,7 @@ func NewOperation(context *context.MainContext, selectedRevisions jj.SelectedRev
 		confirmation.WithAltOption("Yes", cmd(false), cmd(true), key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
 		confirmation.WithOption("No", common.Close, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
 		confirmation.WithStylePrefix("abandon"),
+		confirmation.WithDefaultNo(),
 	)
 
 	op := &Operation{
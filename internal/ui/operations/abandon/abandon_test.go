@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
 )
 
 var commit = &jj.Commit{ChangeId: "a"}
@@ -19,6 +20,17 @@ func Test_Accept(t *testing.T) {
 	model := NewOperation(test.NewTestContext(commandRunner), revisions)
 	test.SimulateModel(model, model.Init())
 
+	model.SetSelectedRevision(commit)
+	test.SimulateModel(model, test.Type("y"))
+}
+
+func Test_EnterDefaultsToNo(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), revisions)
+	test.SimulateModel(model, model.Init())
+
 	model.SetSelectedRevision(commit)
 	test.SimulateModel(model, test.Press(tea.KeyEnter))
 }
@@ -34,3 +46,14 @@ func Test_Cancel(t *testing.T) {
 
 	test.SimulateModel(model, test.Press(tea.KeyEsc))
 }
+
+func Test_ConfirmationListsNumberOfRevisions(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	other := &jj.Commit{ChangeId: "b"}
+	model := NewOperation(test.NewTestContext(commandRunner), jj.NewSelectedRevisions(commit, other))
+	test.SimulateModel(model, model.Init())
+
+	assert.Contains(t, test.Stripped(model.View()), "abandon 2 revisions?")
+}
@@ -0,0 +1,51 @@
+package bookmark
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxGeneratedNameLength keeps the names derived from long descriptions readable
+const maxGeneratedNameLength = 50
+
+// GenerateName derives a bookmark name from the first line of the description, falling back to a
+// timestamp when the description has nothing to use. A numeric suffix is appended when the name
+// is already taken by one of the existing bookmarks.
+func GenerateName(description string, existing []string, now time.Time) string {
+	name := slugify(strings.SplitN(description, "\n", 2)[0])
+	if name == "" {
+		name = now.Format("bookmark-20060102-150405")
+	}
+	candidate := name
+	for i := 2; slices.Contains(existing, candidate); i++ {
+		candidate = name + "-" + strconv.Itoa(i)
+	}
+	return candidate
+}
+
+// slugify lowercases the text and joins its words with dashes
+func slugify(text string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingDash = true
+	}
+	slug := b.String()
+	if len(slug) > maxGeneratedNameLength {
+		slug = slug[:maxGeneratedNameLength]
+		if i := strings.LastIndexByte(slug, '-'); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	return slug
+}
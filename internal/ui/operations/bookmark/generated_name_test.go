@@ -0,0 +1,29 @@
+package bookmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateName(t *testing.T) {
+	now := time.Date(2025, 3, 4, 15, 16, 17, 0, time.UTC)
+	tests := []struct {
+		name        string
+		description string
+		existing    []string
+		expected    string
+	}{
+		{"uses the first line", "Fix: the parser's  error\n\nbody", nil, "fix-the-parser-s-error"},
+		{"falls back to a timestamp", "", nil, "bookmark-20250304-151617"},
+		{"ignores symbols only", "!!!", nil, "bookmark-20250304-151617"},
+		{"appends a suffix on collision", "fix bug", []string{"fix-bug", "fix-bug-2"}, "fix-bug-3"},
+		{"cuts long names at a word", "this description is way too long to be used as the name of a bookmark", nil, "this-description-is-way-too-long-to-be-used-as"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GenerateName(tt.description, tt.existing, now))
+		})
+	}
+}
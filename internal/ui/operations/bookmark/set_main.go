@@ -0,0 +1,83 @@
+package bookmark
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/operations"
+)
+
+var (
+	_ operations.Operation = (*SetMainOperation)(nil)
+	_ common.Editable      = (*SetMainOperation)(nil)
+)
+
+// SetMainOperation asks for a confirmation before moving the main bookmark to a revision
+type SetMainOperation struct {
+	model   *confirmation.Model
+	current *jj.Commit
+}
+
+func (s *SetMainOperation) IsEditing() bool {
+	return true
+}
+
+func (s *SetMainOperation) Init() tea.Cmd {
+	return nil
+}
+
+func (s *SetMainOperation) Update(msg tea.Msg) tea.Cmd {
+	return s.model.Update(msg)
+}
+
+func (s *SetMainOperation) View() string {
+	return s.model.View()
+}
+
+func (s *SetMainOperation) ShortHelp() []key.Binding {
+	return s.model.ShortHelp()
+}
+
+func (s *SetMainOperation) FullHelp() [][]key.Binding {
+	return [][]key.Binding{s.ShortHelp()}
+}
+
+func (s *SetMainOperation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
+	s.current = commit
+	return nil
+}
+
+func (s *SetMainOperation) Render(commit *jj.Commit, pos operations.RenderPosition) string {
+	isSelected := commit != nil && commit.GetChangeId() == s.current.GetChangeId()
+	if !isSelected || pos != operations.RenderPositionAfter {
+		return ""
+	}
+	return s.View()
+}
+
+func (s *SetMainOperation) Name() string {
+	return "bookmark"
+}
+
+// NewSetMainOperation moves the bookmark with the given name to the revision, refreshing the
+// revisions afterwards so that trunk() is highlighted at its new place
+func NewSetMainOperation(context *context.MainContext, commit *jj.Commit, name string) *SetMainOperation {
+	message := fmt.Sprintf("Are you sure you want to move %s to %s?", name, commit.GetChangeId())
+	cmd := context.RunCommand(jj.BookmarkSet(commit.GetChangeId(), name), common.Refresh, common.Close)
+	model := confirmation.New(
+		[]string{message},
+		confirmation.WithOption("Yes", cmd, key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+		confirmation.WithOption("No", common.Close, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+		confirmation.WithStylePrefix("bookmark"),
+	)
+
+	return &SetMainOperation{
+		model:   model,
+		current: commit,
+	}
+}
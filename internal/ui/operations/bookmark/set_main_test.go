@@ -0,0 +1,61 @@
+package bookmark
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMain_Accept(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkSet("a", "trunk"))
+	defer commandRunner.Verify()
+
+	model := NewSetMainOperation(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: "a"}, "trunk")
+	test.SimulateModel(model, model.Init())
+
+	refreshed := false
+	test.SimulateModel(model, test.Type("y"), func(msg tea.Msg) {
+		if _, ok := msg.(common.RefreshMsg); ok {
+			refreshed = true
+		}
+	})
+	assert.True(t, refreshed)
+}
+
+func TestSetMain_Cancel(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewSetMainOperation(test.NewTestContext(commandRunner), &jj.Commit{ChangeId: "a"}, "main")
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Press(tea.KeyEsc))
+}
+
+func TestSetMain_DryRun(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.DryRun = true
+	model := NewSetMainOperation(ctx, &jj.Commit{ChangeId: "a"}, "main")
+	test.SimulateModel(model, model.Init())
+
+	var output string
+	refreshed := false
+	test.SimulateModel(model, test.Type("y"), func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case common.CommandCompletedMsg:
+			output = msg.Output
+		case common.RefreshMsg:
+			refreshed = true
+		}
+	})
+	assert.Equal(t, "dry run: jj bookmark set -r a main", output)
+	assert.True(t, refreshed)
+}
@@ -139,6 +139,7 @@ func NewOperation(context *context.MainContext, revision *jj.Commit) *Operation
 	input.CharLimit = 0
 	input.MaxHeight = 10
 	input.Prompt = ""
+	input.Placeholder = "(no description set)"
 	input.ShowLineNumbers = false
 	input.FocusedStyle.Base = selectedStyle.Underline(false).Strikethrough(false).Reverse(false).Blink(false)
 	input.FocusedStyle.CursorLine = input.FocusedStyle.Base
@@ -2,6 +2,8 @@ package details
 
 import (
 	"bufio"
+	stdcontext "context"
+	"errors"
 	"fmt"
 	"path"
 	"reflect"
@@ -9,6 +11,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,6 +19,7 @@ import (
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/common/list"
 	"github.com/idursun/jjui/internal/ui/confirmation"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/intents"
@@ -25,12 +29,26 @@ import (
 type updateCommitStatusMsg struct {
 	summary       string
 	selectedFiles []string
+	err           error
+	stat          string
 }
 
 var (
 	_ operations.Operation = (*Operation)(nil)
 	_ common.Focusable     = (*Operation)(nil)
 	_ common.Overlay       = (*Operation)(nil)
+	_ common.Editable      = (*Operation)(nil)
+	_ common.IMouseAware   = (*Operation)(nil)
+)
+
+// doubleClickInterval is the longest time between two clicks on the same file that opens its diff
+const doubleClickInterval = 500 * time.Millisecond
+
+// ids of the confirmations that can be suppressed for the rest of the session
+const (
+	restoreConfirmation        = "details restore"
+	restoreDeletedConfirmation = "details restore deleted"
+	absorbConfirmation         = "details absorb"
 )
 
 type Operation struct {
@@ -43,6 +61,29 @@ type Operation struct {
 	confirmation      *confirmation.Model
 	keyMap            config.KeyMappings[key.Binding]
 	styles            styles
+	cancelLoad        stdcontext.CancelFunc
+	count             list.CountPrefix
+	stat              string
+	fileToSelect      string
+	lastClickIndex    int
+	lastClickTime     time.Time
+	// follow reloads the details for the revision the cursor moves to
+	follow bool
+	// splitting splits the checked files out without asking and moves on to the rest of the files
+	splitting bool
+}
+
+// restoreDeletedMsg carries the parents of the revision a deleted file is restored in
+type restoreDeletedMsg struct {
+	changeId string
+	file     string
+	parents  []string
+	err      error
+}
+
+type splitOutMsg struct {
+	files       []string
+	description string
 }
 
 func (s *Operation) IsOverlay() bool {
@@ -53,6 +94,10 @@ func (s *Operation) IsFocused() bool {
 	return true
 }
 
+func (s *Operation) IsEditing() bool {
+	return s.confirmation != nil
+}
+
 func (s *Operation) Init() tea.Cmd {
 	return s.load(s.revision.GetChangeId())
 }
@@ -61,12 +106,24 @@ func (s *Operation) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case confirmation.CloseMsg:
 		s.confirmation = nil
-		s.selectedHint = ""
-		s.unselectedHint = ""
+		s.setSplitHints()
 		return nil
+	case splitOutMsg:
+		return s.context.RunCommand(jj.SplitWithMessage(s.revision.GetChangeId(), msg.files, msg.description), s.selectRemaining(s.revision))
 	case common.RefreshMsg:
 		return s.load(s.revision.GetChangeId())
+	case restoreDeletedMsg:
+		return s.confirmRestoreDeleted(msg)
 	case updateCommitStatusMsg:
+		s.cancelLoad = nil
+		if msg.err != nil {
+			return func() tea.Msg {
+				return common.CommandCompletedMsg{
+					Output: msg.summary,
+					Err:    msg.err,
+				}
+			}
+		}
 		items := s.createListItems(msg.summary, msg.selectedFiles)
 		s.context.ClearCheckedItems(reflect.TypeFor[context.SelectedFile]())
 
@@ -81,6 +138,13 @@ func (s *Operation) Update(msg tea.Msg) tea.Cmd {
 			}
 		}
 		s.setItems(items)
+		s.stat = msg.stat
+		if s.fileToSelect != "" {
+			if idx := slices.IndexFunc(items, func(it *item) bool { return it.fileName == s.fileToSelect }); idx >= 0 {
+				s.cursor = idx
+			}
+			s.fileToSelect = ""
+		}
 
 		// Set selection to current cursor position
 		var selectionChangedCmd tea.Cmd
@@ -97,29 +161,121 @@ func (s *Operation) Update(msg tea.Msg) tea.Cmd {
 		var cmds []tea.Cmd
 		cmds = append(cmds, s.internalUpdate(msg))
 		if s.cursor != oldCursor {
-			cmds = append(cmds, s.context.SetSelectedItem(context.SelectedFile{
-				ChangeId: s.revision.GetChangeId(),
-				CommitId: s.revision.CommitId,
-				File:     s.current().fileName,
-			}))
+			cmds = append(cmds, s.updateSelection())
 		}
 		return tea.Batch(cmds...)
 	}
 }
 
+func (s *Operation) updateSelection() tea.Cmd {
+	return s.context.SetSelectedItem(context.SelectedFile{
+		ChangeId: s.revision.GetChangeId(),
+		CommitId: s.revision.CommitId,
+		File:     s.current().fileName,
+	})
+}
+
+// ClickAt moves the cursor to the clicked file, a second click on the same file opens its diff.
+// The frame is placed by the revisions view, it starts at the first line of the details.
+func (s *Operation) ClickAt(x, y int) tea.Cmd {
+	if s.confirmation != nil || len(s.files) == 0 {
+		return nil
+	}
+	localY := y - s.Frame.Min.Y
+	if s.stat != "" {
+		localY--
+	}
+	if localY < 0 || localY >= s.Height {
+		return nil
+	}
+	index := s.rowAtLine(s.renderer.ViewRange.Start + localY)
+	if index == -1 {
+		return nil
+	}
+
+	now := time.Now()
+	isDoubleClick := index == s.lastClickIndex && now.Sub(s.lastClickTime) <= doubleClickInterval
+	s.lastClickIndex, s.lastClickTime = index, now
+	if isDoubleClick {
+		s.lastClickTime = time.Time{}
+		return s.showDiff()
+	}
+	if index == s.cursor {
+		return nil
+	}
+	s.cursor = index
+	return s.updateSelection()
+}
+
+// Scroll moves the cursor since the list always keeps the cursor in view
+func (s *Operation) Scroll(delta int) tea.Cmd {
+	if len(s.files) == 0 {
+		return nil
+	}
+	oldCursor := s.cursor
+	if delta < 0 {
+		s.cursorUp(-delta)
+	} else {
+		s.cursorDown(delta)
+	}
+	if s.cursor == oldCursor {
+		return nil
+	}
+	return s.updateSelection()
+}
+
 func (s *Operation) internalUpdate(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if s.confirmation != nil {
 			return s.confirmation.Update(msg)
 		}
+		switch s.count.Update(msg) {
+		case list.PrefixPending:
+			return nil
+		case list.PrefixTop:
+			s.cursor = 0
+			return nil
+		}
+		count := s.count.Take()
 		switch {
 		case key.Matches(msg, s.keyMap.Up):
-			s.cursorUp()
+			s.cursorUp(count)
 			return nil
 		case key.Matches(msg, s.keyMap.Down):
-			s.cursorDown()
+			s.cursorDown(count)
+			return nil
+		case key.Matches(msg, s.keyMap.JumpToTop):
+			s.cursor = 0
 			return nil
+		case key.Matches(msg, s.keyMap.JumpToBottom):
+			s.cursorDown(len(s.files))
+			return nil
+		case key.Matches(msg, s.keyMap.Details.NextConflict, s.keyMap.Details.PrevConflict):
+			direction := 1
+			if key.Matches(msg, s.keyMap.Details.PrevConflict) {
+				direction = -1
+			}
+			if !s.moveToConflict(direction) {
+				return intents.Invoke(intents.AddMessage{Text: "no conflicts"})
+			}
+			return nil
+		case key.Matches(msg, s.keyMap.Details.Resolve):
+			return s.resolve()
+		case key.Matches(msg, s.keyMap.Details.Follow):
+			s.follow = !s.follow
+			if s.follow {
+				return intents.Invoke(intents.AddMessage{Text: "details follow the selected revision"})
+			}
+			return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("details stay on %s", s.revision.GetChangeId())})
+		case key.Matches(msg, s.keyMap.Details.NextRevision):
+			return s.moveRevision(1)
+		case key.Matches(msg, s.keyMap.Details.PrevRevision):
+			return s.moveRevision(-1)
+		case key.Matches(msg, s.keyMap.Cancel) && s.cancelLoad != nil:
+			s.cancelLoad()
+			s.cancelLoad = nil
+			return intents.Invoke(intents.AddMessage{Text: "cancelled"})
 		case key.Matches(msg, s.keyMap.Cancel), key.Matches(msg, s.keyMap.Details.Close):
 			return common.Close
 		case key.Matches(msg, s.keyMap.Quit): // handle global quit after cancel
@@ -127,30 +283,23 @@ func (s *Operation) internalUpdate(msg tea.Msg) tea.Cmd {
 		case key.Matches(msg, s.keyMap.Refresh):
 			return common.Refresh
 		case key.Matches(msg, s.keyMap.Details.Diff):
-			selected := s.current()
-			if selected == nil {
-				return nil
-			}
-			args := jj.TemplatedArgs(config.Current.Diff.Command, map[string]string{
-				jj.ChangeIdPlaceholder: s.revision.GetChangeId(),
-				jj.CommitIdPlaceholder: s.revision.CommitId,
-				jj.FilePlaceholder:     selected.fileName,
-				jj.WidthPlaceholder:    strconv.Itoa(s.context.ScreenWidth),
-			})
-			if config.Current.Diff.Show == config.ShowOptionInteractive {
-				return s.context.RunInteractiveCommand(args, common.Refresh)
-			}
-			return func() tea.Msg {
-				output, _ := s.context.RunCommandImmediate(args)
-				return common.ShowDiffMsg(output)
+			return s.showDiff()
+		case key.Matches(msg, s.keyMap.Details.SplitSession):
+			s.splitting = !s.splitting
+			s.setSplitHints()
+			if s.splitting {
+				return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("splitting %s file by file, press %s to stop", s.revision.GetChangeId(), s.keyMap.Details.SplitSession.Help().Key)})
 			}
+			return intents.Invoke(intents.AddMessage{Text: "stopped splitting"})
+		case s.splitting && key.Matches(msg, s.keyMap.Details.Split):
+			return s.splitOut()
 		case key.Matches(msg, s.keyMap.Details.Split, s.keyMap.Details.SplitParallel):
 			isParallel := key.Matches(msg, s.keyMap.Details.SplitParallel)
 			selectedFiles := s.getSelectedFiles(true)
 			s.selectedHint = "stays as is"
 			s.unselectedHint = "moves to the new revision"
 			model := confirmation.New(
-				[]string{"Are you sure you want to split the selected files?"},
+				[]string{s.confirmationMessage("split", "Are you sure you want to split the selected files?", selectedFiles)},
 				confirmation.WithStylePrefix("revisions"),
 				confirmation.WithOption("Yes",
 					tea.Batch(s.context.RunInteractiveCommand(jj.Split(s.revision.GetChangeId(), selectedFiles, isParallel), common.Refresh), common.Close),
@@ -161,22 +310,40 @@ func (s *Operation) internalUpdate(msg tea.Msg) tea.Cmd {
 			)
 			s.confirmation = model
 			return s.confirmation.Init()
+		case key.Matches(msg, s.keyMap.Details.SplitInteractive):
+			model := confirmation.New(
+				[]string{s.confirmationMessage("split_interactive", "Are you sure you want to split the revision by hunks?", s.getSelectedFiles(true))},
+				confirmation.WithStylePrefix("revisions"),
+				confirmation.WithOption("Yes",
+					tea.Batch(s.context.RunInteractiveCommand(jj.SplitInteractive(s.revision.GetChangeId()), common.Refresh), common.Close),
+					key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+				confirmation.WithOption("No",
+					confirmation.Close,
+					key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+			)
+			s.confirmation = model
+			return s.confirmation.Init()
 		case key.Matches(msg, s.keyMap.Details.Squash):
 			return intents.Invoke(intents.StartSquash{
-				Selected: jj.NewSelectedRevisions(s.revision),
-				Files:    s.getSelectedFiles(true),
+				Selected:            jj.NewSelectedRevisions(s.revision),
+				Files:               s.getSelectedFiles(true),
+				DescribeDestination: config.Current.Details.PromptDescriptionAfterSquash,
 			})
 		case key.Matches(msg, s.keyMap.Details.Restore):
 			selectedFiles := s.getSelectedFiles(true)
 			selected := s.current()
+			restore := s.context.RunCommand(jj.Restore(s.revision.GetChangeId(), selectedFiles), common.Refresh, confirmation.Close)
+			if s.context.IsConfirmationSuppressed(restoreConfirmation) {
+				return restore
+			}
 			s.selectedHint = "gets restored"
 			s.unselectedHint = "stays as is"
 			model := confirmation.New(
-				[]string{"Are you sure you want to restore the selected files?"},
+				[]string{s.confirmationMessage("restore", "Are you sure you want to restore the selected files?", selectedFiles)},
 				confirmation.WithStylePrefix("revisions"),
-				confirmation.WithOption("Yes",
-					s.context.RunCommand(jj.Restore(s.revision.GetChangeId(), selectedFiles), common.Refresh, confirmation.Close),
-					key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+				confirmation.WithDefaultNo(),
+				confirmation.WithOption("Yes", restore, key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+				confirmation.WithDontAskAgain(s.context, restoreConfirmation, restore),
 				confirmation.WithOption("Interactive",
 					tea.Batch(s.context.RunInteractiveCommand(jj.RestoreInteractive(s.revision.GetChangeId(), selected.fileName), common.Refresh), common.Close),
 					key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "interactive"))),
@@ -186,16 +353,21 @@ func (s *Operation) internalUpdate(msg tea.Msg) tea.Cmd {
 			)
 			s.confirmation = model
 			return s.confirmation.Init()
+		case key.Matches(msg, s.keyMap.Details.RestoreDeleted):
+			return s.restoreDeleted()
 		case key.Matches(msg, s.keyMap.Details.Absorb):
 			selectedFiles := s.getSelectedFiles(true)
+			absorb := s.context.RunCommand(jj.Absorb(s.revision.GetChangeId(), selectedFiles...), common.Refresh, confirmation.Close)
+			if s.context.IsConfirmationSuppressed(absorbConfirmation) {
+				return absorb
+			}
 			s.selectedHint = "might get absorbed into parents"
 			s.unselectedHint = "stays as is"
 			model := confirmation.New(
-				[]string{"Are you sure you want to absorb changes from the selected files?"},
+				[]string{s.confirmationMessage("absorb", "Are you sure you want to absorb changes from the selected files?", selectedFiles)},
 				confirmation.WithStylePrefix("revisions"),
-				confirmation.WithOption("Yes",
-					s.context.RunCommand(jj.Absorb(s.revision.GetChangeId(), selectedFiles...), common.Refresh, confirmation.Close),
-					key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+				confirmation.WithOption("Yes", absorb, key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+				confirmation.WithDontAskAgain(s.context, absorbConfirmation, absorb),
 				confirmation.WithOption("No",
 					confirmation.Close,
 					key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
@@ -218,18 +390,154 @@ func (s *Operation) internalUpdate(msg tea.Msg) tea.Cmd {
 					s.context.RemoveCheckedItem(checkedFile)
 				}
 
-				s.cursorDown()
+				s.cursorDown(1)
 			}
 			return nil
 		case key.Matches(msg, s.keyMap.Details.RevisionsChangingFile):
 			if current := s.current(); current != nil {
 				return tea.Batch(common.Close, common.UpdateRevSet(fmt.Sprintf("files(%s)", jj.EscapeFileName(current.fileName))))
 			}
+		case key.Matches(msg, s.keyMap.Details.OpenInEditor):
+			if current := s.current(); current != nil {
+				return s.openInEditor(current)
+			}
 		}
 	}
 	return nil
 }
 
+// showDiff shows the diff of the file under the cursor
+func (s *Operation) showDiff() tea.Cmd {
+	selected := s.current()
+	if selected == nil {
+		return nil
+	}
+	if config.Current.Details.DiffInPreview {
+		fullScreen := s.fullScreenDiff(selected)
+		return func() tea.Msg {
+			return common.ShowDiffInPreviewMsg{FullScreen: fullScreen}
+		}
+	}
+	return s.fullScreenDiff(selected)
+}
+
+// fullScreenDiff shows the diff of the file in the diff view, or with diff.command when it is interactive
+func (s *Operation) fullScreenDiff(selected *item) tea.Cmd {
+	args := jj.TemplatedArgs(config.Current.Diff.Command, map[string]string{
+		jj.ChangeIdPlaceholder: s.revision.GetChangeId(),
+		jj.CommitIdPlaceholder: s.revision.CommitId,
+		jj.FilePlaceholder:     selected.fileName,
+		jj.WidthPlaceholder:    strconv.Itoa(s.context.ScreenWidth),
+	})
+	if config.Current.Diff.Show == config.ShowOptionInteractive {
+		// diffs don't change the repository, so they are shown in the dry-run mode too
+		return s.context.CommandRunner.RunInteractiveCommand(args, common.Refresh)
+	}
+	return func() tea.Msg {
+		output, _ := s.context.RunCommandImmediate(args)
+		return common.ShowDiffMsg(output)
+	}
+}
+
+// confirmationMessage fills in the ui.confirmations template of the named confirmation, the
+// fallback is shown when there is no template
+func (s *Operation) confirmationMessage(name string, fallback string, files []string) string {
+	var file string
+	if current := s.current(); current != nil {
+		file = current.fileName
+	}
+	return confirmation.Message(name, fallback, map[string]string{
+		jj.ChangeIdPlaceholder:            s.revision.GetChangeId(),
+		jj.FilePlaceholder:                file,
+		confirmation.FilesPlaceholder:     strings.Join(files, ", "),
+		confirmation.FileCountPlaceholder: strconv.Itoa(len(files)),
+	})
+}
+
+// restoreDeleted brings back the deleted file under the cursor from the parent revision, the
+// parent to restore it from is asked for when the revision is a merge. The checked files are left alone.
+func (s *Operation) restoreDeleted() tea.Cmd {
+	file := s.current()
+	if file == nil {
+		return nil
+	}
+	if file.status != Deleted {
+		err := fmt.Errorf("%s was not deleted in this revision", file.fileName)
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	changeId := s.revision.GetChangeId()
+	fileName := file.fileName
+	return func() tea.Msg {
+		output, err := s.context.RunCommandImmediate(jj.GetParents(changeId))
+		return restoreDeletedMsg{changeId: changeId, file: fileName, parents: strings.Fields(string(output)), err: err}
+	}
+}
+
+// confirmRestoreDeleted asks for the parent to restore the deleted file from, or only for a
+// confirmation when the revision has a single parent
+func (s *Operation) confirmRestoreDeleted(msg restoreDeletedMsg) tea.Cmd {
+	if msg.err != nil || len(msg.parents) == 0 {
+		err := fmt.Errorf("couldn't find the parents of %s", msg.changeId)
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	restore := func(parent string) tea.Cmd {
+		return s.context.RunCommand(jj.RestoreFromParent(msg.changeId, parent, msg.file), common.Refresh, confirmation.Close)
+	}
+	replacements := map[string]string{
+		jj.ChangeIdPlaceholder:            msg.changeId,
+		jj.FilePlaceholder:                msg.file,
+		confirmation.FilesPlaceholder:     msg.file,
+		confirmation.FileCountPlaceholder: "1",
+	}
+	var message string
+	var options []confirmation.Option
+	if len(msg.parents) == 1 {
+		if s.context.IsConfirmationSuppressed(restoreDeletedConfirmation) {
+			return restore(msg.parents[0])
+		}
+		message = confirmation.Message("restore_deleted",
+			fmt.Sprintf("Are you sure you want to restore %s from the parent revision?", msg.file), replacements)
+		options = append(options,
+			confirmation.WithOption("Yes", restore(msg.parents[0]), key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+			confirmation.WithDontAskAgain(s.context, restoreDeletedConfirmation, restore(msg.parents[0])),
+		)
+	} else {
+		message = confirmation.Message("restore_deleted_parent",
+			fmt.Sprintf("Which parent do you want to restore %s from?", msg.file), replacements)
+		for i, parent := range msg.parents[:min(len(msg.parents), 9)] {
+			shortcut := strconv.Itoa(i + 1)
+			options = append(options, confirmation.WithOption(parent, restore(parent),
+				key.NewBinding(key.WithKeys(shortcut), key.WithHelp(shortcut, parent))))
+		}
+	}
+	options = append(options,
+		confirmation.WithStylePrefix("revisions"),
+		confirmation.WithOption("No",
+			confirmation.Close,
+			key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+	)
+	s.confirmation = confirmation.New([]string{message}, options...)
+	return s.confirmation.Init()
+}
+
+// openInEditor edits the file in the working copy; the refresh that follows
+// the editor picks up the changes with a new snapshot
+func (s *Operation) openInEditor(file *item) tea.Cmd {
+	if file.status == Deleted {
+		err := fmt.Errorf("%s was deleted in this revision", file.fileName)
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	// the shell runs an editor configured with its arguments too
+	quoted := "'" + strings.ReplaceAll(file.fileName, "'", `'\''`) + "'"
+	return func() tea.Msg {
+		return common.ExecMsg{
+			Line:        config.GetDefaultEditor() + " " + quoted,
+			Mode:        common.ExecShell,
+			Interactive: true,
+		}
+	}
+}
+
 func (s *Operation) View() string {
 	confirmationView := ""
 	ch := 0
@@ -240,8 +548,13 @@ func (s *Operation) View() string {
 	if s.Len() == 0 {
 		return s.styles.Dimmed.Render("No changes\n")
 	}
+	header := ""
+	if s.stat != "" {
+		header = s.styles.Dimmed.Render(s.stat) + "\n"
+		ch++
+	}
 	s.SetHeight(min(s.Parent.Height-5-ch, s.Len()))
-	filesView := s.renderer.Render(s.cursor)
+	filesView := header + s.renderer.Render(s.cursor)
 	if confirmationView != "" {
 		return lipgloss.JoinVertical(lipgloss.Top, filesView, confirmationView)
 	}
@@ -250,7 +563,67 @@ func (s *Operation) View() string {
 
 func (s *Operation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
 	s.Current = commit
-	return nil
+	if !(s.follow || s.splitting) || commit == nil || commit.GetChangeId() == s.revision.GetChangeId() {
+		return nil
+	}
+	s.revision = commit
+	// the checked files belong to the previous revision
+	s.setItems(nil)
+	s.stat = ""
+	return s.load(commit.GetChangeId())
+}
+
+// setSplitHints marks what happens to the files when they are split in the split session
+func (s *Operation) setSplitHints() {
+	s.selectedHint, s.unselectedHint = "", ""
+	if s.splitting {
+		s.selectedHint = "stays as is"
+		s.unselectedHint = "moves to the next revision"
+	}
+}
+
+// splitOut splits the checked files out of the revision keeping its description, so that no
+// editor is opened between the splits
+func (s *Operation) splitOut() tea.Cmd {
+	files := s.getSelectedFiles(true)
+	if len(files) == 0 {
+		return nil
+	}
+	changeId := s.revision.GetChangeId()
+	return func() tea.Msg {
+		description, err := s.context.RunCommandImmediate(jj.GetDescription(changeId))
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		return splitOutMsg{files: files, description: string(description)}
+	}
+}
+
+// selectRemaining moves the cursor to the revision created by splitting the given one, which
+// holds the rest of the files. The revision stays selected when the split didn't happen as it
+// still has the commit it had before.
+func (s *Operation) selectRemaining(split *jj.Commit) tea.Cmd {
+	return func() tea.Msg {
+		revset := fmt.Sprintf("children(%s)", split.GetChangeId())
+		if split.CommitId != "" {
+			revset = fmt.Sprintf("children(%s) ~ %s::", split.GetChangeId(), split.CommitId)
+		}
+		output, err := s.context.RunCommandImmediate(jj.GetIdsFromRevset(revset))
+		remaining := strings.TrimSpace(string(output))
+		if err != nil || remaining == "" || strings.Contains(remaining, "\n") {
+			return common.RefreshMsg{SelectedRevision: split.GetChangeId()}
+		}
+		return common.RefreshMsg{SelectedRevision: remaining}
+	}
+}
+
+// moveRevision moves the cursor of the revisions view, the details are closed unless they follow it
+func (s *Operation) moveRevision(delta int) tea.Cmd {
+	navigate := intents.Invoke(intents.Navigate{Delta: delta})
+	if s.follow {
+		return navigate
+	}
+	return tea.Sequence(common.Close, navigate)
 }
 
 func (s *Operation) ShortHelp() []key.Binding {
@@ -260,13 +633,22 @@ func (s *Operation) ShortHelp() []key.Binding {
 	return []key.Binding{
 		s.keyMap.Cancel,
 		s.keyMap.Details.Diff,
+		s.keyMap.DiffTool,
 		s.keyMap.Details.ToggleSelect,
 		s.keyMap.Details.Split,
 		s.keyMap.Details.SplitParallel,
+		s.keyMap.Details.SplitInteractive,
 		s.keyMap.Details.Squash,
 		s.keyMap.Details.Restore,
 		s.keyMap.Details.Absorb,
 		s.keyMap.Details.RevisionsChangingFile,
+		s.keyMap.Details.OpenInEditor,
+		s.keyMap.Details.NextConflict,
+		s.keyMap.Details.PrevConflict,
+		s.keyMap.Details.Resolve,
+		s.keyMap.Details.RestoreDeleted,
+		s.keyMap.Details.Follow,
+		s.keyMap.Details.SplitSession,
 	}
 }
 
@@ -286,6 +668,27 @@ func (s *Operation) Name() string {
 	return "details"
 }
 
+// resolve launches the merge tool on the selected files, or on all the conflicted files
+// of the revision when no file is selected
+func (s *Operation) resolve() tea.Cmd {
+	var files []string
+	hasConflicts := false
+	for _, f := range s.files {
+		hasConflicts = hasConflicts || f.conflict
+		if !f.selected {
+			continue
+		}
+		if !f.conflict {
+			return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("%s has no conflicts", f.fileName)})
+		}
+		files = append(files, f.fileName)
+	}
+	if !hasConflicts {
+		return intents.Invoke(intents.AddMessage{Text: "no conflicts"})
+	}
+	return s.context.RunInteractiveCommand(jj.Resolve(s.revision.GetChangeId(), files), common.Refresh)
+}
+
 func (s *Operation) getSelectedFiles(allowVirtualSelection bool) []string {
 	selectedFiles := make([]string, 0)
 	if len(s.files) == 0 {
@@ -357,26 +760,61 @@ func (s *Operation) createListItems(content string, selectedFiles []string) []*i
 	return items
 }
 
+var diffStatPattern = regexp.MustCompile(`(\d+) files? changed, (\d+) insertions?\(\+\), (\d+) deletions?\(-\)`)
+
+// parseDiffStat turns the last line of jj diff --stat into a "N files changed, +X -Y" summary
+func parseDiffStat(output string) string {
+	matches := diffStatPattern.FindStringSubmatch(output)
+	if matches == nil {
+		return ""
+	}
+	noun := "files"
+	if matches[1] == "1" {
+		noun = "file"
+	}
+	return fmt.Sprintf("%s %s changed, +%s -%s", matches[1], noun, matches[2], matches[3])
+}
+
+// load snapshots the working copy, unless revisions.auto_snapshot is off, and reads the
+// changed files in the background; the cancel key stops it while it is still running
 func (s *Operation) load(revision string) tea.Cmd {
-	output, err := s.context.RunCommandImmediate(jj.Snapshot())
-	if err == nil {
-		output, err = s.context.RunCommandImmediate(jj.Status(revision))
+	if s.cancelLoad != nil {
+		s.cancelLoad()
+	}
+	ctx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	s.cancelLoad = cancel
+	selectedFiles := s.getSelectedFiles(false)
+	autoSnapshot := config.Current.Revisions.AutoSnapshot
+	return func() tea.Msg {
+		var output []byte
+		var err error
+		if autoSnapshot {
+			_, err = s.context.RunCommandImmediateContext(ctx, jj.Snapshot())
+		}
 		if err == nil {
-			return func() tea.Msg {
-				summary := string(output)
-				selectedFiles := s.getSelectedFiles(false)
-				return updateCommitStatusMsg{summary, selectedFiles}
+			output, err = s.context.RunCommandImmediateContext(ctx, jj.Status(revision))
+		}
+		var stat string
+		if err == nil {
+			// the header is left out when the statistics can't be read
+			statOutput, statErr := s.context.RunCommandImmediateContext(ctx, jj.DiffStat(revision))
+			stat, err = parseDiffStat(string(statOutput)), statErr
+			if !errors.Is(err, stdcontext.Canceled) {
+				err = nil
 			}
 		}
-	}
-	return func() tea.Msg {
-		return common.CommandCompletedMsg{
-			Output: string(output),
-			Err:    err,
+		if errors.Is(err, stdcontext.Canceled) {
+			return nil
 		}
+		return updateCommitStatusMsg{string(output), selectedFiles, err, stat}
 	}
 }
 
+// SelectFile places the cursor on the given file once the files of the revision are loaded
+func (s *Operation) SelectFile(file string) {
+	s.fileToSelect = file
+}
+
 func NewOperation(context *context.MainContext, selected *jj.Commit) *Operation {
 	keyMap := config.Current.GetKeyMap()
 
@@ -401,6 +839,7 @@ func NewOperation(context *context.MainContext, selected *jj.Commit) *Operation
 		styles:            s,
 		keymap:            config.Current.GetKeyMap(),
 		targetMarkerStyle: common.DefaultPalette.Get("revisions details target_marker"),
+		follow:            config.Current.Details.FollowSelection,
 	}
 	l.Parent = op.ViewNode
 	return op
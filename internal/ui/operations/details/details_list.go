@@ -45,16 +45,35 @@ func (d *DetailsList) setItems(files []*item) {
 	d.renderer.Reset()
 }
 
-func (d *DetailsList) cursorUp() {
-	if d.cursor > 0 {
-		d.cursor--
+func (d *DetailsList) cursorUp(count int) {
+	d.cursor = max(d.cursor-count, 0)
+}
+
+func (d *DetailsList) cursorDown(count int) {
+	d.cursor = max(min(d.cursor+count, len(d.files)-1), 0)
+}
+
+// moveToConflict moves the cursor to the next conflicted file in the given direction, wrapping around.
+// It returns false when there are no conflicted files.
+func (d *DetailsList) moveToConflict(direction int) bool {
+	n := len(d.files)
+	for step := 1; step <= n; step++ {
+		index := ((d.cursor+direction*step)%n + n) % n
+		if d.files[index].conflict {
+			d.cursor = index
+			return true
+		}
 	}
+	return false
 }
 
-func (d *DetailsList) cursorDown() {
-	if d.cursor < len(d.files)-1 {
-		d.cursor++
+func (d *DetailsList) rowAtLine(line int) int {
+	for _, rr := range d.renderer.RowRanges() {
+		if line >= rr.StartLine && line < rr.EndLine {
+			return rr.Row
+		}
 	}
+	return -1
 }
 
 func (d *DetailsList) current() *item {
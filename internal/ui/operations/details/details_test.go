@@ -5,8 +5,10 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/idursun/jjui/test"
@@ -28,6 +30,7 @@ func TestModel_Init_ExecutesStatusCommand(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	defer commandRunner.Verify()
 
 	model := NewOperation(test.NewTestContext(commandRunner), Commit)
@@ -40,6 +43,7 @@ func TestModel_Update_RestoresSelectedFiles(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	commandRunner.Expect(jj.Restore(Revision, []string{"file.txt"}))
 	defer commandRunner.Verify()
 
@@ -50,13 +54,14 @@ func TestModel_Update_RestoresSelectedFiles(t *testing.T) {
 
 	test.SimulateModel(model, test.Press(tea.KeySpace))
 	test.SimulateModel(model, test.Type("r"))
-	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	test.SimulateModel(model, test.Type("y"))
 }
 
 func TestModel_Update_RestoresInteractively(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	commandRunner.Expect(jj.RestoreInteractive(Revision, "file.txt"))
 	defer commandRunner.Verify()
 
@@ -71,6 +76,7 @@ func TestModel_Update_SplitsSelectedFiles(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	commandRunner.Expect(jj.Split(Revision, []string{"file.txt"}, false))
 	defer commandRunner.Verify()
 
@@ -88,6 +94,7 @@ func TestModel_Update_ParallelSplitsSelectedFiles(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	commandRunner.Expect(jj.Split(Revision, []string{"file.txt"}, true))
 	defer commandRunner.Verify()
 
@@ -103,10 +110,30 @@ func TestModel_Update_ParallelSplitsSelectedFiles(t *testing.T) {
 	test.SimulateModel(model, test.Press(tea.KeyEnter))
 }
 
+func TestModel_Update_SplitsInteractivelyRegardlessOfSelection(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.SplitInteractive(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+	test.SimulateModel(model, func() tea.Msg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i"), Alt: true}
+	})
+	test.SimulateModel(model, test.Press(tea.KeyEnter))
+}
+
 func TestModel_Update_HandlesMovedFiles(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false $\nR internal/ui/{revisions => }/file.go\nR {file => sub/newfile}\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	commandRunner.Expect(jj.Restore(Revision, []string{"internal/ui/file.go", "sub/newfile"}))
 	defer commandRunner.Verify()
 
@@ -118,13 +145,14 @@ func TestModel_Update_HandlesMovedFiles(t *testing.T) {
 	test.SimulateModel(model, test.Press(tea.KeySpace))
 	test.SimulateModel(model, test.Press(tea.KeySpace))
 	test.SimulateModel(model, test.Type("r"))
-	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	test.SimulateModel(model, test.Type("y"))
 }
 
 func TestModel_Update_HandlesMovedFilesInDeepDirectories(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false false $\nR {src/new_file_3.md => new_file.md}\nR src/{new_file.py => renamed_py.py}\nR {src1/to_be_renamed.md => src2/renamed.md}\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	commandRunner.Expect(jj.Restore(Revision, []string{"new_file.md", "src/renamed_py.py", "src2/renamed.md"}))
 	defer commandRunner.Verify()
 
@@ -137,13 +165,14 @@ func TestModel_Update_HandlesMovedFilesInDeepDirectories(t *testing.T) {
 	test.SimulateModel(model, test.Press(tea.KeySpace))
 	test.SimulateModel(model, test.Press(tea.KeySpace))
 	test.SimulateModel(model, test.Type("r"))
-	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	test.SimulateModel(model, test.Type("y"))
 }
 
 func TestModel_Update_HandlesFilenamesWithBraces(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false $\nM file{with}braces.txt\nA another{test}.go\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	commandRunner.Expect(jj.Restore(Revision, []string{"file{with}braces.txt", "another{test}.go"}))
 	defer commandRunner.Verify()
 
@@ -155,13 +184,14 @@ func TestModel_Update_HandlesFilenamesWithBraces(t *testing.T) {
 	test.SimulateModel(model, test.Press(tea.KeySpace))
 	test.SimulateModel(model, test.Press(tea.KeySpace))
 	test.SimulateModel(model, test.Type("r"))
-	test.SimulateModel(model, test.Press(tea.KeyEnter))
+	test.SimulateModel(model, test.Type("y"))
 }
 
 func TestModel_Refresh_IgnoreVirtuallySelectedFiles(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.Snapshot())
 	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
 	defer commandRunner.Verify()
 
 	model := NewOperation(test.NewTestContext(commandRunner), Commit)
@@ -224,3 +254,488 @@ A test/file4`
 	files := model.createListItems(content, nil)
 	assert.Len(t, files, 4)
 }
+
+func TestModel_Update_NavigatesBetweenConflicts(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false true false true $\nM a.txt\nM b.txt\nM c.txt\nM d.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Type("]"))
+	assert.Equal(t, "b.txt", model.current().fileName)
+	test.SimulateModel(model, test.Type("]"))
+	assert.Equal(t, "d.txt", model.current().fileName)
+	test.SimulateModel(model, test.Type("]"))
+	assert.Equal(t, "b.txt", model.current().fileName, "should wrap around to the first conflict")
+	test.SimulateModel(model, test.Type("["))
+	assert.Equal(t, "d.txt", model.current().fileName, "should wrap around to the last conflict")
+}
+
+func TestModel_Update_ShowsMessageWhenThereAreNoConflicts(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	var messages []string
+	test.SimulateModel(model, test.Type("]"), func(msg tea.Msg) {
+		if msg, ok := msg.(intents.AddMessage); ok {
+			messages = append(messages, msg.Text)
+		}
+	})
+	assert.Equal(t, []string{"no conflicts"}, messages)
+	assert.Equal(t, "file.txt", model.current().fileName)
+}
+
+func TestModel_Update_ResolvesAllConflictsWhenNothingIsSelected(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false true $\nM a.txt\nM b.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.Resolve(Revision, nil))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Type("R"))
+}
+
+func TestModel_Update_ResolvesSelectedConflictedFiles(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false true $\nM a.txt\nM b.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	var messages []string
+	test.SimulateModel(model, test.Type(" R"), func(msg tea.Msg) {
+		if msg, ok := msg.(intents.AddMessage); ok {
+			messages = append(messages, msg.Text)
+		}
+	})
+	assert.Equal(t, []string{"a.txt has no conflicts"}, messages)
+
+	commandRunner.Expect(jj.Resolve(Revision, []string{"b.txt"}))
+	test.SimulateModel(model, test.Type("k  R"))
+}
+
+func TestModel_Update_OpensFileInEditor(t *testing.T) {
+	t.Setenv("EDITOR", "vi")
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, common.ExecMsg{Line: "vi 'file.txt'", Mode: common.ExecShell, Interactive: true}, cmd())
+}
+
+func TestModel_Update_DoesNotOpenDeletedFileInEditor(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false $\nD removed.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	assert.NotNil(t, cmd)
+	msg, ok := cmd().(intents.AddMessage)
+	assert.True(t, ok)
+	assert.Error(t, msg.Err)
+	assert.Equal(t, "removed.txt was deleted in this revision", msg.Text)
+}
+
+func TestModel_Update_CancelsLoading(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	load := model.Init()
+
+	cmd := model.Update(test.Press(tea.KeyEsc)())
+	assert.NotNil(t, cmd)
+	assert.Equal(t, intents.AddMessage{Text: "cancelled"}, cmd())
+	assert.Nil(t, load())
+
+	cmd = model.Update(test.Press(tea.KeyEsc)())
+	assert.NotNil(t, cmd)
+	assert.Equal(t, common.CloseViewMsg{}, cmd())
+}
+
+func TestModel_View_ShowsDiffStatHeader(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision)).SetOutput([]byte("file.txt    | 3 ++-\nnewfile.txt | 1 +\n2 files changed, 3 insertions(+), 1 deletion(-)"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	model.Parent = common.NewViewNode(100, 20)
+	test.SimulateModel(model, model.Init())
+	assert.Contains(t, model.View(), "2 files changed, +3 -1")
+}
+
+func TestParseDiffStat(t *testing.T) {
+	assert.Equal(t, "1 file changed, +0 -4", parseDiffStat("a | 4 ----\n1 file changed, 0 insertions(+), 4 deletions(-)"))
+	assert.Equal(t, "", parseDiffStat(""))
+}
+
+func TestModel_SelectFile_PlacesCursorOnFile(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SelectFile("newfile.txt")
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	assert.Equal(t, "newfile.txt", model.current().fileName)
+}
+
+func TestModel_ClickAt_MovesCursorBelowTheHeader(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision)).SetOutput([]byte("2 files changed, 3 insertions(+), 1 deletion(-)"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.Parent = common.NewViewNode(100, 20)
+	test.SimulateModel(model, model.Init())
+	model.View()
+	model.Frame = cellbuf.Rect(0, 5, 100, 3)
+
+	assert.Nil(t, model.ClickAt(0, 5))
+	assert.Equal(t, "file.txt", model.current().fileName)
+	assert.NotNil(t, model.ClickAt(0, 7))
+	assert.Equal(t, "newfile.txt", model.current().fileName)
+}
+
+func TestModel_ClickAt_AccountsForScrollOffset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false false false $\nM a.txt\nM b.txt\nM c.txt\nM d.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.Parent = common.NewViewNode(100, 7)
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("G"))
+	model.View()
+	model.Frame = cellbuf.Rect(0, 0, 100, 2)
+
+	test.SimulateModel(model, model.ClickAt(0, 0))
+	assert.Equal(t, "c.txt", model.current().fileName)
+}
+
+func TestModel_ClickAt_DoubleClickShowsDiff(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Diff.Show = config.ShowOptionDiff
+	config.Current.Diff.Command = []string{"diff", "-r", jj.ChangeIdPlaceholder, jj.FilePlaceholder}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.CommandArgs{"diff", "-r", Revision, jj.EscapeFileName("newfile.txt")}).SetOutput([]byte("diff output"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.Parent = common.NewViewNode(100, 20)
+	test.SimulateModel(model, model.Init())
+	model.View()
+	model.Frame = cellbuf.Rect(0, 0, 100, 2)
+
+	model.ClickAt(0, 1)
+	cmd := model.ClickAt(0, 1)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, common.ShowDiffMsg("diff output"), cmd())
+}
+
+func TestModel_Init_SkipsSnapshotWhenAutoSnapshotIsOff(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Revisions.AutoSnapshot = false
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	assert.Contains(t, model.View(), "file.txt")
+}
+
+func TestModel_SetSelectedRevision_ReloadsWhenFollowing(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Details.FollowSelection = true
+
+	other := &jj.Commit{ChangeId: "other", CommitId: "other"}
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.Status("other")).SetOutput([]byte("false false $\nA other.txt\n"))
+	commandRunner.Expect(jj.DiffStat("other"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, model.SetSelectedRevision(other))
+	assert.Contains(t, model.View(), "other.txt")
+	assert.NotContains(t, model.View(), "newfile.txt")
+}
+
+func TestModel_SetSelectedRevision_KeepsRevisionWhenNotFollowing(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	assert.Nil(t, model.SetSelectedRevision(&jj.Commit{ChangeId: "other", CommitId: "other"}))
+	assert.Contains(t, model.View(), "newfile.txt")
+}
+
+func TestModel_Update_FollowTogglesReloading(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	assert.False(t, model.follow)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f"), Alt: true})
+	assert.True(t, model.follow)
+}
+
+func TestModel_Update_RestoresDeletedFileFromParent(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false $\nD deleted.txt\nM file.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.GetParents(Revision)).SetOutput([]byte("abc"))
+	commandRunner.Expect(jj.RestoreFromParent(Revision, "abc", "deleted.txt"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("U"))
+	assert.Contains(t, model.View(), "restore deleted.txt from the parent revision")
+	test.SimulateModel(model, test.Type("y"))
+}
+
+func TestModel_Update_RestoresDeletedFileFromChosenParentOfMerge(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false $\nD deleted.txt\nM file.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.GetParents(Revision)).SetOutput([]byte("abc def"))
+	commandRunner.Expect(jj.RestoreFromParent(Revision, "def", "deleted.txt"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("U"))
+	assert.Contains(t, model.View(), "Which parent do you want to restore deleted.txt from?")
+	test.SimulateModel(model, test.Type("2"))
+}
+
+func TestModel_Update_RestoreDeletedWithoutAskingAgain(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false $\nD deleted.txt\nM file.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.GetParents(Revision)).SetOutput([]byte("abc"))
+	commandRunner.Expect(jj.RestoreFromParent(Revision, "abc", "deleted.txt"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SuppressConfirmation(restoreDeletedConfirmation)
+	model := NewOperation(ctx, Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("U"))
+	assert.Nil(t, model.confirmation)
+}
+
+func TestModel_Update_RestoreDeletedFromMergeUsesTemplate(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Confirmations = map[string]string{"restore_deleted_parent": "Bring $file back from?"}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte("false false $\nD deleted.txt\nM file.txt\n"))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.GetParents(Revision)).SetOutput([]byte("abc def"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Type("U"))
+	assert.Contains(t, model.View(), "Bring deleted.txt back from?")
+}
+
+func TestModel_Update_RestoreDeletedIgnoresExistingFiles(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("U")})
+	assert.NotNil(t, cmd)
+	assert.Nil(t, model.confirmation)
+}
+
+func TestModel_Update_RestoreWithoutAskingAgain(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.Restore(Revision, []string{"file.txt"}))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := NewOperation(ctx, Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Type("r"))
+	test.SimulateModel(model, test.Type("a"))
+	assert.True(t, ctx.IsConfirmationSuppressed(restoreConfirmation))
+	assert.Nil(t, model.confirmation)
+
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	assert.NotNil(t, cmd)
+	assert.Nil(t, model.confirmation)
+}
+
+func TestModel_Update_ShowsDiffInPreview(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Details.DiffInPreview = true
+	config.Current.Diff.Show = config.ShowOptionDiff
+	config.Current.Diff.Command = []string{"diff", "-r", jj.ChangeIdPlaceholder, jj.FilePlaceholder}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.CommandArgs{"diff", "-r", Revision, jj.EscapeFileName("file.txt")}).SetOutput([]byte("diff output"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+
+	msg, ok := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})().(common.ShowDiffInPreviewMsg)
+	assert.True(t, ok)
+	assert.Equal(t, common.ShowDiffMsg("diff output"), msg.FullScreen())
+}
+
+func TestModel_Update_SplitSessionMovesOnToTheRemainingFiles(t *testing.T) {
+	commit := &jj.Commit{ChangeId: Revision, CommitId: "before"}
+	remaining := &jj.Commit{ChangeId: "remaining", CommitId: "remaining"}
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	commandRunner.Expect(jj.GetDescription(Revision)).SetOutput([]byte("add files"))
+	commandRunner.Expect(jj.SplitWithMessage(Revision, []string{"file.txt"}, "add files"))
+	commandRunner.Expect(jj.GetIdsFromRevset("children(ignored) ~ before::")).SetOutput([]byte("remaining\n"))
+	commandRunner.Expect(jj.Status("remaining")).SetOutput([]byte("false false $\nA newfile.txt\n"))
+	commandRunner.Expect(jj.DiffStat("remaining"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), commit)
+	model.Parent = common.NewViewNode(100, 20)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Press(tea.KeyCtrlX))
+	assert.Contains(t, model.View(), "moves to the next revision")
+
+	var selected string
+	test.SimulateModel(model, test.Type("s"), func(msg tea.Msg) {
+		if refresh, ok := msg.(common.RefreshMsg); ok {
+			selected = refresh.SelectedRevision
+		}
+	})
+	assert.Equal(t, "remaining", selected)
+
+	test.SimulateModel(model, model.SetSelectedRevision(remaining))
+	assert.Contains(t, model.View(), "newfile.txt")
+	assert.NotContains(t, model.View(), " M file.txt")
+
+	test.SimulateModel(model, test.Press(tea.KeyCtrlX))
+	assert.NotContains(t, model.View(), "moves to the next revision")
+}
+
+func TestModel_Update_ConfirmationTemplate(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Confirmations = map[string]string{"absorb": "Absorb $file_count file(s) of $change_id: $files?"}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Snapshot())
+	commandRunner.Expect(jj.Status(Revision)).SetOutput([]byte(StatusOutput))
+	commandRunner.Expect(jj.DiffStat(Revision))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), Commit)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 20))
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+	test.SimulateModel(model, test.Press(tea.KeySpace))
+	test.SimulateModel(model, test.Type("A"))
+	assert.Contains(t, model.View(), "Absorb 2 file(s) of ignored: file.txt, newfile.txt?")
+}
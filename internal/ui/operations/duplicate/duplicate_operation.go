@@ -1,6 +1,7 @@
 package duplicate
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -8,11 +9,17 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/choose"
 	"github.com/idursun/jjui/internal/ui/common"
 	appContext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/reorder"
 )
 
+// inPlace is the destination option that keeps the parents of the duplicated revisions
+const inPlace = "in place"
+
 type Target int
 
 const (
@@ -39,32 +46,83 @@ type styles struct {
 
 var _ operations.Operation = (*Operation)(nil)
 var _ common.Focusable = (*Operation)(nil)
+var _ common.Overlay = (*Operation)(nil)
 
 type Operation struct {
-	context     *appContext.MainContext
-	From        jj.SelectedRevisions
-	InsertStart *jj.Commit
-	To          *jj.Commit
-	Target      Target
-	keyMap      config.KeyMappings[key.Binding]
-	styles      styles
+	context      *appContext.MainContext
+	From         jj.SelectedRevisions
+	InsertStart  *jj.Commit
+	To           *jj.Commit
+	Target       Target
+	keyMap       config.KeyMappings[key.Binding]
+	styles       styles
+	destinations []string
+	choosing     bool
+	reordering   bool
+	running      bool
 }
 
 func (r *Operation) IsFocused() bool {
 	return true
 }
 
+// IsOverlay keeps receiving messages while the destination is being chosen, the checked revisions
+// are being reordered and the command is running
+func (r *Operation) IsOverlay() bool {
+	return r.choosing || r.reordering || r.running
+}
+
 func (r *Operation) Init() tea.Cmd {
 	return nil
 }
 
 func (r *Operation) Update(msg tea.Msg) tea.Cmd {
-	if msg, ok := msg.(tea.KeyMsg); ok {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if r.choosing || r.reordering {
+			return nil
+		}
 		return r.HandleKey(msg)
+	case reorder.AppliedMsg:
+		r.reordering = false
+		r.From = msg.Revisions
+	case reorder.CancelledMsg:
+		r.reordering = false
+		return common.Close
+	case choose.SelectedMsg:
+		if !r.choosing {
+			return nil
+		}
+		r.choosing = false
+		if msg.Value == inPlace {
+			return r.duplicate("", "")
+		}
+		return r.duplicate(msg.Value, targetToFlags[TargetDestination])
+	case choose.CancelledMsg:
+		r.choosing = false
+	case common.CommandCompletedMsg:
+		if !r.running {
+			return nil
+		}
+		r.running = false
+		changeIds := jj.ParseDuplicateOutput(msg.Output)
+		if msg.Err != nil || len(changeIds) == 0 {
+			return common.RefreshAndSelect(r.From.Last())
+		}
+		return tea.Batch(
+			intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("Duplicated as %s", strings.Join(changeIds, " "))}),
+			common.RefreshAndSelect(changeIds[len(changeIds)-1]),
+		)
 	}
 	return nil
 }
 
+// duplicate runs `jj duplicate` and reports the change ids of the new revisions once it completes
+func (r *Operation) duplicate(to string, target string) tea.Cmd {
+	r.running = true
+	return r.context.RunCommand(jj.Duplicate(r.From, to, target), common.Close)
+}
+
 func (r *Operation) View() string {
 	return ""
 }
@@ -77,9 +135,17 @@ func (r *Operation) HandleKey(msg tea.KeyMsg) tea.Cmd {
 		r.Target = TargetAfter
 	case key.Matches(msg, r.keyMap.Duplicate.Before):
 		r.Target = TargetBefore
+	case key.Matches(msg, r.keyMap.Duplicate.Choose):
+		r.choosing = true
+		options := append([]string{inPlace}, r.destinations...)
+		return func() tea.Msg {
+			return common.ShowChooseMsg{Options: options, Title: "Duplicate onto"}
+		}
+	case key.Matches(msg, r.keyMap.Duplicate.Reorder) && len(r.From.Revisions) > 1:
+		r.reordering = true
+		return reorder.Show("Reorder revisions to duplicate", r.From)
 	case key.Matches(msg, r.keyMap.Apply):
-		target := targetToFlags[r.Target]
-		return r.context.RunCommand(jj.Duplicate(r.From, r.To.GetChangeId(), target), common.RefreshAndSelect(r.From.Last()), common.Close)
+		return r.duplicate(r.To.GetChangeId(), targetToFlags[r.Target])
 	case key.Matches(msg, r.keyMap.Cancel):
 		return common.Close
 	}
@@ -97,9 +163,18 @@ func (r *Operation) ShortHelp() []key.Binding {
 		r.keyMap.Duplicate.After,
 		r.keyMap.Duplicate.Before,
 		r.keyMap.Duplicate.Onto,
+		r.keyMap.Duplicate.Choose,
+		r.reorderKey(),
 	}
 }
 
+// reorderKey is only offered when several revisions are duplicated
+func (r *Operation) reorderKey() key.Binding {
+	binding := r.keyMap.Duplicate.Reorder
+	binding.SetEnabled(len(r.From.Revisions) > 1)
+	return binding
+}
+
 func (r *Operation) FullHelp() [][]key.Binding {
 	return [][]key.Binding{r.ShortHelp()}
 }
@@ -151,7 +226,7 @@ func (r *Operation) Name() string {
 	return "duplicate"
 }
 
-func NewOperation(context *appContext.MainContext, from jj.SelectedRevisions, target Target) *Operation {
+func NewOperation(context *appContext.MainContext, from jj.SelectedRevisions, target Target, destinations []string) *Operation {
 	styles := styles{
 		changeId:     common.DefaultPalette.Get("duplicate change_id"),
 		dimmed:       common.DefaultPalette.Get("duplicate dimmed"),
@@ -159,10 +234,11 @@ func NewOperation(context *appContext.MainContext, from jj.SelectedRevisions, ta
 		targetMarker: common.DefaultPalette.Get("duplicate target_marker"),
 	}
 	return &Operation{
-		context: context,
-		keyMap:  config.Current.GetKeyMap(),
-		From:    from,
-		Target:  target,
-		styles:  styles,
+		context:      context,
+		keyMap:       config.Current.GetKeyMap(),
+		From:         from,
+		Target:       target,
+		styles:       styles,
+		destinations: destinations,
 	}
 }
@@ -0,0 +1,99 @@
+package duplicate
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/choose"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/reorder"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var revision = &jj.Commit{ChangeId: "abc", CommitId: "123"}
+
+func TestOperation_ChooseDestination(t *testing.T) {
+	selected := jj.NewSelectedRevisions(revision)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Duplicate(selected, "def", "--destination")).SetOutput([]byte("Duplicated 123 as xyz 456 message"))
+	defer commandRunner.Verify()
+
+	op := NewOperation(test.NewTestContext(commandRunner), selected, TargetDestination, []string{"abc", "def"})
+
+	var options []string
+	test.SimulateModel(op, test.Type("c"), func(msg tea.Msg) {
+		if msg, ok := msg.(common.ShowChooseMsg); ok {
+			options = msg.Options
+		}
+	})
+	assert.Equal(t, []string{inPlace, "abc", "def"}, options)
+	assert.True(t, op.IsOverlay())
+
+	var messages []string
+	var selectedRevision string
+	test.SimulateModel(op, func() tea.Msg { return choose.SelectedMsg{Value: "def"} }, func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case intents.AddMessage:
+			messages = append(messages, msg.Text)
+		case common.RefreshMsg:
+			selectedRevision = msg.SelectedRevision
+		}
+	})
+	assert.Equal(t, []string{"Duplicated as xyz"}, messages)
+	assert.Equal(t, "xyz", selectedRevision)
+	assert.False(t, op.IsOverlay())
+}
+
+func TestOperation_DuplicateInPlace(t *testing.T) {
+	selected := jj.NewSelectedRevisions(revision)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect([]string{"duplicate", "-r", "abc"})
+	defer commandRunner.Verify()
+
+	op := NewOperation(test.NewTestContext(commandRunner), selected, TargetDestination, nil)
+	test.SimulateModel(op, test.Type("c"))
+	test.SimulateModel(op, func() tea.Msg { return choose.SelectedMsg{Value: inPlace} })
+}
+
+func TestOperation_ReorderCheckedRevisions(t *testing.T) {
+	other := &jj.Commit{ChangeId: "def", CommitId: "456"}
+	reordered := jj.NewSelectedRevisions(other, revision)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Duplicate(reordered, "xyz", "--destination")).SetOutput([]byte("Duplicated 456 as uvw 789 message"))
+	defer commandRunner.Verify()
+
+	op := NewOperation(test.NewTestContext(commandRunner), jj.NewSelectedRevisions(revision, other), TargetDestination, nil)
+	op.SetSelectedRevision(&jj.Commit{ChangeId: "xyz"})
+
+	var shown common.ShowReorderMsg
+	test.SimulateModel(op, test.Type("o"), func(msg tea.Msg) {
+		if msg, ok := msg.(common.ShowReorderMsg); ok {
+			shown = msg
+		}
+	})
+	assert.Equal(t, []string{"abc", "def"}, shown.Revisions.GetIds())
+	assert.True(t, op.IsOverlay())
+
+	test.SimulateModel(op, func() tea.Msg { return reorder.AppliedMsg{Revisions: reordered} })
+	assert.False(t, op.IsOverlay())
+	test.SimulateModel(op, test.Press(tea.KeyEnter))
+}
+
+func TestOperation_CancelReorderAbortsDuplicate(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	op := NewOperation(test.NewTestContext(commandRunner), jj.NewSelectedRevisions(revision, &jj.Commit{ChangeId: "def"}), TargetDestination, nil)
+	test.SimulateModel(op, test.Type("o"))
+
+	var closed bool
+	test.SimulateModel(op, func() tea.Msg { return reorder.CancelledMsg{} }, func(msg tea.Msg) {
+		if _, ok := msg.(common.CloseViewMsg); ok {
+			closed = true
+		}
+	})
+	assert.True(t, closed)
+}
@@ -0,0 +1,87 @@
+package edit
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/operations"
+)
+
+var (
+	_ operations.Operation = (*Operation)(nil)
+	_ common.Editable      = (*Operation)(nil)
+)
+
+type Operation struct {
+	model   *confirmation.Model
+	current *jj.Commit
+}
+
+func (e *Operation) IsEditing() bool {
+	return true
+}
+
+func (e *Operation) Init() tea.Cmd {
+	return nil
+}
+
+func (e *Operation) Update(msg tea.Msg) tea.Cmd {
+	return e.model.Update(msg)
+}
+
+func (e *Operation) View() string {
+	return e.model.View()
+}
+
+func (e *Operation) ShortHelp() []key.Binding {
+	baseHelp := e.model.ShortHelp()
+
+	additionalHelp := key.NewBinding(
+		key.WithKeys("alt+enter"),
+		key.WithHelp("alt+enter", "force apply"),
+	)
+	return append(baseHelp, additionalHelp)
+}
+
+func (e *Operation) FullHelp() [][]key.Binding {
+	return [][]key.Binding{e.ShortHelp()}
+}
+
+func (e *Operation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
+	e.current = commit
+	return nil
+}
+
+func (e *Operation) Render(commit *jj.Commit, pos operations.RenderPosition) string {
+	isSelected := commit != nil && commit.GetChangeId() == e.current.GetChangeId()
+	if !isSelected || pos != operations.RenderPositionAfter {
+		return ""
+	}
+	return e.View()
+}
+
+func (e *Operation) Name() string {
+	return "edit"
+}
+
+// NewOperation asks for a confirmation before making the given revision the working copy.
+// jj refuses to edit immutable revisions unless ignoreImmutable is set; its error is shown as is.
+func NewOperation(context *context.MainContext, commit *jj.Commit, ignoreImmutable bool) *Operation {
+	cmd := func(ignoreImmutable bool) tea.Cmd {
+		return context.RunCommand(jj.Edit(commit.GetChangeId(), ignoreImmutable), common.RefreshAndSelect("@"), common.Close)
+	}
+	model := confirmation.New(
+		[]string{"Are you sure you want to edit this revision?"},
+		confirmation.WithAltOption("Yes", cmd(ignoreImmutable), cmd(true), key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+		confirmation.WithOption("No", common.Close, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+		confirmation.WithStylePrefix("edit"),
+	)
+
+	return &Operation{
+		model:   model,
+		current: commit,
+	}
+}
@@ -0,0 +1,53 @@
+package edit
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var commit = &jj.Commit{ChangeId: "a"}
+
+func Test_Accept(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Edit("a", false))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), commit, false)
+	test.SimulateModel(model, model.Init())
+
+	var selected string
+	test.SimulateModel(model, test.Type("y"), func(msg tea.Msg) {
+		if refresh, ok := msg.(common.RefreshMsg); ok {
+			selected = refresh.SelectedRevision
+		}
+	})
+	assert.Equal(t, "@", selected)
+}
+
+func Test_ForceApply(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Edit("a", true))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), commit, false)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, func() tea.Msg {
+		return tea.KeyMsg{Type: tea.KeyEnter, Alt: true}
+	})
+}
+
+func Test_Cancel(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), commit, false)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Press(tea.KeyEsc))
+}
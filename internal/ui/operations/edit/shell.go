@@ -0,0 +1,46 @@
+package edit
+
+import (
+	"bytes"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+)
+
+// NewShellOperation warns that the working copy is moved to the given revision, and stays there,
+// before editing it and opening ui.shell in it.
+func NewShellOperation(context *context.MainContext, commit *jj.Commit) *Operation {
+	model := confirmation.New(
+		[]string{"The working copy (@) will be moved to this revision and stays there after the shell exits. Open a shell?"},
+		confirmation.WithOption("Yes", context.RunCommand(jj.Edit(commit.GetChangeId(), false), common.Close, openShellAt(context, commit)), key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+		confirmation.WithOption("No", common.Close, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+		confirmation.WithStylePrefix("edit"),
+	)
+
+	return &Operation{
+		model:   model,
+		current: commit,
+	}
+}
+
+// OpenShell opens ui.shell in the working copy, the revisions are refreshed when it exits
+func OpenShell() tea.Msg {
+	return common.ExecMsg{Line: config.Current.UI.Shell, Mode: common.ExecShell}
+}
+
+// openShellAt opens the shell only when the working copy has been moved to the revision, the
+// shell isn't opened when jj edit failed or was not run in the dry-run mode
+func openShellAt(context *context.MainContext, commit *jj.Commit) tea.Cmd {
+	return func() tea.Msg {
+		output, err := context.RunCommandImmediate(jj.GetIdsFromRevset("@ & " + commit.GetChangeId()))
+		if err != nil || len(bytes.TrimSpace(output)) == 0 {
+			return nil
+		}
+		return OpenShell()
+	}
+}
@@ -0,0 +1,63 @@
+package edit
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Shell_Accept(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Shell = "zsh"
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Edit("a", false))
+	commandRunner.Expect(jj.GetIdsFromRevset("@ & a")).SetOutput([]byte("a\n"))
+	defer commandRunner.Verify()
+
+	model := NewShellOperation(test.NewTestContext(commandRunner), commit)
+	test.SimulateModel(model, model.Init())
+
+	var exec *common.ExecMsg
+	test.SimulateModel(model, test.Type("y"), func(msg tea.Msg) {
+		if msg, ok := msg.(common.ExecMsg); ok {
+			exec = &msg
+		}
+	})
+	assert.Equal(t, &common.ExecMsg{Line: "zsh", Mode: common.ExecShell}, exec)
+}
+
+func Test_Shell_EditFailed(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Edit("a", false)).SetError(errors.New("Error: Commit abc is immutable"))
+	commandRunner.Expect(jj.GetIdsFromRevset("@ & a"))
+	defer commandRunner.Verify()
+
+	model := NewShellOperation(test.NewTestContext(commandRunner), commit)
+	test.SimulateModel(model, model.Init())
+
+	opened := false
+	test.SimulateModel(model, test.Type("y"), func(msg tea.Msg) {
+		if _, ok := msg.(common.ExecMsg); ok {
+			opened = true
+		}
+	})
+	assert.False(t, opened)
+}
+
+func Test_Shell_Cancel(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewShellOperation(test.NewTestContext(commandRunner), commit)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Press(tea.KeyEsc))
+}
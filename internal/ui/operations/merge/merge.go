@@ -0,0 +1,85 @@
+package merge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/operations"
+)
+
+var (
+	_ operations.Operation = (*Operation)(nil)
+	_ common.Editable      = (*Operation)(nil)
+)
+
+type Operation struct {
+	model   *confirmation.Model
+	current *jj.Commit
+	context *context.MainContext
+}
+
+func (o *Operation) IsEditing() bool {
+	return true
+}
+
+func (o *Operation) Init() tea.Cmd {
+	return nil
+}
+
+func (o *Operation) Update(msg tea.Msg) tea.Cmd {
+	return o.model.Update(msg)
+}
+
+func (o *Operation) View() string {
+	return o.model.View()
+}
+
+func (o *Operation) ShortHelp() []key.Binding {
+	return o.model.ShortHelp()
+}
+
+func (o *Operation) FullHelp() [][]key.Binding {
+	return [][]key.Binding{o.ShortHelp()}
+}
+
+func (o *Operation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
+	o.current = commit
+	return nil
+}
+
+func (o *Operation) Render(commit *jj.Commit, pos operations.RenderPosition) string {
+	isSelected := commit != nil && commit.GetChangeId() == o.current.GetChangeId()
+	if !isSelected || pos != operations.RenderPositionAfter {
+		return ""
+	}
+	return o.View()
+}
+
+func (o *Operation) Name() string {
+	return "merge"
+}
+
+// NewOperation asks for confirmation before creating a new revision whose parents are the selected revisions.
+// The new revision becomes the working copy, so the cursor follows @ after the refresh.
+func NewOperation(context *context.MainContext, selectedRevisions jj.SelectedRevisions) *Operation {
+	parents := selectedRevisions.GetIds()
+	message := fmt.Sprintf("Create a new merge revision with %d parents?", len(parents))
+	model := confirmation.New(
+		[]string{message, "Parents: " + strings.Join(parents, ", ")},
+		confirmation.WithOption("Yes",
+			context.RunCommand(jj.NewMerge(parents...), common.RefreshAndSelect("@"), common.Close),
+			key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+		confirmation.WithOption("No", common.Close, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+		confirmation.WithStylePrefix("revisions"),
+	)
+	return &Operation{
+		model:   model,
+		context: context,
+	}
+}
@@ -0,0 +1,51 @@
+package merge
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	first     = &jj.Commit{ChangeId: "a"}
+	second    = &jj.Commit{ChangeId: "b"}
+	revisions = jj.NewSelectedRevisions(first, second)
+)
+
+func Test_Accept(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.NewMerge("a", "b"))
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), revisions)
+	test.SimulateModel(model, model.Init())
+
+	model.SetSelectedRevision(first)
+	test.SimulateModel(model, test.Press(tea.KeyEnter))
+}
+
+func Test_Cancel(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), revisions)
+	test.SimulateModel(model, model.Init())
+
+	model.SetSelectedRevision(first)
+	test.SimulateModel(model, test.Press(tea.KeyEsc))
+}
+
+func Test_ConfirmationListsParents(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewOperation(test.NewTestContext(commandRunner), revisions)
+	test.SimulateModel(model, model.Init())
+
+	view := test.Stripped(model.View())
+	assert.Contains(t, view, "2 parents")
+	assert.Contains(t, view, "Parents: a, b")
+}
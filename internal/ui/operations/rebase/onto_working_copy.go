@@ -0,0 +1,95 @@
+package rebase
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/operations"
+)
+
+var (
+	_ operations.Operation = (*OntoWorkingCopyOperation)(nil)
+	_ common.Editable      = (*OntoWorkingCopyOperation)(nil)
+)
+
+// OntoWorkingCopyOperation asks for a confirmation before rebasing a revision onto @
+type OntoWorkingCopyOperation struct {
+	model   *confirmation.Model
+	current *jj.Commit
+}
+
+func (o *OntoWorkingCopyOperation) IsEditing() bool {
+	return true
+}
+
+func (o *OntoWorkingCopyOperation) Init() tea.Cmd {
+	return nil
+}
+
+func (o *OntoWorkingCopyOperation) Update(msg tea.Msg) tea.Cmd {
+	return o.model.Update(msg)
+}
+
+func (o *OntoWorkingCopyOperation) View() string {
+	return o.model.View()
+}
+
+func (o *OntoWorkingCopyOperation) ShortHelp() []key.Binding {
+	baseHelp := o.model.ShortHelp()
+
+	additionalHelp := key.NewBinding(
+		key.WithKeys("alt+enter"),
+		key.WithHelp("alt+enter", "force apply"),
+	)
+	return append(baseHelp, additionalHelp)
+}
+
+func (o *OntoWorkingCopyOperation) FullHelp() [][]key.Binding {
+	return [][]key.Binding{o.ShortHelp()}
+}
+
+func (o *OntoWorkingCopyOperation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
+	o.current = commit
+	return nil
+}
+
+func (o *OntoWorkingCopyOperation) Render(commit *jj.Commit, pos operations.RenderPosition) string {
+	isSelected := commit != nil && commit.GetChangeId() == o.current.GetChangeId()
+	if !isSelected || pos != operations.RenderPositionAfter {
+		return ""
+	}
+	return o.View()
+}
+
+func (o *OntoWorkingCopyOperation) Name() string {
+	return "rebase"
+}
+
+// NewOntoWorkingCopyOperation rebases the revision with its descendants onto @, or only the revision
+// itself when source is SourceRevision
+func NewOntoWorkingCopyOperation(context *context.MainContext, commit *jj.Commit, source Source) *OntoWorkingCopyOperation {
+	message := fmt.Sprintf("Are you sure you want to rebase %s and its descendants onto @?", commit.GetChangeId())
+	if source == SourceRevision {
+		message = fmt.Sprintf("Are you sure you want to rebase %s onto @?", commit.GetChangeId())
+	}
+	cmd := func(ignoreImmutable bool) tea.Cmd {
+		args := jj.Rebase(jj.NewSelectedRevisions(commit), "@", sourceToFlags[source], targetToFlags[TargetDestination], false, ignoreImmutable)
+		return context.RunCommand(args, common.RefreshAndSelect(commit.GetChangeId()), common.Close)
+	}
+	model := confirmation.New(
+		[]string{message},
+		confirmation.WithAltOption("Yes", cmd(false), cmd(true), key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+		confirmation.WithOption("No", common.Close, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
+		confirmation.WithStylePrefix("rebase"),
+	)
+
+	return &OntoWorkingCopyOperation{
+		model:   model,
+		current: commit,
+	}
+}
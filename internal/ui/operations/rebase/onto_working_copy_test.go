@@ -0,0 +1,54 @@
+package rebase
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOntoWorkingCopy_Accept(t *testing.T) {
+	commit := &jj.Commit{ChangeId: "a"}
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Rebase(jj.NewSelectedRevisions(commit), "@", "--source", "--destination", false, false))
+	defer commandRunner.Verify()
+
+	model := NewOntoWorkingCopyOperation(test.NewTestContext(commandRunner), commit, SourceDescendants)
+	test.SimulateModel(model, model.Init())
+
+	var selected string
+	test.SimulateModel(model, test.Type("y"), func(msg tea.Msg) {
+		if refresh, ok := msg.(common.RefreshMsg); ok {
+			selected = refresh.SelectedRevision
+		}
+	})
+	assert.Equal(t, "a", selected)
+}
+
+func TestOntoWorkingCopy_OnlyRevision(t *testing.T) {
+	commit := &jj.Commit{ChangeId: "a"}
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Rebase(jj.NewSelectedRevisions(commit), "@", "--revisions", "--destination", false, true))
+	defer commandRunner.Verify()
+
+	model := NewOntoWorkingCopyOperation(test.NewTestContext(commandRunner), commit, SourceRevision)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, func() tea.Msg {
+		return tea.KeyMsg{Type: tea.KeyEnter, Alt: true}
+	})
+}
+
+func TestOntoWorkingCopy_Cancel(t *testing.T) {
+	commit := &jj.Commit{ChangeId: "a"}
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewOntoWorkingCopyOperation(test.NewTestContext(commandRunner), commit, SourceDescendants)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Press(tea.KeyEsc))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/reorder"
 )
 
 type Source int
@@ -58,6 +59,7 @@ type styles struct {
 var (
 	_ operations.Operation = (*Operation)(nil)
 	_ common.Focusable     = (*Operation)(nil)
+	_ common.Overlay       = (*Operation)(nil)
 )
 
 type Operation struct {
@@ -71,6 +73,7 @@ type Operation struct {
 	highlightedIds []string
 	styles         styles
 	SkipEmptied    bool
+	reordering     bool
 }
 
 type updateHighlightedIdsMsg struct {
@@ -83,17 +86,29 @@ func (r *Operation) IsFocused() bool {
 	return true
 }
 
+// IsOverlay keeps receiving messages while the checked revisions are being reordered
+func (r *Operation) IsOverlay() bool {
+	return r.reordering
+}
+
 func (r *Operation) Init() tea.Cmd {
 	return nil
 }
 
 func (r *Operation) Update(msg tea.Msg) tea.Cmd {
-	if msg, ok := msg.(updateHighlightedIdsMsg); ok {
+	switch msg := msg.(type) {
+	case updateHighlightedIdsMsg:
 		r.highlightedIds = msg.ids
-		return nil
-	}
-
-	if msg, ok := msg.(tea.KeyMsg); ok {
+	case reorder.AppliedMsg:
+		r.reordering = false
+		r.From = msg.Revisions
+	case reorder.CancelledMsg:
+		r.reordering = false
+		return common.Close
+	case tea.KeyMsg:
+		if r.reordering {
+			return nil
+		}
 		return r.HandleKey(msg)
 	}
 	return nil
@@ -125,6 +140,9 @@ func (r *Operation) HandleKey(msg tea.KeyMsg) tea.Cmd {
 		r.InsertStart = r.To
 	case key.Matches(msg, r.keyMap.Rebase.SkipEmptied):
 		r.SkipEmptied = !r.SkipEmptied
+	case key.Matches(msg, r.keyMap.Rebase.Reorder) && len(r.From.Revisions) > 1:
+		r.reordering = true
+		return reorder.Show("Reorder revisions to rebase", r.From)
 	case key.Matches(msg, r.keyMap.Apply, r.keyMap.ForceApply):
 		ignoreImmutable := key.Matches(msg, r.keyMap.ForceApply)
 		skipEmptied := r.SkipEmptied
@@ -183,9 +201,17 @@ func (r *Operation) ShortHelp() []key.Binding {
 		r.keyMap.Rebase.Onto,
 		r.keyMap.Rebase.Insert,
 		r.keyMap.Rebase.SkipEmptied,
+		r.reorderKey(),
 	}
 }
 
+// reorderKey is only offered when several revisions are rebased
+func (r *Operation) reorderKey() key.Binding {
+	binding := r.keyMap.Rebase.Reorder
+	binding.SetEnabled(len(r.From.Revisions) > 1)
+	return binding
+}
+
 func (r *Operation) FullHelp() [][]key.Binding {
 	return [][]key.Binding{r.ShortHelp()}
 }
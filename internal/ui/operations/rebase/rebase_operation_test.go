@@ -0,0 +1,23 @@
+package rebase
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/reorder"
+	"github.com/idursun/jjui/test"
+)
+
+func TestOperation_RebasesTheReorderedRevisionsInASingleCommand(t *testing.T) {
+	a, b := &jj.Commit{ChangeId: "a"}, &jj.Commit{ChangeId: "b"}
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.CommandArgs{"rebase", "--revisions", "b", "--revisions", "a", "--insert-after", "x"})
+	defer commandRunner.Verify()
+
+	op := NewOperation(test.NewTestContext(commandRunner), jj.NewSelectedRevisions(a, b), SourceRevision, TargetAfter)
+	op.SetSelectedRevision(&jj.Commit{ChangeId: "x"})
+	test.SimulateModel(op, test.Type("o"))
+	test.SimulateModel(op, func() tea.Msg { return reorder.AppliedMsg{Revisions: jj.NewSelectedRevisions(b, a)} })
+	test.SimulateModel(op, test.Press(tea.KeyEnter))
+}
@@ -28,9 +28,15 @@ type Operation struct {
 	keepEmptied           bool
 	useDestinationMessage bool
 	interactive           bool
+	describeDestination   bool
 	styles                styles
 }
 
+// DescribeDestinationMsg asks for the description of the revision the changes were squashed into
+type DescribeDestinationMsg struct {
+	ChangeId string
+}
+
 func (s *Operation) IsFocused() bool {
 	return true
 }
@@ -61,11 +67,23 @@ func (s *Operation) HandleKey(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, s.keyMap.Apply, s.keyMap.ForceApply):
 		ignoreImmutable := key.Matches(msg, s.keyMap.ForceApply)
 		args := jj.Squash(s.from, s.current.GetChangeId(), s.files, s.keepEmptied, s.useDestinationMessage, s.interactive, ignoreImmutable)
-		continuation := common.RefreshAndSelect(s.current.GetChangeId())
+		destination := s.current.GetChangeId()
+		continuations := []tea.Cmd{common.RefreshAndSelect(destination)}
+		if s.describeDestination {
+			continuations = append(continuations, common.OnCommandResult(func(result common.CommandCompletedMsg) tea.Cmd {
+				// nothing was squashed into the destination when the command failed
+				if result.Err != nil {
+					return nil
+				}
+				return func() tea.Msg {
+					return DescribeDestinationMsg{ChangeId: destination}
+				}
+			}))
+		}
 		if s.interactive || !s.useDestinationMessage {
-			return tea.Batch(common.Close, s.context.RunInteractiveCommand(args, continuation))
+			return tea.Batch(common.Close, s.context.RunInteractiveCommand(args, tea.Sequence(continuations...)))
 		} else {
-			return tea.Batch(common.Close, s.context.RunCommand(args, continuation))
+			return tea.Batch(common.Close, s.context.RunCommand(args, continuations...))
 		}
 	case key.Matches(msg, s.keyMap.Cancel):
 		return common.Close
@@ -138,6 +156,13 @@ func WithFiles(files []string) Option {
 	}
 }
 
+// WithDescribeDestination prompts for the description of the destination after squashing
+func WithDescribeDestination() Option {
+	return func(op *Operation) {
+		op.describeDestination = true
+	}
+}
+
 func NewOperation(context *context.MainContext, from jj.SelectedRevisions, opts ...Option) *Operation {
 	styles := styles{
 		dimmed:       common.DefaultPalette.Get("squash dimmed"),
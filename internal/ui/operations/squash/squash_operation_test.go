@@ -0,0 +1,43 @@
+package squash
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	source      = &jj.Commit{ChangeId: "abc", CommitId: "123"}
+	destination = &jj.Commit{ChangeId: "xyz", CommitId: "456"}
+)
+
+func describeDestination(t *testing.T, err error) []DescribeDestinationMsg {
+	selected := jj.NewSelectedRevisions(source)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Squash(selected, "xyz", nil, false, true, false, false)).SetError(err)
+	defer commandRunner.Verify()
+
+	op := NewOperation(test.NewTestContext(commandRunner), selected, WithDescribeDestination())
+	op.useDestinationMessage = true
+	op.SetSelectedRevision(destination)
+
+	var described []DescribeDestinationMsg
+	test.SimulateModel(test.CommandResults(op), test.Press(tea.KeyEnter), func(msg tea.Msg) {
+		if msg, ok := msg.(DescribeDestinationMsg); ok {
+			described = append(described, msg)
+		}
+	})
+	return described
+}
+
+func TestOperation_DescribesTheDestinationAfterSquashing(t *testing.T) {
+	assert.Equal(t, []DescribeDestinationMsg{{ChangeId: "xyz"}}, describeDestination(t, nil))
+}
+
+func TestOperation_DoesNotDescribeTheDestinationWhenSquashFails(t *testing.T) {
+	assert.Empty(t, describeDestination(t, errors.New("conflict")))
+}
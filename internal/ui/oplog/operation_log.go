@@ -2,6 +2,8 @@ package oplog
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,6 +13,8 @@ import (
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/common/list"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/input"
+	"github.com/idursun/jjui/internal/ui/intents"
 )
 
 type updateOpLogMsg struct {
@@ -35,6 +39,10 @@ type Model struct {
 	textStyle        lipgloss.Style
 	selectedStyle    lipgloss.Style
 	ensureCursorView bool
+	jumping          bool
+	count            list.CountPrefix
+	limit            int
+	hasMore          bool
 }
 
 func (m *Model) Len() int {
@@ -85,6 +93,9 @@ func (m *Model) ShortHelp() []key.Binding {
 		m.keymap.Diff,
 		m.keymap.OpLog.Restore,
 		m.keymap.OpLog.Revert,
+		m.keymap.OpLog.Jump,
+		m.keymap.OpLog.LoadMore,
+		m.keymap.OpLog.ViewAt,
 	}
 }
 
@@ -152,10 +163,23 @@ func (m *Model) Scroll(delta int) tea.Cmd {
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
+	case common.ThemeChangedMsg:
+		m.reloadStyles()
+		return nil
 	case updateOpLogMsg:
 		m.rows = msg.Rows
+		m.hasMore = m.limit > 0 && len(m.rows) >= m.limit
 		m.renderer.Reset()
 		return m.updateSelection()
+	case input.SelectedMsg:
+		if !m.jumping {
+			return nil
+		}
+		m.jumping = false
+		return m.jumpTo(msg.Value)
+	case input.CancelledMsg:
+		m.jumping = false
+		return nil
 	case tea.MouseMsg:
 		switch msg.Action {
 		case tea.MouseActionPress:
@@ -170,13 +194,24 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			return nil
 		}
 	case tea.KeyMsg:
+		switch m.count.Update(msg) {
+		case list.PrefixPending:
+			return nil
+		case list.PrefixTop:
+			return m.jumpToRow(0)
+		}
+		count := m.count.Take()
 		switch {
 		case key.Matches(msg, m.keymap.Cancel):
 			return tea.Batch(common.Close, common.Refresh, common.SelectionChanged)
 		case key.Matches(msg, m.keymap.Up, m.keymap.ScrollUp):
-			return m.navigate(-1, key.Matches(msg, m.keymap.ScrollUp))
+			return m.navigate(-count, key.Matches(msg, m.keymap.ScrollUp))
 		case key.Matches(msg, m.keymap.Down, m.keymap.ScrollDown):
-			return m.navigate(1, key.Matches(msg, m.keymap.ScrollDown))
+			return m.navigate(count, key.Matches(msg, m.keymap.ScrollDown))
+		case key.Matches(msg, m.keymap.JumpToTop):
+			return m.jumpToRow(0)
+		case key.Matches(msg, m.keymap.JumpToBottom):
+			return m.jumpToRow(len(m.rows) - 1)
 		case key.Matches(msg, m.keymap.Diff):
 			return func() tea.Msg {
 				output, _ := m.context.RunCommandImmediate(jj.OpShow(m.rows[m.cursor].OperationId))
@@ -186,6 +221,17 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			return tea.Batch(common.Close, m.context.RunCommand(jj.OpRestore(m.rows[m.cursor].OperationId), common.Refresh))
 		case key.Matches(msg, m.keymap.OpLog.Revert):
 			return tea.Batch(common.Close, m.context.RunCommand(jj.OpRevert(m.rows[m.cursor].OperationId), common.Refresh))
+		case key.Matches(msg, m.keymap.OpLog.Jump):
+			m.jumping = true
+			return input.ShowWithTitle("Jump to operation", "operation id: ")
+		case key.Matches(msg, m.keymap.OpLog.LoadMore):
+			return m.loadMore()
+		case key.Matches(msg, m.keymap.OpLog.ViewAt):
+			if len(m.rows) == 0 {
+				return nil
+			}
+			m.context.SetAtOperation(m.rows[m.cursor].OperationId)
+			return tea.Batch(common.Close, common.Refresh, common.SelectionChanged)
 		}
 
 	}
@@ -207,6 +253,30 @@ func (m *Model) navigate(delta int, page bool) tea.Cmd {
 	return m.updateSelection()
 }
 
+func (m *Model) jumpToRow(index int) tea.Cmd {
+	if len(m.rows) == 0 {
+		return nil
+	}
+	m.SetCursor(index)
+	return m.updateSelection()
+}
+
+// jumpTo moves the cursor to the first operation whose id starts with the given prefix
+func (m *Model) jumpTo(prefix string) tea.Cmd {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return nil
+	}
+	for i, row := range m.rows {
+		if strings.HasPrefix(row.OperationId, prefix) {
+			m.SetCursor(i)
+			return m.updateSelection()
+		}
+	}
+	err := fmt.Errorf("operation %s not found", prefix)
+	return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+}
+
 func (m *Model) updateSelection() tea.Cmd {
 	if len(m.rows) == 0 {
 		return nil
@@ -226,9 +296,20 @@ func (m *Model) View() string {
 	return m.textStyle.Render(content)
 }
 
+// loadMore reloads the op log with another page of operations, the cursor stays where it is
+// as the already loaded operations come first
+func (m *Model) loadMore() tea.Cmd {
+	if !m.hasMore {
+		return intents.Invoke(intents.AddMessage{Text: "all operations are loaded"})
+	}
+	m.limit += config.Current.OpLog.Limit
+	return m.load()
+}
+
 func (m *Model) load() tea.Cmd {
+	limit := m.limit
 	return func() tea.Msg {
-		output, err := m.context.RunCommandImmediate(jj.OpLog(config.Current.OpLog.Limit))
+		output, err := m.context.RunCommandImmediate(jj.OpLog(limit))
 		if err != nil {
 			panic(err)
 		}
@@ -238,19 +319,25 @@ func (m *Model) load() tea.Cmd {
 	}
 }
 
+// reloadStyles reads the styles from common.DefaultPalette
+func (m *Model) reloadStyles() {
+	m.textStyle = common.DefaultPalette.Get("oplog text")
+	m.selectedStyle = common.DefaultPalette.Get("oplog selected")
+}
+
 func New(context *context.MainContext) *Model {
 	keyMap := config.Current.GetKeyMap()
 	node := common.NewViewNode(0, 0)
 	m := &Model{
-		ViewNode:      node,
-		MouseAware:    common.NewMouseAware(),
-		context:       context,
-		keymap:        keyMap,
-		rows:          nil,
-		cursor:        0,
-		textStyle:     common.DefaultPalette.Get("oplog text"),
-		selectedStyle: common.DefaultPalette.Get("oplog selected"),
+		ViewNode:   node,
+		MouseAware: common.NewMouseAware(),
+		context:    context,
+		keymap:     keyMap,
+		rows:       nil,
+		cursor:     0,
+		limit:      config.Current.OpLog.Limit,
 	}
+	m.reloadStyles()
 	m.renderer = list.NewRenderer(m, node)
 	return m
 }
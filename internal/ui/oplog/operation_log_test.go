@@ -1,12 +1,18 @@
 package oplog
 
 import (
+	"os"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/common/list"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/input"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -63,3 +69,130 @@ func TestCancelKeyRefreshesPreview(t *testing.T) {
 	assert.True(t, hasRefresh, "expected RefreshMsg to be sent when Cancel key is pressed")
 	assert.True(t, hasSelectionChanged, "expected SelectionChangedMsg to be sent when Cancel key is pressed")
 }
+
+func TestJumpToOperationSelectsFirstPrefixMatch(t *testing.T) {
+	m := &Model{
+		ViewNode:   common.NewViewNode(0, 0),
+		MouseAware: common.NewMouseAware(),
+		context:    &context.MainContext{},
+		rows: []row{
+			{OperationId: "aaa111111111"},
+			{OperationId: "bbb111111111"},
+			{OperationId: "bbb222222222"},
+		},
+		keymap: config.Current.GetKeyMap(),
+	}
+	m.renderer = list.NewRenderer(m, m.ViewNode)
+
+	cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	require.NotNil(t, cmd)
+	assert.IsType(t, common.ShowInputMsg{}, cmd())
+
+	m.Update(input.SelectedMsg{Value: "bbb"})
+	assert.Equal(t, 1, m.cursor)
+}
+
+func TestJumpToOperationNotFound(t *testing.T) {
+	m := &Model{
+		ViewNode:   common.NewViewNode(0, 0),
+		MouseAware: common.NewMouseAware(),
+		context:    &context.MainContext{},
+		rows:       []row{{OperationId: "aaa111111111"}},
+		keymap:     config.Current.GetKeyMap(),
+		jumping:    true,
+	}
+
+	cmd := m.Update(input.SelectedMsg{Value: "ccc"})
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(intents.AddMessage)
+	require.True(t, ok)
+	assert.Error(t, msg.Err)
+	assert.Equal(t, 0, m.cursor)
+}
+
+func TestJumpToOperationIgnoresUnrequestedInput(t *testing.T) {
+	m := &Model{
+		ViewNode:   common.NewViewNode(0, 0),
+		MouseAware: common.NewMouseAware(),
+		context:    &context.MainContext{},
+		rows:       []row{{OperationId: "aaa111111111"}, {OperationId: "bbb111111111"}},
+		keymap:     config.Current.GetKeyMap(),
+	}
+
+	assert.Nil(t, m.Update(input.SelectedMsg{Value: "bbb"}))
+	assert.Equal(t, 0, m.cursor)
+}
+
+func TestNavigateHonoursCountAndJumps(t *testing.T) {
+	m := &Model{
+		ViewNode:   common.NewViewNode(0, 0),
+		MouseAware: common.NewMouseAware(),
+		context:    &context.MainContext{},
+		rows:       []row{{OperationId: "a"}, {OperationId: "b"}, {OperationId: "c"}, {OperationId: "d"}},
+		keymap:     config.Current.GetKeyMap(),
+	}
+	m.renderer = list.NewRenderer(m, m.ViewNode)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.Equal(t, 2, m.cursor)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	assert.Equal(t, 3, m.cursor)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	assert.Equal(t, 0, m.cursor)
+}
+
+func TestLoadMoreKeepsCursorAndRaisesLimit(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.OpLog.Limit = 2
+
+	commandRunner := test.NewTestCommandRunner(t)
+	output, err := os.ReadFile("testdata/multi.log")
+	require.NoError(t, err)
+	commandRunner.Expect(jj.OpLog(4)).SetOutput(output)
+	defer commandRunner.Verify()
+
+	m := New(test.NewTestContext(commandRunner))
+	m.Update(updateOpLogMsg{Rows: []row{{OperationId: "a"}, {OperationId: "b"}}})
+	m.SetCursor(1)
+
+	cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	require.NotNil(t, cmd)
+	m.Update(cmd())
+	assert.Equal(t, 4, m.limit)
+	assert.Equal(t, 1, m.cursor)
+	assert.Equal(t, "5ce8658087d8", m.rows[m.cursor].OperationId)
+}
+
+func TestLoadMoreWhenEverythingIsLoaded(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.OpLog.Limit = 2
+
+	m := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	m.Update(updateOpLogMsg{Rows: []row{{OperationId: "a"}}})
+
+	cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, intents.AddMessage{Text: "all operations are loaded"}, cmd())
+}
+
+func TestViewAtOperationSetsContext(t *testing.T) {
+	ctx := &context.MainContext{}
+	m := &Model{
+		ViewNode:   common.NewViewNode(0, 0),
+		MouseAware: common.NewMouseAware(),
+		context:    ctx,
+		rows:       []row{{OperationId: "aaa111111111"}, {OperationId: "bbb111111111"}},
+		cursor:     1,
+		keymap:     config.Current.GetKeyMap(),
+	}
+
+	cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, "bbb111111111", ctx.AtOperation)
+}
@@ -1,6 +1,8 @@
 package password
 
 import (
+	"slices"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -62,6 +64,12 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 				return common.TogglePasswordMsg{}
 			}
 		default:
+			if msg.Paste {
+				// pasted passphrases often end with a new line which must not be part of the password
+				msg.Runes = slices.DeleteFunc(slices.Clone(msg.Runes), func(r rune) bool {
+					return r == '\n' || r == '\r'
+				})
+			}
 			var cmd tea.Cmd
 			m.textinput, cmd = m.textinput.Update(msg)
 			return cmd
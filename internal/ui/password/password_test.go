@@ -0,0 +1,24 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Update_PastesPassword(t *testing.T) {
+	passwordCh := make(chan []byte, 1)
+	model := New(common.TogglePasswordMsg{Prompt: "passphrase: ", Password: passwordCh}, common.NewViewNode(80, 20))
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("secret\r\n"), Paste: true})
+
+	view := model.View()
+	assert.NotContains(t, view, "secret")
+	assert.Contains(t, view, strings.Repeat("*", len("secret")))
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "secret", string(<-passwordCh))
+}
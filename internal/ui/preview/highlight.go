@@ -0,0 +1,236 @@
+package preview
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// maxHighlightSize is the size above which file previews are shown as they are
+const maxHighlightSize = 256 * 1024
+
+type language struct {
+	keywords     []string
+	lineComments []string
+	quotes       string
+}
+
+var (
+	cLike = language{
+		keywords: []string{
+			"auto", "break", "case", "char", "class", "const", "continue", "default", "delete", "do", "double",
+			"else", "enum", "extern", "false", "float", "for", "goto", "if", "inline", "int", "long", "namespace",
+			"new", "nullptr", "private", "protected", "public", "return", "short", "signed", "sizeof", "static",
+			"struct", "switch", "template", "this", "true", "typedef", "union", "unsigned", "using", "virtual",
+			"void", "volatile", "while",
+		},
+		lineComments: []string{"//"},
+		quotes:       `"'`,
+	}
+	javaScript = language{
+		keywords: []string{
+			"async", "await", "break", "case", "catch", "class", "const", "continue", "default", "delete", "do",
+			"else", "export", "extends", "false", "finally", "for", "from", "function", "if", "import", "in",
+			"instanceof", "interface", "let", "new", "null", "return", "super", "switch", "this", "throw", "true",
+			"try", "type", "typeof", "undefined", "var", "void", "while", "yield",
+		},
+		lineComments: []string{"//"},
+		quotes:       "\"'`",
+	}
+	shell = language{
+		keywords: []string{
+			"case", "do", "done", "elif", "else", "esac", "export", "fi", "for", "function", "if", "in", "local",
+			"return", "then", "until", "while",
+		},
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+)
+
+var languages = map[string]language{
+	".go": {
+		keywords: []string{
+			"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "false", "for",
+			"func", "go", "goto", "if", "import", "interface", "map", "nil", "package", "range", "return", "select",
+			"struct", "switch", "true", "type", "var",
+		},
+		lineComments: []string{"//"},
+		quotes:       "\"'`",
+	},
+	".py": {
+		keywords: []string{
+			"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else",
+			"except", "False", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "None",
+			"nonlocal", "not", "or", "pass", "raise", "return", "True", "try", "while", "with", "yield",
+		},
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	},
+	".rs": {
+		keywords: []string{
+			"as", "async", "await", "break", "const", "continue", "crate", "dyn", "else", "enum", "extern", "false",
+			"fn", "for", "if", "impl", "in", "let", "loop", "match", "mod", "move", "mut", "pub", "ref", "return",
+			"self", "Self", "static", "struct", "super", "trait", "true", "type", "unsafe", "use", "where", "while",
+		},
+		lineComments: []string{"//"},
+		quotes:       `"`,
+	},
+	".lua": {
+		keywords: []string{
+			"and", "break", "do", "else", "elseif", "end", "false", "for", "function", "goto", "if", "in", "local",
+			"nil", "not", "or", "repeat", "return", "then", "true", "until", "while",
+		},
+		lineComments: []string{"--"},
+		quotes:       `"'`,
+	},
+	".toml": {
+		keywords:     []string{"true", "false"},
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	},
+	".c":    cLike,
+	".h":    cLike,
+	".cc":   cLike,
+	".cpp":  cLike,
+	".hpp":  cLike,
+	".java": cLike,
+	".cs":   cLike,
+	".js":   javaScript,
+	".jsx":  javaScript,
+	".ts":   javaScript,
+	".tsx":  javaScript,
+	".sh":   shell,
+	".bash": shell,
+	".zsh":  shell,
+}
+
+type syntaxStyles struct {
+	keyword lipgloss.Style
+	str     lipgloss.Style
+	comment lipgloss.Style
+	number  lipgloss.Style
+}
+
+func newSyntaxStyles() syntaxStyles {
+	return syntaxStyles{
+		keyword: common.DefaultPalette.Get("preview syntax keyword"),
+		str:     common.DefaultPalette.Get("preview syntax string"),
+		comment: common.DefaultPalette.Get("preview syntax comment"),
+		number:  common.DefaultPalette.Get("preview syntax number"),
+	}
+}
+
+// highlight colours the content of a file after the language of its
+// extension. Content that is already coloured, too large or in an unknown
+// language is returned as it is.
+func highlight(file string, content string, styles syntaxStyles) string {
+	lang, ok := languages[strings.ToLower(path.Ext(file))]
+	if !ok || len(content) > maxHighlightSize || strings.Contains(content, "\x1b[") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = lang.highlightLine(line, styles)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// alignAnnotations pads the revision written before each line of jj file annotate, everything
+// up to the first ": ", to the same width so that the lines of the file stay aligned. The lines
+// are highlighted together without the revisions when syntaxHighlight is set.
+func alignAnnotations(file string, content string, syntaxHighlight bool, styles syntaxStyles) string {
+	lines := strings.Split(content, "\n")
+	gutters := make([]string, len(lines))
+	width := 0
+	for i, line := range lines {
+		if before, after, ok := strings.Cut(line, ": "); ok {
+			gutters[i] = before + ": "
+			lines[i] = after
+			width = max(width, lipgloss.Width(gutters[i]))
+		}
+	}
+	code := strings.Join(lines, "\n")
+	if syntaxHighlight {
+		code = highlight(file, code, styles)
+	}
+	lines = strings.Split(code, "\n")
+	for i, line := range lines {
+		if gutters[i] != "" {
+			lines[i] = fmt.Sprintf("%*s%s", width, gutters[i], line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (l language) highlightLine(line string, styles syntaxStyles) string {
+	var sb strings.Builder
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		rest := string(runes[i:])
+		switch {
+		case l.startsComment(rest):
+			sb.WriteString(styles.comment.Render(rest))
+			return sb.String()
+		case strings.ContainsRune(l.quotes, r):
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(runes))
+			sb.WriteString(styles.str.Render(string(runes[i:end])))
+			i = end
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || unicode.IsLetter(runes[end]) || runes[end] == '.' || runes[end] == '_') {
+				end++
+			}
+			sb.WriteString(styles.number.Render(string(runes[i:end])))
+			i = end
+		case isIdentifier(r):
+			end := i
+			for end < len(runes) && (isIdentifier(runes[end]) || unicode.IsDigit(runes[end])) {
+				end++
+			}
+			word := string(runes[i:end])
+			if l.isKeyword(word) {
+				word = styles.keyword.Render(word)
+			}
+			sb.WriteString(word)
+			i = end
+		default:
+			sb.WriteRune(r)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+func (l language) startsComment(s string) bool {
+	for _, prefix := range l.lineComments {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l language) isKeyword(word string) bool {
+	for _, keyword := range l.keywords {
+		if keyword == word {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdentifier(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
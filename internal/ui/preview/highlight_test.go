@@ -0,0 +1,48 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+)
+
+func markedStyles() syntaxStyles {
+	mark := func(tag string) lipgloss.Style {
+		return lipgloss.NewStyle().Transform(func(s string) string { return "<" + tag + ">" + s + "</" + tag + ">" })
+	}
+	return syntaxStyles{keyword: mark("k"), str: mark("s"), comment: mark("c"), number: mark("n")}
+}
+
+func TestHighlight(t *testing.T) {
+	content := "func main() {\n\tx := \"a\\\"b\" + 42 // done\n}"
+	expected := "<k>func</k> main() {\n\tx := <s>\"a\\\"b\"</s> + <n>42</n> <c>// done</c>\n}"
+	assert.Equal(t, expected, highlight("main.go", content, markedStyles()))
+}
+
+func TestHighlight_LeavesContentAsIs(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "unknown extension", file: "notes.xyz", content: "func main() {}"},
+		{name: "already coloured", file: "main.go", content: "\x1b[32mfunc\x1b[0m main() {}"},
+		{name: "too large", file: "main.go", content: strings.Repeat("func ", maxHighlightSize/5+1)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.content, highlight(tc.file, tc.content, markedStyles()))
+		})
+	}
+}
+
+func TestAlignAnnotations(t *testing.T) {
+	content := "kx    1: func main() {\nkxyz    2: \treturn // done\nkx    3: }\n"
+	expected := "  kx    1: <k>func</k> main() {\nkxyz    2: \t<k>return</k> <c>// done</c>\n  kx    3: }\n"
+	assert.Equal(t, expected, alignAnnotations("main.go", content, true, markedStyles()))
+
+	expected = "  kx    1: func main() {\nkxyz    2: \treturn // done\n  kx    3: }\n"
+	assert.Equal(t, expected, alignAnnotations("main.go", content, false, markedStyles()))
+}
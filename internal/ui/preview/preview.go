@@ -2,6 +2,7 @@ package preview
 
 import (
 	"log"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/common/search"
 	"github.com/idursun/jjui/internal/ui/context"
 )
 
@@ -38,6 +40,11 @@ type Model struct {
 	contentWidth            int
 	context                 *context.MainContext
 	keyMap                  config.KeyMappings[key.Binding]
+	search                  *search.Model
+	// itemType is the type of the selected item shown in the preview
+	itemType reflect.Type
+	// annotate previews files with the annotate command
+	annotate bool
 }
 
 const (
@@ -106,6 +113,11 @@ func (m *Model) AtBottom() bool {
 	return m.previewAtBottom
 }
 
+// IsSearching reports whether the search prompt is waiting for a pattern
+func (m *Model) IsSearching() bool {
+	return m.search.Editing()
+}
+
 func (m *Model) WindowPercentage() float64 {
 	return m.previewWindowPercentage
 }
@@ -184,13 +196,42 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		case tea.MouseButtonWheelRight:
 			m.ScrollHorizontal(scrollAmount)
 		}
+	case common.ThemeChangedMsg:
+		// the syntax highlighting is done with the styles of the palette
+		return m.refreshPreview()
 	case common.SelectionChangedMsg, common.RefreshMsg:
+		// a different kind of item is shown with another command, start from the top
+		if itemType := reflect.TypeOf(m.context.SelectedItem); itemType != m.itemType {
+			m.itemType = itemType
+			m.reset()
+		}
 		return m.refreshPreview()
 	case updatePreviewContentMsg:
 		m.SetContent(msg.Content)
 		return nil
 	case tea.KeyMsg:
+		if m.search.Editing() {
+			cmd, applied := m.search.Update(msg)
+			if applied {
+				m.view.SetContent(m.search.Highlight())
+				m.jumpToMatch(m.search.Next(m.view.YOffset - 1))
+			}
+			return cmd
+		}
 		switch {
+		case key.Matches(msg, m.keyMap.Preview.Search):
+			return m.search.Start()
+		case key.Matches(msg, m.keyMap.Preview.SearchNext):
+			m.jumpToMatch(m.search.Next(m.view.YOffset))
+		case key.Matches(msg, m.keyMap.Preview.SearchPrev):
+			m.jumpToMatch(m.search.Prev(m.view.YOffset))
+		case key.Matches(msg, m.keyMap.Preview.Annotate):
+			m.annotate = !m.annotate
+			if _, ok := m.context.SelectedItem.(context.SelectedFile); !ok {
+				return nil
+			}
+			m.reset()
+			return m.refreshPreview()
 		case key.Matches(msg, m.keyMap.Preview.ScrollDown):
 			m.Scroll(1)
 		case key.Matches(msg, m.keyMap.Preview.ScrollUp):
@@ -206,12 +247,27 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 
 func (m *Model) SetContent(content string) {
 	m.content = strings.ReplaceAll(content, "\r", "")
-	m.view.SetContent(content)
+	m.search.SetContent(m.content)
+	m.view.SetContent(m.search.Highlight())
+}
+
+func (m *Model) jumpToMatch(line int, ok bool) {
+	if ok {
+		m.view.SetYOffset(line)
+	}
 }
 
 func (m *Model) View() string {
 	border := lipgloss.NewStyle().Border(lipgloss.NormalBorder(), m.AtBottom(), false, false, !m.AtBottom())
-	return border.Render(m.view.View())
+	if !m.search.Editing() && !m.search.Active() {
+		return border.Render(m.view.View())
+	}
+	prompt := m.search.View()
+	height := m.view.Height
+	m.view.Height = max(height-lipgloss.Height(prompt), 0)
+	content := lipgloss.JoinVertical(lipgloss.Left, m.view.View(), prompt)
+	m.view.Height = height
+	return border.Render(content)
 }
 
 func (m *Model) reset() {
@@ -220,47 +276,108 @@ func (m *Model) reset() {
 }
 
 func (m *Model) refreshPreview() tea.Cmd {
+	item := m.context.SelectedItem
+	width := strconv.Itoa(m.view.Width)
 	return common.Debounce(debounceId, debounceDuration, func() tea.Msg {
-		var args []string
-		width := strconv.Itoa(m.view.Width)
-		switch msg := m.context.SelectedItem.(type) {
-		case context.SelectedFile:
-			args = jj.TemplatedArgs(config.Current.Preview.FileCommand, map[string]string{
-				jj.RevsetPlaceholder:       m.context.CurrentRevset,
-				jj.ChangeIdPlaceholder:     msg.ChangeId,
-				jj.CommitIdPlaceholder:     msg.CommitId,
-				jj.FilePlaceholder:         msg.File,
-				jj.WidthPlaceholder:        width,
-			})
-		case context.SelectedRevision:
-			args = jj.TemplatedArgs(config.Current.Preview.RevisionCommand, map[string]string{
-				jj.RevsetPlaceholder:       m.context.CurrentRevset,
-				jj.ChangeIdPlaceholder:     msg.ChangeId,
-				jj.CommitIdPlaceholder:     msg.CommitId,
-				jj.WidthPlaceholder:        width,
-			})
-		case context.SelectedOperation:
-			args = jj.TemplatedArgs(config.Current.Preview.OplogCommand, map[string]string{
-				jj.RevsetPlaceholder:       m.context.CurrentRevset,
-				jj.OperationIdPlaceholder:  msg.OperationId,
-				jj.WidthPlaceholder:        width,
-			})
+		args, header, file := m.previewCommand(item, width)
+		if args == nil {
+			// nothing to preview for this kind of item
+			return updatePreviewContentMsg{}
 		}
 
 		output, _ := m.context.RunCommandImmediate(args)
+		content := string(output)
+		if header != nil {
+			if output, err := m.context.RunCommandImmediate(header); err == nil && len(output) > 0 {
+				content = string(output) + "\n" + content
+			}
+		}
+		syntaxHighlight := file != "" && config.Current.Preview.SyntaxHighlight
+		if _, ok := item.(context.SelectedFile); ok && m.annotate {
+			content = alignAnnotations(file, content, syntaxHighlight, newSyntaxStyles())
+		} else if syntaxHighlight {
+			content = highlight(file, content, newSyntaxStyles())
+		}
 		return updatePreviewContentMsg{
-			Content: string(output),
+			Content: content,
 		}
 	})
 }
 
+// previewCommand returns the command previewing the given item, the command of the header shown
+// above it and the file whose syntax is highlighted
+func (m *Model) previewCommand(item context.SelectedItem, width string) (args []string, header []string, file string) {
+	switch item := item.(type) {
+	case context.SelectedFile:
+		changeId := item.ChangeId
+		if m.context.IdType == config.IdTypeCommitId {
+			changeId = item.CommitId
+		}
+		command := config.Current.Preview.FileCommand
+		if m.annotate {
+			command = config.Current.Preview.AnnotateCommand
+		}
+		args = jj.TemplatedArgs(command, map[string]string{
+			jj.RevsetPlaceholder:   m.context.CurrentRevset,
+			jj.ChangeIdPlaceholder: changeId,
+			jj.CommitIdPlaceholder: item.CommitId,
+			jj.FilePlaceholder:     item.File,
+			jj.WidthPlaceholder:    width,
+		})
+		file = item.File
+	case context.SelectedRevision:
+		changeId := item.ChangeId
+		// show exactly the commit the revisions view points at when commit ids are preferred
+		if m.context.IdType == config.IdTypeCommitId {
+			changeId = item.CommitId
+		}
+		args = jj.TemplatedArgs(config.Current.Preview.RevisionCommand, map[string]string{
+			jj.RevsetPlaceholder:   m.context.CurrentRevset,
+			jj.ChangeIdPlaceholder: changeId,
+			jj.CommitIdPlaceholder: item.CommitId,
+			jj.WidthPlaceholder:    width,
+		})
+		if template := config.Current.Revisions.GetPreviewTemplate(); template != "" {
+			header = jj.RevisionHeader(changeId, template)
+		}
+	case context.SelectedOperation:
+		args = jj.TemplatedArgs(config.Current.Preview.OplogCommand, map[string]string{
+			jj.RevsetPlaceholder:      m.context.CurrentRevset,
+			jj.OperationIdPlaceholder: item.OperationId,
+			jj.WidthPlaceholder:       width,
+		})
+	}
+	return args, header, file
+}
+
+const (
+	minWindowPercentage = 10
+	maxWindowPercentage = 95
+	// minWindowSize is the number of cells the preview is never shrunk below
+	minWindowSize = 20
+)
+
+// SetWindowPercentage resizes the preview and remembers the new size for the
+// next launch
 func (m *Model) SetWindowPercentage(percentage float64) {
-	m.previewWindowPercentage = percentage
-	if m.previewWindowPercentage < 10 {
-		m.previewWindowPercentage = 10
-	} else if m.previewWindowPercentage > 95 {
-		m.previewWindowPercentage = 95
+	lower := float64(minWindowPercentage)
+	if size := m.parentSize(); size > 0 {
+		lower = max(lower, min(float64(minWindowSize*100)/float64(size), maxWindowPercentage))
+	}
+	m.previewWindowPercentage = max(lower, min(percentage, maxWindowPercentage))
+	if m.context.State != nil {
+		m.context.State.SetPreviewWidthPercentage(m.previewWindowPercentage)
+	}
+}
+
+func (m *Model) parentSize() int {
+	if m.Parent == nil {
+		return 0
+	}
+	if m.AtBottom() {
+		return m.Parent.Height
 	}
+	return m.Parent.Width
 }
 
 func (m *Model) Expand() {
@@ -295,5 +412,6 @@ func New(context *context.MainContext) *Model {
 		previewAtBottom:         previewAtBottom,
 		previewVisible:          config.Current.Preview.ShowAtStart,
 		previewWindowPercentage: config.Current.Preview.WidthPercentage,
+		search:                  search.New(),
 	}
 }
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -134,3 +136,62 @@ func TestModel_View(t *testing.T) {
 		})
 	}
 }
+
+func TestModel_ShrinkKeepsMinimumWidth(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := New(ctx)
+	model.Parent = common.NewViewNode(100, 10)
+
+	model.SetWindowPercentage(50)
+	for range 20 {
+		model.Shrink()
+	}
+	assert.Equal(t, float64(minWindowSize), model.WindowPercentage())
+	assert.Equal(t, model.WindowPercentage(), ctx.State.PreviewWidthPercentage)
+}
+
+func TestModel_PreviewCommandFollowsSelectedItem(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := New(ctx)
+
+	args, _, file := model.previewCommand(context.SelectedFile{ChangeId: "abc", CommitId: "123", File: "dir/a file.go"}, "80")
+	assert.Equal(t, []string{"diff", "--color", "always", "-r", "abc", jj.EscapeFileName("dir/a file.go")}, args)
+	assert.Equal(t, "dir/a file.go", file)
+
+	model.annotate = true
+	args, _, _ = model.previewCommand(context.SelectedFile{ChangeId: "abc", CommitId: "123", File: "a.go"}, "80")
+	assert.Equal(t, []string{"file", "annotate"}, args[:2])
+	assert.Equal(t, jj.EscapeFileName("a.go"), args[len(args)-1])
+
+	args, _, _ = model.previewCommand(context.SelectedOperation{OperationId: "op"}, "80")
+	assert.Contains(t, args, "op")
+
+	args, _, _ = model.previewCommand(context.SelectedBookmark{Name: "main"}, "80")
+	assert.Nil(t, args)
+}
+
+func TestModel_ChangingTheKindOfSelectionScrollsToTop(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 10, 3))
+	model.SetContent("1\n2\n3\n4\n5\n6")
+
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+	model.Update(common.SelectionChangedMsg{})
+	model.Scroll(2)
+	model.Update(common.SelectionChangedMsg{})
+	assert.Equal(t, 2, model.view.YOffset, "the offset is kept for the same kind of item")
+
+	ctx.SelectedItem = context.SelectedFile{ChangeId: "abc", File: "a.go"}
+	model.Update(common.SelectionChangedMsg{})
+	assert.Equal(t, 0, model.view.YOffset)
+}
@@ -0,0 +1,163 @@
+package reorder
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+// AppliedMsg carries the revisions in the order they are passed to jj
+type AppliedMsg struct {
+	Revisions jj.SelectedRevisions
+}
+
+type CancelledMsg struct{}
+
+var (
+	_ common.Model     = (*Model)(nil)
+	_ common.IViewNode = (*Model)(nil)
+	_ help.KeyMap      = (*Model)(nil)
+)
+
+// Model lists the checked revisions of a batch operation so that their order can be changed
+// before the operation is applied
+type Model struct {
+	*common.ViewNode
+	revisions []*jj.Commit
+	cursor    int
+	title     string
+	keymap    config.KeyMappings[key.Binding]
+	styles    styles
+}
+
+type styles struct {
+	border   lipgloss.Style
+	text     lipgloss.Style
+	title    lipgloss.Style
+	selected lipgloss.Style
+	dimmed   lipgloss.Style
+}
+
+func New(title string, revisions jj.SelectedRevisions) *Model {
+	return &Model{
+		ViewNode:  common.NewViewNode(0, 0),
+		revisions: slices.Clone(revisions.Revisions),
+		title:     title,
+		keymap:    config.Current.GetKeyMap(),
+		styles: styles{
+			border:   common.DefaultPalette.GetBorder("reorder border", lipgloss.RoundedBorder()),
+			text:     common.DefaultPalette.Get("reorder text"),
+			title:    common.DefaultPalette.Get("reorder title"),
+			selected: common.DefaultPalette.Get("reorder selected"),
+			dimmed:   common.DefaultPalette.Get("reorder dimmed"),
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keymap.Reorder.MoveUp):
+			m.swap(-1)
+		case key.Matches(msg, m.keymap.Reorder.MoveDown):
+			m.swap(1)
+		case key.Matches(msg, m.keymap.Up):
+			m.cursor = max(m.cursor-1, 0)
+		case key.Matches(msg, m.keymap.Down):
+			m.cursor = min(m.cursor+1, len(m.revisions)-1)
+		case key.Matches(msg, m.keymap.Apply):
+			return newCmd(AppliedMsg{Revisions: jj.NewSelectedRevisions(m.revisions...)})
+		case key.Matches(msg, m.keymap.Cancel):
+			return newCmd(CancelledMsg{})
+		}
+	case common.CloseViewMsg:
+		return newCmd(CancelledMsg{})
+	}
+	return nil
+}
+
+// swap moves the revision under the cursor by delta places, the cursor moves along with it
+func (m *Model) swap(delta int) {
+	next := m.cursor + delta
+	if next < 0 || next >= len(m.revisions) {
+		return
+	}
+	m.revisions[m.cursor], m.revisions[next] = m.revisions[next], m.revisions[m.cursor]
+	m.cursor = next
+}
+
+// Revisions returns the revisions in their current order
+func (m *Model) Revisions() jj.SelectedRevisions {
+	return jj.NewSelectedRevisions(m.revisions...)
+}
+
+func (m *Model) Scroll(delta int) tea.Cmd {
+	m.cursor = max(min(m.cursor+delta, len(m.revisions)-1), 0)
+	return nil
+}
+
+func (m *Model) View() string {
+	var rows []string
+	if m.title != "" {
+		rows = append(rows, m.styles.title.Render(m.title))
+	}
+	for i, revision := range m.revisions {
+		line := lipgloss.JoinHorizontal(0, m.styles.text.Render(revision.GetChangeId()+" "), m.styles.dimmed.Render(revision.CommitId))
+		if i == m.cursor {
+			line = m.styles.selected.Render("> " + revision.GetChangeId() + " " + revision.CommitId)
+		} else {
+			line = m.styles.text.Render("  ") + line
+		}
+		rows = append(rows, line)
+	}
+	content := lipgloss.JoinVertical(0, rows...)
+	content = m.styles.border.Padding(0, 1).Render(content)
+	w, h := lipgloss.Size(content)
+
+	if m.Parent != nil {
+		pw, ph := m.Parent.Width, m.Parent.Height
+		sx := max((pw-w)/2, 0)
+		sy := max((ph-h)/2, 0)
+		m.SetFrame(cellbuf.Rect(sx, sy, w, h))
+	}
+
+	return content
+}
+
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		m.keymap.Up,
+		m.keymap.Down,
+		m.keymap.Reorder.MoveUp,
+		m.keymap.Reorder.MoveDown,
+		m.keymap.Apply,
+		m.keymap.Cancel,
+	}
+}
+
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
+
+func newCmd(msg tea.Msg) tea.Cmd {
+	return func() tea.Msg { return msg }
+}
+
+// Show opens the list of the revisions to reorder on top of the current view
+func Show(title string, revisions jj.SelectedRevisions) tea.Cmd {
+	return func() tea.Msg {
+		return common.ShowReorderMsg{Title: title, Revisions: revisions}
+	}
+}
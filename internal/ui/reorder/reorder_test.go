@@ -0,0 +1,64 @@
+package reorder
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var revisions = jj.NewSelectedRevisions(
+	&jj.Commit{ChangeId: "a", CommitId: "1"},
+	&jj.Commit{ChangeId: "b", CommitId: "2"},
+	&jj.Commit{ChangeId: "c", CommitId: "3"},
+)
+
+func TestModel_MovesRevisions(t *testing.T) {
+	model := New("Reorder", revisions)
+	test.SimulateModel(model, model.Init())
+
+	test.SimulateModel(model, test.Type("J"))
+	assert.Equal(t, []string{"b", "a", "c"}, model.Revisions().GetIds())
+	test.SimulateModel(model, test.Type("J"))
+	test.SimulateModel(model, test.Type("J"))
+	assert.Equal(t, []string{"b", "c", "a"}, model.Revisions().GetIds())
+
+	test.SimulateModel(model, test.Type("kK"))
+	assert.Equal(t, []string{"c", "b", "a"}, model.Revisions().GetIds())
+	assert.Equal(t, []string{"a", "b", "c"}, revisions.GetIds())
+}
+
+func TestModel_Apply(t *testing.T) {
+	model := New("Reorder", revisions)
+	test.SimulateModel(model, test.Type("jK"))
+
+	var applied AppliedMsg
+	test.SimulateModel(model, test.Press(tea.KeyEnter), func(msg tea.Msg) {
+		if msg, ok := msg.(AppliedMsg); ok {
+			applied = msg
+		}
+	})
+	assert.Equal(t, []string{"b", "a", "c"}, applied.Revisions.GetIds())
+}
+
+func TestModel_Cancel(t *testing.T) {
+	model := New("Reorder", revisions)
+
+	var cancelled bool
+	test.SimulateModel(model, test.Press(tea.KeyEsc), func(msg tea.Msg) {
+		if _, ok := msg.(CancelledMsg); ok {
+			cancelled = true
+		}
+	})
+	assert.True(t, cancelled)
+}
+
+func TestModel_View(t *testing.T) {
+	model := New("Reorder revisions to rebase", revisions)
+	view := test.Stripped(model.View())
+	assert.Contains(t, view, "Reorder revisions to rebase")
+	assert.Contains(t, view, "> a 1")
+	assert.Contains(t, view, "b 2")
+}
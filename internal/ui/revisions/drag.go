@@ -0,0 +1,100 @@
+package revisions
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+var _ common.Draggable = (*Model)(nil)
+
+// dragState tracks a revision being dragged onto another one to rebase it
+type dragState struct {
+	source *jj.Commit
+	target int
+	moved  bool
+}
+
+func (m *Model) IsDragging() bool {
+	return m.drag != nil
+}
+
+func (m *Model) DragStart(x, y int) bool {
+	if !m.InNormalMode() || m.context.ReadOnly {
+		return false
+	}
+	row := m.rowAtY(y)
+	if row == -1 {
+		return false
+	}
+	m.drag = &dragState{source: m.rows[row].Commit, target: row}
+	return true
+}
+
+func (m *Model) DragMove(x, y int) tea.Cmd {
+	if m.drag == nil {
+		return nil
+	}
+	m.drag.moved = true
+	m.drag.target = m.rowAtY(y)
+	return nil
+}
+
+// DragEnd rebases the dragged revision onto the revision it is dropped on.
+// Releasing the button without moving is handled as a click, and dropping
+// anywhere else cancels the drag.
+func (m *Model) DragEnd(x, y int) tea.Cmd {
+	drag := m.drag
+	m.drag = nil
+	if drag == nil {
+		return nil
+	}
+	if !drag.moved {
+		return m.ClickAt(x, y)
+	}
+	target := m.dropTarget(drag, m.rowAtY(y))
+	if target == nil {
+		return nil
+	}
+	return m.context.RunCommand(
+		jj.Rebase(jj.NewSelectedRevisions(drag.source), target.GetChangeId(), "--revisions", "--destination", false, false),
+		common.Refresh)
+}
+
+// DragHint describes what happens when the dragged revision is dropped
+func (m *Model) DragHint() string {
+	if m.drag == nil || !m.drag.moved {
+		return ""
+	}
+	source := m.drag.source.GetChangeId()
+	if target := m.dropTarget(m.drag, m.drag.target); target != nil {
+		return fmt.Sprintf("drop to rebase %s onto %s", source, target.GetChangeId())
+	}
+	return fmt.Sprintf("drag %s onto a revision to rebase it", source)
+}
+
+func (m *Model) isDropTarget(index int) bool {
+	return m.drag != nil && m.drag.moved && index == m.drag.target && m.dropTarget(m.drag, index) != nil
+}
+
+func (m *Model) dropTarget(drag *dragState, row int) *jj.Commit {
+	if row < 0 || row >= len(m.rows) {
+		return nil
+	}
+	target := m.rows[row].Commit
+	if target.GetChangeId() == drag.source.GetChangeId() {
+		return nil
+	}
+	return target
+}
+
+// rowAtY returns the index of the row rendered at the given screen row or -1
+func (m *Model) rowAtY(y int) int {
+	localY := y - m.Frame.Min.Y
+	if len(m.rows) == 0 || localY < 0 || localY >= m.Height {
+		return -1
+	}
+	return m.rowAtLine(m.renderer.ViewRange.Start + localY)
+}
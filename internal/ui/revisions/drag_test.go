@@ -0,0 +1,61 @@
+package revisions
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type discardUpdates struct{}
+
+func (discardUpdates) Update(tea.Msg) tea.Cmd { return nil }
+
+func newDraggableModel(t *testing.T, commandRunner *test.CommandRunner) *Model {
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+	model.View()
+	return model
+}
+
+func TestDrag_RebasesOntoDroppedRevision(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Rebase(jj.NewSelectedRevisions(rows[0].Commit), "b", "--revisions", "--destination", false, false))
+	defer commandRunner.Verify()
+
+	model := newDraggableModel(t, commandRunner)
+	assert.True(t, model.DragStart(0, 0))
+	model.DragMove(0, 1)
+	assert.Equal(t, "drop to rebase a onto b", model.DragHint())
+
+	// the refresh that follows the rebase is not of interest here
+	test.SimulateModel(discardUpdates{}, model.DragEnd(0, 1))
+	assert.False(t, model.IsDragging())
+}
+
+func TestDrag_DropOutsideRevisionsCancels(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := newDraggableModel(t, commandRunner)
+	assert.True(t, model.DragStart(0, 0))
+	model.DragMove(0, 20)
+	assert.Equal(t, "drag a onto a revision to rebase it", model.DragHint())
+
+	test.SimulateModel(model, model.DragEnd(0, 20))
+	assert.Empty(t, model.DragHint())
+}
+
+func TestDrag_ReleaseWithoutMovingSelectsRevision(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := newDraggableModel(t, commandRunner)
+	assert.True(t, model.DragStart(0, 1))
+	test.SimulateModel(model, model.DragEnd(0, 1))
+	assert.Equal(t, "b", model.SelectedRevision().ChangeId)
+}
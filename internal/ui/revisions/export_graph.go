@@ -0,0 +1,92 @@
+package revisions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+)
+
+// exportGraph writes the revisions to the given file, in the DOT format when the file name
+// ends with .dot or .gv and as they are shown without colours otherwise. A relative file name
+// is resolved against the location of the repository.
+func (m *Model) exportGraph(fileName string) tea.Cmd {
+	fileName = strings.TrimSpace(fileName)
+	if fileName == "" {
+		return nil
+	}
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(m.context.Location, fileName)
+	}
+	var text strings.Builder
+	for _, row := range m.rows {
+		for _, line := range row.Lines {
+			for _, segment := range line.Gutter.Segments {
+				text.WriteString(segment.Text)
+			}
+			for _, segment := range line.Segments {
+				text.WriteString(segment.Text)
+			}
+			text.WriteString("\n")
+		}
+	}
+	revset := m.context.CurrentRevset
+	return func() tea.Msg {
+		content := text.String()
+		switch strings.ToLower(filepath.Ext(fileName)) {
+		case ".dot", ".gv":
+			output, err := m.context.RunCommandImmediate(jj.GraphEdges(revset))
+			if err != nil {
+				err = fmt.Errorf("failed to export graph: %w", err)
+				return intents.AddMessage{Text: err.Error(), Err: err}
+			}
+			content = graphToDot(string(output))
+		}
+		if err := os.WriteFile(fileName, []byte(content), 0o644); err != nil {
+			err = fmt.Errorf("failed to export graph: %w", err)
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		return intents.AddMessage{Text: fmt.Sprintf("Graph exported to %s", fileName)}
+	}
+}
+
+// graphToDot turns the output of jj.GraphEdges into a DOT digraph with edges from the children
+// to their parents. Parents outside the revset are left out.
+func graphToDot(output string) string {
+	type node struct {
+		id      string
+		parents []string
+		label   string
+	}
+	var nodes []node
+	ids := make(map[string]bool)
+	for line := range strings.SplitSeq(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		nodes = append(nodes, node{id: fields[0], parents: strings.Fields(fields[1]), label: strings.TrimSpace(fields[2])})
+		ids[fields[0]] = true
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph jjui {\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&sb, "  %s [label=%s];\n", strconv.Quote(n.id), strconv.Quote(n.label))
+	}
+	for _, n := range nodes {
+		for _, parent := range n.parents {
+			if ids[parent] {
+				fmt.Fprintf(&sb, "  %s -> %s;\n", strconv.Quote(n.id), strconv.Quote(parent))
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
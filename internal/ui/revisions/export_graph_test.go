@@ -0,0 +1,74 @@
+package revisions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/input"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphToDot(t *testing.T) {
+	output := "c1\tc2\tabc first \"quoted\"\nc2\tc3 c9\tdef second\n"
+	expected := "digraph jjui {\n" +
+		"  node [shape=box];\n" +
+		"  \"c1\" [label=\"abc first \\\"quoted\\\"\"];\n" +
+		"  \"c2\" [label=\"def second\"];\n" +
+		"  \"c1\" -> \"c2\";\n" +
+		"}\n"
+	assert.Equal(t, expected, graphToDot(output))
+}
+
+func TestModel_ExportGraphAsText(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+
+	fileName := filepath.Join(t.TempDir(), "graph.txt")
+	model.exportingGraph = true
+	msg := model.Update(input.SelectedMsg{Value: fileName})()
+
+	assert.Equal(t, "Graph exported to "+fileName, msg.(intents.AddMessage).Text)
+	content, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, "|a\n|b\n", string(content))
+}
+
+func TestModel_ExportGraphToRelativePath(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Location = t.TempDir()
+	model := New(ctx)
+	model.updateGraphRows(rows, "a")
+
+	model.exportingGraph = true
+	model.Update(input.SelectedMsg{Value: "graph.txt"})()
+
+	content, err := os.ReadFile(filepath.Join(ctx.Location, "graph.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "|a\n|b\n", string(content))
+}
+
+func TestModel_ExportGraphAsDot(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GraphEdges("::@")).SetOutput([]byte("c1\tc2\ta\nc2\t\tb\n"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "::@"
+	model := New(ctx)
+	model.updateGraphRows(rows, "a")
+
+	fileName := filepath.Join(t.TempDir(), "graph.dot")
+	model.exportingGraph = true
+	model.Update(input.SelectedMsg{Value: fileName})()
+
+	content, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "\"c1\" -> \"c2\";")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"unicode"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/idursun/jjui/internal/parser"
@@ -28,6 +29,10 @@ type itemRenderer struct {
 	SearchText       string
 	AceJumpPrefix    *string
 	isChecked        bool
+	swapIds          bool
+	isDropTarget     bool
+	targetStyle      lipgloss.Style
+	emphasisStyle    *lipgloss.Style // set for the working copy and trunk() rows
 }
 
 func (ir itemRenderer) writeSection(w io.Writer, current parser.GraphGutter, extended parser.GraphGutter, highlight bool, section string, width int) {
@@ -183,6 +188,9 @@ func (ir itemRenderer) renderGutter(lw *strings.Builder, lineIndex int, segmente
 		if beforeChangeID != "" {
 			fmt.Fprint(lw, beforeChangeID)
 		}
+		if ir.isDropTarget {
+			fmt.Fprint(lw, ir.targetStyle.Render("<< onto >>"), " ")
+		}
 	}
 }
 
@@ -191,7 +199,11 @@ func (ir itemRenderer) renderGutter(lw *strings.Builder, lineIndex int, segmente
 func (ir itemRenderer) renderSegments(lw *strings.Builder, segmentedLine parser.GraphRowLine) {
 	beforeCommitID := ir.op.Render(ir.row.Commit, operations.RenderBeforeCommitId)
 
-	for _, segment := range segmentedLine.Segments {
+	segments := segmentedLine.Segments
+	if ir.swapIds && segmentedLine.Flags&parser.Revision == parser.Revision {
+		segments = swapIdSegments(segments, ir.row.Commit.ChangeId, ir.row.Commit.CommitId)
+	}
+	for _, segment := range segments {
 		if beforeCommitID != "" && segment.Text == ir.row.Commit.CommitId {
 			fmt.Fprint(lw, beforeCommitID)
 		}
@@ -220,10 +232,13 @@ func (ir itemRenderer) getSegmentStyle(segment screen.Segment) lipgloss.Style {
 	style := segment.Style
 	if ir.isHighlighted {
 		style = style.Inherit(ir.selectedStyle)
-	} else if ir.inLane {
-		style = style.Inherit(ir.textStyle)
-	} else {
+	} else if !ir.inLane {
+		// the rows the tracer dims stay dimmed even when they are emphasized
 		style = style.Inherit(ir.dimmedStyle).Faint(true)
+	} else if ir.emphasisStyle != nil {
+		style = style.Inherit(*ir.emphasisStyle).Inherit(ir.textStyle)
+	} else {
+		style = style.Inherit(ir.textStyle)
 	}
 	return style
 }
@@ -282,6 +297,21 @@ func (ir itemRenderer) renderNonHighlightableLines(w io.Writer, width int) {
 	}
 }
 
+// afterSectionOffset returns the number of lines rendered above the after section
+func (ir itemRenderer) afterSectionOffset() int {
+	h := 0
+	if before := ir.op.Render(ir.row.Commit, operations.RenderPositionBefore); before != "" {
+		h += strings.Count(before, "\n") + 1
+	}
+	for _, line := range ir.row.Lines {
+		if line.Flags&parser.Elided == parser.Elided {
+			break
+		}
+		h++
+	}
+	return h
+}
+
 func (ir itemRenderer) Height() int {
 	h := 0
 
@@ -357,3 +387,53 @@ func (ir itemRenderer) renderQuickSearchHighlight(w io.Writer, textToRender stri
 		lastIdx += searchTermIdx + len(searchTerm)
 	}
 }
+
+// swapIdSegments moves the commit id in place of the change id and vice versa,
+// so that the commit id becomes the primary identifier of the row.
+func swapIdSegments(segments []*screen.Segment, changeId string, commitId string) []*screen.Segment {
+	changeStart, changeEnd, ok := idSpan(segments, changeId)
+	if !ok {
+		return segments
+	}
+	commitStart, commitEnd, ok := idSpan(segments, commitId)
+	if !ok || commitStart < changeEnd {
+		return segments
+	}
+	swapped := make([]*screen.Segment, 0, len(segments))
+	swapped = append(swapped, segments[:changeStart]...)
+	swapped = append(swapped, segments[commitStart:commitEnd]...)
+	swapped = append(swapped, segments[changeEnd:commitStart]...)
+	swapped = append(swapped, segments[changeStart:changeEnd]...)
+	return append(swapped, segments[commitEnd:]...)
+}
+
+// idSpan returns the segments rendering the given shortest id, including the
+// remainder jj prints right after the unique prefix
+func idSpan(segments []*screen.Segment, id string) (int, int, bool) {
+	if id == "" {
+		return 0, 0, false
+	}
+	for i, segment := range segments {
+		if segment.Text != id {
+			continue
+		}
+		end := i + 1
+		if end < len(segments) && isIdRest(segments[end].Text) {
+			end++
+		}
+		return i, end, true
+	}
+	return 0, 0, false
+}
+
+func isIdRest(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -146,3 +146,48 @@ func TestRenderMainLines_WithElidedLine(t *testing.T) {
 	// Lines after elided should not appear
 	assert.NotContains(t, output, "Should not appear", "Lines after elided marker should not be rendered")
 }
+
+func TestSwapIdSegments(t *testing.T) {
+	segments := []*screen.Segment{
+		{Text: "kx"},
+		{Text: "yzabcd"},
+		{Text: " "},
+		{Text: "author"},
+		{Text: " "},
+		{Text: "1a"},
+		{Text: "bc1234"},
+		{Text: " description"},
+	}
+
+	var texts []string
+	for _, segment := range swapIdSegments(segments, "kx", "1a") {
+		texts = append(texts, segment.Text)
+	}
+	assert.Equal(t, "1abc1234 author kxyzabcd description", strings.Join(texts, ""))
+}
+
+func TestSwapIdSegments_KeepsSegmentsWhenCommitIdIsMissing(t *testing.T) {
+	segments := []*screen.Segment{{Text: "kx"}, {Text: " description"}}
+	assert.Equal(t, segments, swapIdSegments(segments, "kx", "1a"))
+}
+
+func TestGetSegmentStyle_Emphasis(t *testing.T) {
+	emphasis := lipgloss.NewStyle().Background(lipgloss.Color("22"))
+	renderer := itemRenderer{
+		selectedStyle: lipgloss.NewStyle().Background(lipgloss.Color("blue")),
+		textStyle:     lipgloss.NewStyle(),
+		dimmedStyle:   lipgloss.NewStyle(),
+		emphasisStyle: &emphasis,
+		inLane:        true,
+	}
+
+	segment := screen.Segment{Text: "abc", Style: lipgloss.NewStyle()}
+	assert.Equal(t, lipgloss.Color("22"), renderer.getSegmentStyle(segment).GetBackground())
+
+	renderer.inLane = false
+	assert.True(t, renderer.getSegmentStyle(segment).GetFaint())
+	assert.Equal(t, lipgloss.NoColor{}, renderer.getSegmentStyle(segment).GetBackground())
+
+	renderer.isHighlighted = true
+	assert.Equal(t, lipgloss.Color("blue"), renderer.getSegmentStyle(segment).GetBackground())
+}
@@ -11,11 +11,13 @@ import (
 	"slices"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/idursun/jjui/internal/ui/common/list"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/operations/ace_jump"
 	"github.com/idursun/jjui/internal/ui/operations/duplicate"
+	"github.com/idursun/jjui/internal/ui/operations/edit"
 	"github.com/idursun/jjui/internal/ui/operations/revert"
 	"github.com/idursun/jjui/internal/ui/operations/set_parents"
 
@@ -26,16 +28,21 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/clipboard"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/choose"
 	"github.com/idursun/jjui/internal/ui/common"
 	appContext "github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/graph"
+	"github.com/idursun/jjui/internal/ui/input"
 	"github.com/idursun/jjui/internal/ui/operations"
 	"github.com/idursun/jjui/internal/ui/operations/abandon"
 	"github.com/idursun/jjui/internal/ui/operations/bookmark"
 	"github.com/idursun/jjui/internal/ui/operations/details"
 	"github.com/idursun/jjui/internal/ui/operations/evolog"
+	"github.com/idursun/jjui/internal/ui/operations/merge"
 	"github.com/idursun/jjui/internal/ui/operations/rebase"
 	"github.com/idursun/jjui/internal/ui/operations/squash"
 )
@@ -50,6 +57,9 @@ var (
 	_ common.IMouseAware   = (*Model)(nil)
 )
 
+// writeClipboard is swapped out in tests
+var writeClipboard = clipboard.Copy
+
 type Model struct {
 	*common.ViewNode
 	*common.MouseAware
@@ -75,8 +85,47 @@ type Model struct {
 	matchedStyle     lipgloss.Style
 	ensureCursorView bool
 	requestInFlight  bool
+	describeTarget   string
+	describeNotice   string
+	drag             *dragState
+	targetStyle      lipgloss.Style
+	workingCopyStyle lipgloss.Style
+	trunkStyle       lipgloss.Style
+	trunkCommitId    string
+	trunkLoadedFor   *trunkSource // trunk() is resolved again when its revset or the jj config changes, or a command completes
+	count            list.CountPrefix
+	detailsFile      string   // opened in the details of the top revision after the next refresh
+	gotoChange       bool     // the change id prompt is open
+	gotoOutside      string   // the change outside the revset offered to be added to it
+	selectAfter      string   // selected after the next refresh
+	exportingGraph   bool     // the file name prompt of the graph export is open
+	selectingRevset  bool     // the revset prompt of select_revset is open
+	expanded         []string // the revisions whose elided ancestors are shown
+	expandedRevset   string   // the revset the expanded revisions belong to
+}
+
+// revsetMatchedMsg carries the revisions of the log matching the revset checked by select_revset
+type revsetMatchedMsg struct {
+	revisions []appContext.SelectedRevision
+}
+
+// selectionInvertedMsg carries the revisions of the log whose checked state is inverted
+type selectionInvertedMsg struct {
+	revisions []appContext.SelectedRevision
+}
+
+// gotoOutsideRevsetMsg reports that the change jumped to exists but isn't in the current revset
+type gotoOutsideRevsetMsg struct {
+	changeId string
+}
+
+// gotoUnloadedMsg is sent when the change is in the revset but below the rows loaded so far
+type gotoUnloadedMsg struct {
+	changeId string
 }
 
+const addToRevset = "Add it to the revset"
+
 type revisionsMsg struct {
 	msg tea.Msg
 }
@@ -93,6 +142,34 @@ type updateRevisionsMsg struct {
 	selectedRevision string
 }
 
+type newRevisionCreatedMsg struct {
+	changeId string
+}
+
+// bookmarkNamedMsg carries the name generated for the bookmark created on the revision
+type bookmarkNamedMsg struct {
+	changeId string
+	name     string
+}
+
+type bookmarkCreatedMsg struct {
+	name string
+}
+
+type trunkLoadedMsg struct {
+	commitId string
+}
+
+type setMainBookmarkMsg struct {
+	commit *jj.Commit
+	name   string
+}
+
+type rebaseOntoWorkingCopyMsg struct {
+	commit *jj.Commit
+	source rebase.Source
+}
+
 type startRowsStreamingMsg struct {
 	selectedRevision string
 	tag              uint64
@@ -151,6 +228,32 @@ func (m *Model) ClickAt(x, y int) tea.Cmd {
 	return m.updateSelection()
 }
 
+// clickOperation passes the click to the operation when it lands on the lines the operation renders
+// below the selected revision
+func (m *Model) clickOperation(op common.IMouseAware, x, y int) tea.Cmd {
+	node, ok := op.(common.IViewNode)
+	if !ok || m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	ir := m.GetItemRenderer(m.cursor).(*itemRenderer)
+	after := ir.op.Render(ir.row.Commit, operations.RenderPositionAfter)
+	if after == "" {
+		return nil
+	}
+
+	rowStart := 0
+	for i := range m.cursor {
+		rowStart += m.GetItemRenderer(i).Height()
+	}
+	top := m.Frame.Min.Y + rowStart + ir.afterSectionOffset() - m.renderer.ViewRange.Start
+	frame := cellbuf.Rect(m.Frame.Min.X, top, m.Frame.Dx(), lipgloss.Height(strings.TrimSuffix(after, "\n")))
+	if !cellbuf.Pos(x, y).In(frame) || !cellbuf.Pos(x, y).In(m.Frame) {
+		return nil
+	}
+	node.GetViewNode().Frame = frame
+	return op.ClickAt(x, y)
+}
+
 func (m *Model) rowAtLine(line int) int {
 	for _, rr := range m.renderer.RowRanges() {
 		if line >= rr.StartLine && line < rr.EndLine {
@@ -202,6 +305,10 @@ func (m *Model) GetItemRenderer(index int) list.IItemRenderer {
 		selectedStyle: m.selectedStyle,
 		matchedStyle:  m.matchedStyle,
 		isChecked:     m.renderer.selections[row.Commit.GetChangeId()],
+		swapIds:       m.context.IdType == config.IdTypeCommitId,
+		isDropTarget:  m.isDropTarget(index),
+		targetStyle:   m.targetStyle,
+		emphasisStyle: m.emphasisStyle(row.Commit),
 		isGutterInLane: func(lineIndex, segmentIndex int) bool {
 			return m.renderer.tracer.IsGutterInLane(index, lineIndex, segmentIndex)
 		},
@@ -298,11 +405,17 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case intents.Intent:
 		return m.handleIntent(msg)
+	case common.ThemeChangedMsg:
+		m.reloadStyles()
+		return m.op.Update(msg)
 	case tea.MouseMsg:
 		switch msg.Action {
 		case tea.MouseActionPress:
 			switch msg.Button {
 			case tea.MouseButtonLeft:
+				if op, ok := m.op.(common.IMouseAware); ok {
+					return m.clickOperation(op, msg.X, msg.Y)
+				}
 				if !m.InNormalMode() {
 					return nil
 				}
@@ -327,7 +440,105 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 	case common.CommandCompletedMsg:
 		m.output = msg.Output
 		m.err = msg.Err
+		// the command may have moved trunk(), e.g. set_main, fetch and push do
+		m.trunkLoadedFor = nil
+		// let the operation inspect the output of the command it started
+		return m.op.Update(msg)
+	case newRevisionCreatedMsg:
+		// nothing was created when jj new failed or was only shown in the dry-run mode
+		if m.err != nil || m.context.DryRun {
+			return common.Refresh
+		}
+		m.describeTarget = msg.changeId
+		m.describeNotice = fmt.Sprintf("Created %s", msg.changeId)
+		return tea.Batch(common.RefreshAndSelect(msg.changeId), input.ShowWithTitle(fmt.Sprintf("Describe %s", msg.changeId), ""))
+	case gotoUnloadedMsg:
+		return m.loadUntil(msg.changeId)
+	case gotoOutsideRevsetMsg:
+		m.gotoOutside = msg.changeId
+		return func() tea.Msg {
+			return common.ShowChooseMsg{
+				Options: []string{addToRevset, "Cancel"},
+				Title:   fmt.Sprintf("%s is not in the revset", msg.changeId),
+			}
+		}
+	case choose.SelectedMsg:
+		if m.gotoOutside == "" {
+			return nil
+		}
+		changeId := m.gotoOutside
+		m.gotoOutside = ""
+		if msg.Value != addToRevset {
+			return nil
+		}
+		m.selectAfter = changeId
+		return common.UpdateRevSet(fmt.Sprintf("(%s) | %s", m.context.CurrentRevset, changeId))
+	case choose.CancelledMsg:
+		m.gotoOutside = ""
 		return nil
+	case setMainBookmarkMsg:
+		m.op = bookmark.NewSetMainOperation(m.context, msg.commit, msg.name)
+		return m.op.Init()
+	case rebaseOntoWorkingCopyMsg:
+		m.op = rebase.NewOntoWorkingCopyOperation(m.context, msg.commit, msg.source)
+		return m.op.Init()
+	case trunkLoadedMsg:
+		m.trunkCommitId = msg.commitId
+		return nil
+	case bookmarkNamedMsg:
+		return m.context.RunCommand(jj.BookmarkCreate(msg.name, msg.changeId), func() tea.Msg {
+			return bookmarkCreatedMsg{name: msg.name}
+		})
+	case bookmarkCreatedMsg:
+		// the bookmark isn't created when the command before this message reported an error
+		if m.err != nil || m.context.DryRun {
+			return common.Refresh
+		}
+		return tea.Batch(common.Refresh, intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("created bookmark %s", msg.name)}))
+	case squash.DescribeDestinationMsg:
+		m.describeTarget = msg.ChangeId
+		m.describeNotice = ""
+		return input.ShowWithTitle(fmt.Sprintf("Describe %s", msg.ChangeId), "")
+	case revsetMatchedMsg:
+		for _, revision := range msg.revisions {
+			m.context.AddCheckedItem(revision)
+		}
+		return m.flashSelectionCount()
+	case selectionInvertedMsg:
+		for _, revision := range msg.revisions {
+			m.context.ToggleCheckedItem(revision)
+		}
+		return m.flashSelectionCount()
+	case input.SelectedMsg:
+		if m.gotoChange {
+			m.gotoChange = false
+			return m.gotoChangeId(msg.Value)
+		}
+		if m.exportingGraph {
+			m.exportingGraph = false
+			return m.exportGraph(msg.Value)
+		}
+		if m.selectingRevset {
+			m.selectingRevset = false
+			return m.selectRevset(msg.Value)
+		}
+		if m.describeTarget == "" {
+			return nil
+		}
+		changeId := m.describeTarget
+		notice := m.flashDescribeNotice()
+		if strings.TrimSpace(msg.Value) == "" {
+			return notice
+		}
+		return m.context.RunCommand(jj.SetDescription(changeId, msg.Value), common.Refresh, notice)
+	case input.CancelledMsg:
+		m.gotoChange = false
+		m.exportingGraph = false
+		m.selectingRevset = false
+		if m.describeTarget == "" {
+			return nil
+		}
+		return m.flashDescribeNotice()
 	case common.AutoRefreshMsg:
 		id, _ := m.context.RunCommandImmediate(jj.OpLogId(true))
 		currentOperationId := string(id)
@@ -338,7 +549,18 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 		}
 	case common.UpdateRevisionsFailedMsg:
 		m.isLoading = false
+		// the file is only opened after the refresh it was asked for
+		m.detailsFile = ""
 		return nil
+	case common.UpdateRevisionsSuccessMsg:
+		// a failed command doesn't hold back the messages that follow the next one
+		m.err = nil
+		file := m.detailsFile
+		m.detailsFile = ""
+		if file != "" && len(m.rows) > 0 {
+			m.SetCursor(0)
+			return m.openDetails(intents.OpenDetails{File: file})
+		}
 	case common.RefreshMsg:
 		return tea.Batch(m.refresh(intents.Refresh{
 			KeepSelections:   msg.KeepSelections,
@@ -354,6 +576,9 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 		m.offScreenRows = nil
 		m.revisionToSelect = msg.selectedRevision
 
+		if m.revisionToSelect == "" && config.Current.UI.FollowWorkingCopy {
+			m.revisionToSelect = "@"
+		}
 		// If the revision to select is not set, use the currently selected item
 		if m.revisionToSelect == "" {
 			switch selected := m.context.SelectedItem.(type) {
@@ -404,11 +629,17 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 			cmds = append(cmds, func() tea.Msg {
 				return common.UpdateRevisionsSuccessMsg{}
 			})
+		} else if !m.hasMore {
+			// nothing was loaded, so there is no revision to open the details of
+			m.detailsFile = ""
 		}
 		return tea.Batch(cmds...)
 	}
 
 	if len(m.rows) == 0 {
+		if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keymap.ResetRevset) && !m.isLoading && m.context.CurrentRevset != m.context.DefaultRevset {
+			return common.UpdateRevSet(m.context.DefaultRevset)
+		}
 		return nil
 	}
 
@@ -423,17 +654,31 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		switch m.count.Update(msg) {
+		case list.PrefixPending:
+			return nil
+		case list.PrefixTop:
+			return m.handleIntent(intents.Navigate{Target: intents.TargetTop})
+		}
+		count := m.count.Take()
 		switch {
 		case key.Matches(msg, m.keymap.Up, m.keymap.ScrollUp):
-			return m.handleIntent(intents.Navigate{Delta: -1, IsPage: key.Matches(msg, m.keymap.ScrollUp)})
+			return m.handleIntent(intents.Navigate{Delta: -count, IsPage: key.Matches(msg, m.keymap.ScrollUp)})
 		case key.Matches(msg, m.keymap.Down, m.keymap.ScrollDown):
-			return m.handleIntent(intents.Navigate{Delta: 1, IsPage: key.Matches(msg, m.keymap.ScrollDown)})
+			return m.handleIntent(intents.Navigate{Delta: count, IsPage: key.Matches(msg, m.keymap.ScrollDown)})
+		case key.Matches(msg, m.keymap.JumpToTop):
+			return m.handleIntent(intents.Navigate{Target: intents.TargetTop})
+		case key.Matches(msg, m.keymap.JumpToBottom):
+			return m.handleIntent(intents.Navigate{Target: intents.TargetBottom})
 		case key.Matches(msg, m.keymap.JumpToParent):
 			return m.handleIntent(intents.Navigate{Target: intents.TargetParent})
 		case key.Matches(msg, m.keymap.JumpToChildren):
 			return m.handleIntent(intents.Navigate{Target: intents.TargetChild})
 		case key.Matches(msg, m.keymap.JumpToWorkingCopy):
 			return m.handleIntent(intents.Navigate{Target: intents.TargetWorkingCopy})
+		case key.Matches(msg, m.keymap.GotoChange):
+			m.gotoChange = true
+			return input.ShowWithTitle("Go to change", "change id: ")
 		case key.Matches(msg, m.keymap.AceJump):
 			op := ace_jump.NewOperation(m, func(index int) parser.Row {
 				return m.rows[index]
@@ -456,6 +701,11 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 				item := appContext.SelectedRevision{ChangeId: changeId, CommitId: commit.CommitId}
 				m.context.ToggleCheckedItem(item)
 				m.jumpToParent(jj.NewSelectedRevisions(commit))
+			case key.Matches(msg, m.keymap.SelectRevset):
+				m.selectingRevset = true
+				return input.ShowWithTitle("Select revisions", "revset: ")
+			case key.Matches(msg, m.keymap.InvertSelection):
+				return m.invertSelection()
 			case key.Matches(msg, m.keymap.Cancel):
 				m.context.ClearCheckedItems(reflect.TypeFor[appContext.SelectedRevision]())
 				m.op = operations.NewDefault()
@@ -471,6 +721,10 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 				return m.handleIntent(intents.StartInlineDescribe{})
 			case key.Matches(msg, m.keymap.New):
 				return m.handleIntent(intents.StartNew{})
+			case key.Matches(msg, m.keymap.NewDescribed):
+				return m.handleIntent(intents.StartNew{WithDescription: true})
+			case key.Matches(msg, m.keymap.NewMerge):
+				return m.handleIntent(intents.StartNewMerge{})
 			case key.Matches(msg, m.keymap.Commit):
 				return m.handleIntent(intents.CommitWorkingCopy{})
 			case key.Matches(msg, m.keymap.Edit, m.keymap.ForceEdit):
@@ -478,6 +732,8 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 				return m.handleIntent(intents.StartEdit{IgnoreImmutable: ignoreImmutable})
 			case key.Matches(msg, m.keymap.Diffedit):
 				return m.handleIntent(intents.StartDiffEdit{})
+			case key.Matches(msg, m.keymap.OpenShell):
+				return m.handleIntent(intents.OpenShell{})
 			case key.Matches(msg, m.keymap.Absorb):
 				return m.handleIntent(intents.StartAbsorb{})
 			case key.Matches(msg, m.keymap.Abandon):
@@ -485,6 +741,10 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 			case key.Matches(msg, m.keymap.Bookmark.Set):
 				m.op = bookmark.NewSetBookmarkOperation(m.context, m.SelectedRevision().GetChangeId())
 				return m.op.Init()
+			case key.Matches(msg, m.keymap.Bookmark.Create):
+				return m.handleIntent(intents.CreateBookmark{})
+			case key.Matches(msg, m.keymap.Bookmark.SetMain):
+				return m.handleIntent(intents.SetMainBookmark{})
 			case key.Matches(msg, m.keymap.Split, m.keymap.SplitParallel):
 				return m.handleIntent(intents.StartSplit{
 					IsParallel: key.Matches(msg, m.keymap.SplitParallel),
@@ -495,6 +755,15 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 				return m.handleIntent(intents.StartEvolog{})
 			case key.Matches(msg, m.keymap.Diff):
 				return m.handleIntent(intents.ShowDiff{})
+			case key.Matches(msg, m.keymap.CopyPatch):
+				return m.handleIntent(intents.CopyPatch{})
+			case key.Matches(msg, m.keymap.ExportGraph):
+				m.exportingGraph = true
+				return input.ShowWithTitle("Export graph", "file: ")
+			case key.Matches(msg, m.keymap.ExpandElided):
+				return m.expandElided()
+			case key.Matches(msg, m.keymap.CollapseElided):
+				return m.collapseElided()
 			case key.Matches(msg, m.keymap.Refresh):
 				return m.handleIntent(intents.Refresh{})
 			case key.Matches(msg, m.keymap.Squash.Mode):
@@ -503,6 +772,12 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 				return m.handleIntent(intents.StartRevert{})
 			case key.Matches(msg, m.keymap.Rebase.Mode):
 				return m.handleIntent(intents.StartRebase{})
+			case key.Matches(msg, m.keymap.RebaseOntoWorkingCopy, m.keymap.RebaseRevisionOntoWorkingCopy):
+				source := rebase.SourceDescendants
+				if key.Matches(msg, m.keymap.RebaseRevisionOntoWorkingCopy) {
+					source = rebase.SourceRevision
+				}
+				return m.handleIntent(intents.RebaseOntoWorkingCopy{Source: source})
 			case key.Matches(msg, m.keymap.Duplicate.Mode):
 				return m.handleIntent(intents.StartDuplicate{})
 			case key.Matches(msg, m.keymap.SetParents):
@@ -528,8 +803,16 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		return m.startAbandon(intent)
 	case intents.StartNew:
 		return m.startNew(intent)
+	case intents.StartNewMerge:
+		return m.startNewMerge(intent)
 	case intents.CommitWorkingCopy:
 		return m.commitWorkingCopy()
+	case intents.CreateBookmark:
+		return m.createBookmark(intent)
+	case intents.SetMainBookmark:
+		return m.setMainBookmark(intent)
+	case intents.OpenShell:
+		return m.openShell(intent)
 	case intents.StartEdit:
 		return m.startEdit(intent)
 	case intents.StartDiffEdit:
@@ -548,10 +831,14 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		return m.startEvolog(intent)
 	case intents.ShowDiff:
 		return m.showDiff(intent)
+	case intents.CopyPatch:
+		return m.copyPatch(intent)
 	case intents.StartSplit:
 		return m.startSplit(intent)
 	case intents.StartRebase:
 		return m.startRebase(intent)
+	case intents.RebaseOntoWorkingCopy:
+		return m.rebaseOntoWorkingCopy(intent)
 	case intents.Refresh:
 		return m.refresh(intent)
 	}
@@ -559,22 +846,96 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 }
 
 func (m *Model) refresh(intent intents.Refresh) tea.Cmd {
+	if intent.SelectedRevision == "" && m.selectAfter != "" {
+		intent.SelectedRevision = m.selectAfter
+	}
+	m.selectAfter = ""
 	if !intent.KeepSelections {
 		m.context.ClearCheckedItems(reflect.TypeFor[appContext.SelectedRevision]())
 	}
 	m.isLoading = true
 	if config.Current.Revisions.LogBatching {
 		currentTag := m.tag.Add(1)
-		return m.loadStreaming(m.context.CurrentRevset, intent.SelectedRevision, currentTag)
+		return tea.Batch(m.loadStreaming(m.logRevset(), intent.SelectedRevision, currentTag), m.reloadTrunk())
+	}
+	return tea.Batch(m.load(m.logRevset(), intent.SelectedRevision), m.reloadTrunk())
+}
+
+// logRevset is the revset the revisions are loaded with, runs of intermediate revisions are left
+// out of it when revisions.elide is set
+func (m *Model) logRevset() string {
+	revset := m.context.CurrentRevset
+	elide, keep, _ := config.GetElision(config.Current)
+	if !elide || revset == "" {
+		return revset
+	}
+	if m.expandedRevset != revset {
+		m.expanded, m.expandedRevset = nil, revset
+	}
+	return jj.ElideRevset(revset, keep, m.expanded)
+}
+
+// expandElided shows the revisions elided below the selected revision in place, they are elided
+// again when the selected revision was already expanded
+func (m *Model) expandElided() tea.Cmd {
+	selected := m.SelectedRevision()
+	if elide, _, _ := config.GetElision(config.Current); !elide || selected == nil {
+		return nil
+	}
+	changeId := selected.GetChangeId()
+	if i := slices.Index(m.expanded, changeId); i >= 0 {
+		m.expanded = slices.Delete(m.expanded, i, i+1)
+	} else {
+		m.expanded = append(m.expanded, changeId)
+	}
+	return m.handleIntent(intents.Refresh{KeepSelections: true, SelectedRevision: changeId})
+}
+
+// collapseElided elides every expanded run again
+func (m *Model) collapseElided() tea.Cmd {
+	if len(m.expanded) == 0 {
+		return nil
+	}
+	m.expanded = nil
+	selected := ""
+	if revision := m.SelectedRevision(); revision != nil {
+		selected = revision.GetChangeId()
+	}
+	return m.handleIntent(intents.Refresh{KeepSelections: true, SelectedRevision: selected})
+}
+
+// trunkSource is what trunk() was resolved for
+type trunkSource struct {
+	revset string
+	config *config.JJConfig
+}
+
+// reloadTrunk resolves trunk() again when the revset or the jj config changed, or a command
+// completed, since it was last resolved
+func (m *Model) reloadTrunk() tea.Cmd {
+	source := trunkSource{revset: m.context.CurrentRevset, config: m.context.JJConfig}
+	if m.trunkLoadedFor != nil && *m.trunkLoadedFor == source {
+		return nil
+	}
+	m.trunkLoadedFor = &source
+	return m.loadTrunk
+}
+
+// loadTrunk finds trunk() to emphasize its row, repositories without one are left as they are
+func (m *Model) loadTrunk() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.GetTrunk())
+	if err != nil {
+		return trunkLoadedMsg{}
 	}
-	return m.load(m.context.CurrentRevset, intent.SelectedRevision)
+	return trunkLoadedMsg{commitId: strings.TrimSpace(string(output))}
 }
 
-func (m *Model) openDetails(_ intents.OpenDetails) tea.Cmd {
+func (m *Model) openDetails(intent intents.OpenDetails) tea.Cmd {
 	if m.SelectedRevision() == nil {
 		return nil
 	}
 	model := details.NewOperation(m.context, m.SelectedRevision())
+	model.SelectFile(intent.File)
 	model.Parent = m.ViewNode
 	m.op = model
 	return m.op.Init()
@@ -596,7 +957,11 @@ func (m *Model) startSquash(intent intents.StartSquash) tea.Cmd {
 	} else if m.cursor < len(m.rows)-1 {
 		m.SetCursor(m.cursor + 1)
 	}
-	m.op = squash.NewOperation(m.context, selected, squash.WithFiles(intent.Files))
+	opts := []squash.Option{squash.WithFiles(intent.Files)}
+	if intent.DescribeDestination {
+		opts = append(opts, squash.WithDescribeDestination())
+	}
+	m.op = squash.NewOperation(m.context, selected, opts...)
 	return m.op.Init()
 }
 
@@ -621,6 +986,34 @@ func (m *Model) startRebase(intent intents.StartRebase) tea.Cmd {
 	return m.op.Init()
 }
 
+// rebaseOntoWorkingCopy refuses to rebase @ itself, or with its descendants a revision that @
+// descends from, as jj would report a cycle, and asks for a confirmation otherwise
+func (m *Model) rebaseOntoWorkingCopy(intent intents.RebaseOntoWorkingCopy) tea.Cmd {
+	commit := intent.Selected
+	if commit == nil {
+		commit = m.SelectedRevision()
+	}
+	if commit == nil {
+		return nil
+	}
+	source := intent.Source
+	return func() tea.Msg {
+		revset := commit.GetChangeId() + " & ::@"
+		if source == rebase.SourceRevision {
+			revset = commit.GetChangeId() + " & @"
+		}
+		output, err := m.context.RunCommandImmediate(jj.GetIdsFromRevset(revset))
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		if strings.TrimSpace(string(output)) != "" {
+			err := fmt.Errorf("cannot rebase %s onto @ as the working copy descends from it", commit.GetChangeId())
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		return rebaseOntoWorkingCopyMsg{commit: commit, source: source}
+	}
+}
+
 func (m *Model) startRevert(intent intents.StartRevert) tea.Cmd {
 	selected := intent.Selected
 	if len(selected.Revisions) == 0 {
@@ -647,7 +1040,11 @@ func (m *Model) startDuplicate(intent intents.StartDuplicate) tea.Cmd {
 		return nil
 	}
 
-	m.op = duplicate.NewOperation(m.context, selected, duplicate.TargetDestination)
+	var destinations []string
+	for _, row := range m.rows {
+		destinations = append(destinations, row.Commit.GetChangeId())
+	}
+	m.op = duplicate.NewOperation(m.context, selected, duplicate.TargetDestination, destinations)
 	return m.op.Init()
 }
 
@@ -669,9 +1066,98 @@ func (m *Model) startNew(intent intents.StartNew) tea.Cmd {
 	if len(selected.Revisions) == 0 {
 		selected = m.SelectedRevisions()
 	}
+	if intent.WithDescription {
+		return m.newWithDescription(selected)
+	}
 	return m.context.RunCommand(jj.New(selected), common.RefreshAndSelect("@"))
 }
 
+func (m *Model) startNewMerge(intent intents.StartNewMerge) tea.Cmd {
+	selected := intent.Selected
+	if len(selected.Revisions) == 0 {
+		selected = m.SelectedRevisions()
+	}
+	if len(selected.Revisions) < 2 {
+		return intents.Invoke(intents.AddMessage{Text: "check at least two revisions to create a merge"})
+	}
+	m.op = merge.NewOperation(m.context, selected)
+	return m.op.Init()
+}
+
+// flashDescribeNotice ends the describe prompt, flashing the notice that was kept for it if any
+func (m *Model) flashDescribeNotice() tea.Cmd {
+	notice := m.describeNotice
+	m.describeTarget, m.describeNotice = "", ""
+	if notice == "" {
+		return nil
+	}
+	return intents.Invoke(intents.AddMessage{Text: notice})
+}
+
+// newWithDescription creates the new revision first and then prompts for its description
+func (m *Model) newWithDescription(selected jj.SelectedRevisions) tea.Cmd {
+	return m.context.RunCommand(jj.New(selected), func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.GetIdsFromRevset("@"))
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		return newRevisionCreatedMsg{changeId: strings.TrimSpace(string(output))}
+	})
+}
+
+// setMainBookmark refuses to move the main bookmark backwards or sideways, as jj would without
+// --allow-backwards, and asks for a confirmation otherwise
+func (m *Model) setMainBookmark(intent intents.SetMainBookmark) tea.Cmd {
+	commit := intent.Selected
+	if commit == nil {
+		commit = m.SelectedRevision()
+	}
+	if commit == nil {
+		return nil
+	}
+	name := config.GetGitMainBookmark(config.Current)
+	return func() tea.Msg {
+		revset := fmt.Sprintf("present(%q) ~ ::%s", name, commit.GetChangeId())
+		output, err := m.context.RunCommandImmediate(jj.GetIdsFromRevset(revset))
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		if strings.TrimSpace(string(output)) != "" {
+			err := fmt.Errorf("cannot move %s to %s as it is not a descendant of %s", name, commit.GetChangeId(), name)
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		return setMainBookmarkMsg{commit: commit, name: name}
+	}
+}
+
+// createBookmark names the bookmark after the description of the revision, or the current time
+// when it has no description, so that no name has to be typed
+func (m *Model) createBookmark(intent intents.CreateBookmark) tea.Cmd {
+	commit := intent.Selected
+	if commit == nil {
+		commit = m.SelectedRevision()
+	}
+	if commit == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		description, err := m.context.RunCommandImmediate(jj.GetDescription(commit.GetChangeId()))
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		output, err := m.context.RunCommandImmediate(jj.BookmarkListAll())
+		if err != nil {
+			return common.CommandCompletedMsg{Err: err}
+		}
+		var existing []string
+		for _, b := range jj.ParseBookmarkListOutput(string(output)) {
+			existing = append(existing, b.Name)
+		}
+		name := bookmark.GenerateName(string(description), existing, time.Now())
+		return bookmarkNamedMsg{changeId: commit.GetChangeId(), name: name}
+	}
+}
+
 func (m *Model) commitWorkingCopy() tea.Cmd {
 	return m.context.RunInteractiveCommand(jj.CommitWorkingCopy(), common.Refresh)
 }
@@ -684,7 +1170,24 @@ func (m *Model) startEdit(intent intents.StartEdit) tea.Cmd {
 	if commit == nil {
 		return nil
 	}
-	return m.context.RunCommand(jj.Edit(commit.GetChangeId(), intent.IgnoreImmutable), common.Refresh)
+	m.op = edit.NewOperation(m.context, commit, intent.IgnoreImmutable)
+	return m.op.Init()
+}
+
+// openShell opens ui.shell in the working copy, after editing the selected revision when it isn't @ already
+func (m *Model) openShell(intent intents.OpenShell) tea.Cmd {
+	commit := intent.Selected
+	if commit == nil {
+		commit = m.SelectedRevision()
+	}
+	if commit == nil {
+		return nil
+	}
+	if commit.IsWorkingCopy {
+		return edit.OpenShell
+	}
+	m.op = edit.NewShellOperation(m.context, commit)
+	return m.op.Init()
 }
 
 func (m *Model) startDiffEdit(intent intents.StartDiffEdit) tea.Cmd {
@@ -750,15 +1253,28 @@ func (m *Model) navigate(intent intents.Navigate) tea.Cmd {
 
 	switch intent.Target {
 	case intents.TargetParent:
-		m.jumpToParent(m.SelectedRevisions())
+		var cmd tea.Cmd
+		if selected := m.SelectedRevisions(); len(selected.Revisions) == 1 {
+			cmd = m.jumpToFirstParent(selected.Revisions[0])
+		} else {
+			m.jumpToParent(selected)
+		}
 		m.ensureCursorView = ensureView
-		return m.updateSelection()
+		return tea.Batch(cmd, m.updateSelection())
 	case intents.TargetWorkingCopy:
 		if idx := m.selectRevision("@"); idx != -1 {
 			m.SetCursor(idx)
 		}
 		m.ensureCursorView = ensureView
 		return m.updateSelection()
+	case intents.TargetTop:
+		m.SetCursor(0)
+		m.ensureCursorView = ensureView
+		return m.updateSelection()
+	case intents.TargetBottom:
+		m.SetCursor(len(m.rows) - 1)
+		m.ensureCursorView = ensureView
+		return m.updateSelection()
 	case intents.TargetChild:
 		immediate, _ := m.context.RunCommandImmediate(jj.GetFirstChild(m.SelectedRevision()))
 		if idx := m.selectRevision(string(immediate)); idx != -1 {
@@ -830,6 +1346,11 @@ func (m *Model) startInlineDescribe(intent intents.StartInlineDescribe) tea.Cmd
 	if commit == nil {
 		return nil
 	}
+	// jj refuses to describe immutable revisions, so don't let the user type a description that will be lost
+	if output, err := m.context.RunCommandImmediate(jj.IsImmutable(commit.GetChangeId())); err == nil && string(output) == "true" {
+		err := fmt.Errorf("cannot describe immutable revision %s", commit.GetChangeId())
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
 	model := describe.NewOperation(m.context, commit)
 	model.Parent = m.ViewNode
 	m.op = model
@@ -851,6 +1372,39 @@ func (m *Model) showDiff(intent intents.ShowDiff) tea.Cmd {
 	}
 }
 
+// copyPatch copies the changes of the selected revisions to the clipboard, oldest first so that
+// the result applies cleanly
+func (m *Model) copyPatch(intent intents.CopyPatch) tea.Cmd {
+	selected := intent.Selected
+	if len(selected.Revisions) == 0 {
+		selected = m.SelectedRevisions()
+	}
+	if len(selected.Revisions) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		var patch strings.Builder
+		for _, commit := range slices.Backward(selected.Revisions) {
+			output, err := m.context.RunCommandImmediate(jj.DiffPatch(commit.GetChangeId()))
+			if err != nil {
+				return intents.AddMessage{Text: err.Error(), Err: err}
+			}
+			patch.Write(output)
+			// the output comes trimmed of its last newline, the patches are still separated by it
+			if len(output) > 0 && !bytes.HasSuffix(output, []byte("\n")) {
+				patch.WriteString("\n")
+			}
+		}
+		if strings.TrimSpace(patch.String()) == "" {
+			return intents.AddMessage{Text: "no changes"}
+		}
+		if err := writeClipboard(patch.String()); err != nil {
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		return intents.AddMessage{Text: fmt.Sprintf("copied patch of %d revision(s) to the clipboard", len(selected.Revisions))}
+	}
+}
+
 func (m *Model) startSplit(intent intents.StartSplit) tea.Cmd {
 	commit := intent.Selected
 	if commit == nil {
@@ -910,6 +1464,9 @@ func (m *Model) updateGraphRows(rows []parser.Row, selectedRevision string) {
 	}
 
 	currentSelectedRevision := selectedRevision
+	if currentSelectedRevision == "" && config.Current.UI.FollowWorkingCopy {
+		currentSelectedRevision = "@"
+	}
 	if cur := m.SelectedRevision(); currentSelectedRevision == "" && cur != nil {
 		currentSelectedRevision = cur.GetChangeId()
 	}
@@ -933,7 +1490,7 @@ func (m *Model) View() string {
 		if m.isLoading {
 			return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, "loading")
 		}
-		return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, "(no matching revisions)")
+		return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, m.emptyView())
 	}
 
 	if config.Current.UI.Tracer.Enabled {
@@ -950,6 +1507,20 @@ func (m *Model) View() string {
 	return output
 }
 
+// emptyView tells that the revset matches nothing and how to go back to the default revset
+func (m *Model) emptyView() string {
+	message := config.Current.Revisions.EmptyMessage
+	if message == "" {
+		message = "(no matching revisions)"
+	}
+	lines := []string{m.textStyle.Render(message), m.dimmedStyle.Render(m.context.CurrentRevset)}
+	if m.context.CurrentRevset != m.context.DefaultRevset && m.keymap.ResetRevset.Enabled() {
+		hint := fmt.Sprintf("press %s to show the default revset %s", m.keymap.ResetRevset.Help().Key, m.context.DefaultRevset)
+		lines = append(lines, "", m.dimmedStyle.Render(hint))
+	}
+	return lipgloss.JoinVertical(lipgloss.Center, lines...)
+}
+
 func (m *Model) load(revset string, selectedRevision string) tea.Cmd {
 	return func() tea.Msg {
 		output, err := m.context.RunCommandImmediate(jj.Log(revset, config.Current.Limit, m.context.JJConfig.Templates.Log))
@@ -1022,6 +1593,119 @@ func (m *Model) selectRevision(revision string) int {
 	return idx
 }
 
+// selectRevset checks the revisions of the log that match the revset, including the ones that
+// haven't been loaded yet
+func (m *Model) selectRevset(revset string) tea.Cmd {
+	revset = strings.TrimSpace(revset)
+	if revset == "" {
+		return nil
+	}
+	logRevset := m.logRevset()
+	limit := config.Current.Limit
+	return func() tea.Msg {
+		revisions, err := m.logRevisions(logRevset, limit)
+		if err == nil && logRevset != "" {
+			revset = fmt.Sprintf("(%s) & (%s)", revset, logRevset)
+		}
+		var output []byte
+		if err == nil {
+			output, err = m.context.RunCommandImmediate(jj.GetCommitIdsFromRevset(revset))
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to select %s: %w", revset, err)
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		matched := strings.Fields(string(output))
+		revisions = slices.DeleteFunc(revisions, func(revision appContext.SelectedRevision) bool {
+			return !slices.Contains(matched, revision.CommitId)
+		})
+		return revsetMatchedMsg{revisions: revisions}
+	}
+}
+
+// invertSelection checks the revisions of the log that aren't checked and unchecks the ones that
+// are, including the ones that haven't been loaded yet
+func (m *Model) invertSelection() tea.Cmd {
+	logRevset := m.logRevset()
+	limit := config.Current.Limit
+	return func() tea.Msg {
+		revisions, err := m.logRevisions(logRevset, limit)
+		if err != nil {
+			err = fmt.Errorf("failed to invert the selection: %w", err)
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		return selectionInvertedMsg{revisions: revisions}
+	}
+}
+
+// logRevisions lists every revision of the log, it is run in the background since the log may be
+// much longer than the loaded rows
+func (m *Model) logRevisions(revset string, limit int) ([]appContext.SelectedRevision, error) {
+	output, err := m.context.RunCommandImmediate(jj.GetRevisionIds(revset, limit))
+	if err != nil {
+		return nil, err
+	}
+	var revisions []appContext.SelectedRevision
+	for line := range strings.Lines(string(output)) {
+		if changeId, commitId, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+			revisions = append(revisions, appContext.SelectedRevision{ChangeId: changeId, CommitId: commitId})
+		}
+	}
+	return revisions, nil
+}
+
+func (m *Model) flashSelectionCount() tea.Cmd {
+	count := len(m.context.GetSelectedRevisions())
+	return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("%d revision(s) selected", count)})
+}
+
+// gotoChangeId moves the cursor to the revision whose change id starts with the given prefix.
+// When it isn't in the loaded rows, jj is asked whether the revision is further down the log or
+// exists outside the current revset.
+func (m *Model) gotoChangeId(prefix string) tea.Cmd {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return nil
+	}
+	for i, row := range m.rows {
+		if strings.HasPrefix(strings.ToLower(row.Commit.ChangeId), strings.ToLower(prefix)) {
+			m.SetCursor(i)
+			return m.updateSelection()
+		}
+	}
+	revset := m.context.CurrentRevset
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.GetIdsFromRevset(prefix))
+		changeId, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+		if err != nil || changeId == "" {
+			err := fmt.Errorf("change %s not found", prefix)
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		output, err = m.context.RunCommandImmediate(jj.GetIdsFromRevset(fmt.Sprintf("%s & (%s)", changeId, revset)))
+		if err == nil && strings.TrimSpace(string(output)) != "" {
+			return gotoUnloadedMsg{changeId: changeId}
+		}
+		return gotoOutsideRevsetMsg{changeId: changeId}
+	}
+}
+
+// loadUntil loads more rows of the log until the change is among them and moves the cursor to it
+func (m *Model) loadUntil(changeId string) tea.Cmd {
+	var cmds []tea.Cmd
+	for m.selectRevision(changeId) < 0 && m.hasMore {
+		loaded := len(m.rows)
+		cmds = append(cmds, m.requestMoreRows(m.tag.Load()))
+		if len(m.rows) == loaded {
+			break
+		}
+	}
+	if index := m.selectRevision(changeId); index >= 0 {
+		m.SetCursor(index)
+		cmds = append(cmds, m.updateSelection())
+	}
+	return tea.Batch(cmds...)
+}
+
 func (m *Model) search(startIndex int) int {
 	if m.quickSearch == "" {
 		return m.cursor
@@ -1065,15 +1749,55 @@ func New(c *appContext.MainContext) *Model {
 		offScreenRows: nil,
 		op:            operations.NewDefault(),
 		cursor:        0,
-		textStyle:     common.DefaultPalette.Get("revisions text"),
-		dimmedStyle:   common.DefaultPalette.Get("revisions dimmed"),
-		selectedStyle: common.DefaultPalette.Get("revisions selected"),
-		matchedStyle:  common.DefaultPalette.Get("revisions matched"),
 	}
+	m.reloadStyles()
 	m.renderer = newRevisionListRenderer(&m, m.ViewNode)
 	return &m
 }
 
+// OpenDetailsAfterRefresh opens the details of the top revision at file once the next refresh completes
+func (m *Model) OpenDetailsAfterRefresh(file string) {
+	m.detailsFile = file
+}
+
+// reloadStyles reads the styles from common.DefaultPalette
+func (m *Model) reloadStyles() {
+	m.textStyle = common.DefaultPalette.Get("revisions text")
+	m.dimmedStyle = common.DefaultPalette.Get("revisions dimmed")
+	m.selectedStyle = common.DefaultPalette.Get("revisions selected")
+	m.matchedStyle = common.DefaultPalette.Get("revisions matched")
+	m.targetStyle = common.DefaultPalette.Get("revisions target_marker")
+	m.workingCopyStyle = common.DefaultPalette.Get("revisions working_copy")
+	m.trunkStyle = common.DefaultPalette.Get("revisions trunk")
+}
+
+// emphasisStyle tells the working copy and trunk() apart from the other revisions
+func (m *Model) emphasisStyle(commit *jj.Commit) *lipgloss.Style {
+	switch {
+	case commit.IsWorkingCopy:
+		return &m.workingCopyStyle
+	case m.trunkCommitId != "" && commit.CommitId == m.trunkCommitId:
+		return &m.trunkStyle
+	}
+	return nil
+}
+
+// jumpToFirstParent moves the cursor to the first parent of a revision, the other parents of a merge are only reported
+func (m *Model) jumpToFirstParent(commit *jj.Commit) tea.Cmd {
+	output, _ := m.context.RunCommandImmediate(jj.GetParents(commit.CommitId))
+	parents := strings.Fields(string(output))
+	if len(parents) == 0 {
+		return nil
+	}
+	if idx := m.selectRevision(parents[0]); idx != -1 {
+		m.SetCursor(idx)
+	}
+	if len(parents) > 1 {
+		return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("%s has %d parents, jumped to the first one", commit.GetChangeId(), len(parents))})
+	}
+	return nil
+}
+
 func (m *Model) jumpToParent(revisions jj.SelectedRevisions) {
 	immediate, _ := m.context.RunCommandImmediate(jj.GetParent(revisions))
 	parentIndex := m.selectRevision(string(immediate))
@@ -1,13 +1,25 @@
 package revisions
 
 import (
+	"errors"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/parser"
 	"github.com/idursun/jjui/internal/screen"
+	"github.com/idursun/jjui/internal/ui/choose"
+	"github.com/idursun/jjui/internal/ui/common"
+	appContext "github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/input"
 	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/operations/bookmark"
+	"github.com/idursun/jjui/internal/ui/operations/edit"
+	"github.com/idursun/jjui/internal/ui/operations/rebase"
+	"github.com/idursun/jjui/internal/ui/operations/squash"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -94,3 +106,658 @@ func TestModel_OperationIntents(t *testing.T) {
 		})
 	}
 }
+
+func TestModel_NewWithDescription(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.New(jj.NewSelectedRevisions(rows[0].Commit)))
+	commandRunner.Expect(jj.GetIdsFromRevset("@")).SetOutput([]byte("xyz\n"))
+	commandRunner.Expect(jj.SetDescription("xyz", "new feature"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	cmd := model.Update(intents.StartNew{WithDescription: true})
+	msgs := cmd().(tea.BatchMsg)
+	model.Update(msgs[0]())
+	assert.Equal(t, newRevisionCreatedMsg{changeId: "xyz"}, msgs[1]())
+
+	model.Update(newRevisionCreatedMsg{changeId: "xyz"})
+	assert.Equal(t, "xyz", model.describeTarget)
+
+	cmd = model.Update(input.SelectedMsg{Value: "new feature"})
+	for _, c := range cmd().(tea.BatchMsg) {
+		c()
+	}
+	assert.Empty(t, model.describeTarget)
+}
+
+func TestModel_NewWithDescriptionSkippedWhenNewFails(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.New(jj.NewSelectedRevisions(rows[0].Commit))).SetError(errors.New("Error: immutable"))
+	commandRunner.Expect(jj.GetIdsFromRevset("@")).SetOutput([]byte("a\n"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	cmd := model.Update(intents.StartNew{WithDescription: true})
+	for _, c := range cmd().(tea.BatchMsg) {
+		model.Update(c())
+	}
+	assert.Empty(t, model.describeTarget)
+}
+
+func TestModel_CreateBookmark(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetDescription("a")).SetOutput([]byte("Fix the parser\n"))
+	commandRunner.Expect(jj.BookmarkListAll()).SetOutput([]byte("fix-the-parser;.;false;false;false;1\n"))
+	commandRunner.Expect(jj.BookmarkCreate("fix-the-parser-2", "a"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	cmd := model.Update(intents.CreateBookmark{})
+	assert.Equal(t, bookmarkNamedMsg{changeId: "a", name: "fix-the-parser-2"}, cmd())
+
+	msgs := model.Update(cmd())().(tea.BatchMsg)
+	model.Update(msgs[0]())
+	assert.Equal(t, bookmarkCreatedMsg{name: "fix-the-parser-2"}, msgs[1]())
+}
+
+func TestModel_CreateBookmarkReadOnly(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.ReadOnly = true
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	var completed []common.CommandCompletedMsg
+	test.SimulateModel(&discard{}, model.Update(bookmarkNamedMsg{changeId: "a", name: "main"}), func(msg tea.Msg) {
+		if msg, ok := msg.(common.CommandCompletedMsg); ok {
+			completed = append(completed, msg)
+		}
+	})
+	assert.Len(t, completed, 1)
+	assert.EqualError(t, completed[0].Err, "read-only mode, jj bookmark create -r a main is not run")
+}
+
+func TestModel_DryRunShowsTheNewRoutes(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("@")).SetOutput([]byte("a\n"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.DryRun = true
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	// the messages reaching the model are handed to it in order, what it returns is collected
+	var shown []string
+	var returned []tea.Msg
+	simulate := func(cmd tea.Cmd) {
+		test.SimulateModel(&discard{}, cmd, func(msg tea.Msg) {
+			switch msg := msg.(type) {
+			case common.CommandCompletedMsg:
+				shown = append(shown, msg.Output)
+				model.Update(msg)
+			case newRevisionCreatedMsg, bookmarkCreatedMsg:
+				returned = append(returned, model.Update(msg)())
+			}
+		})
+	}
+
+	simulate(model.Update(intents.StartNew{WithDescription: true}))
+	simulate(model.Update(bookmarkNamedMsg{changeId: "a", name: "main"}))
+	assert.Equal(t, []string{"dry run: jj new -r a", "dry run: jj bookmark create -r a main"}, shown)
+	// nothing was created, so there is neither a description to ask for nor a bookmark to report
+	assert.Equal(t, []tea.Msg{common.RefreshMsg{}, common.RefreshMsg{}}, returned)
+	assert.Empty(t, model.describeTarget)
+}
+
+// discard receives the messages of a simulation without reacting to them
+type discard struct{}
+
+func (d *discard) Update(tea.Msg) tea.Cmd {
+	return nil
+}
+
+func TestModel_DescribeDestinationAfterSquash(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.SetDescription("xyz", "combined"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+
+	cmd := model.Update(squash.DescribeDestinationMsg{ChangeId: "xyz"})
+	assert.Equal(t, common.ShowInputMsg{Title: "Describe xyz"}, cmd())
+
+	cmd = model.Update(input.SelectedMsg{Value: "combined"})
+	for _, c := range cmd().(tea.BatchMsg) {
+		c()
+	}
+	assert.Empty(t, model.describeTarget)
+}
+
+func TestModel_SuccessfulLoadClearsTheCommandError(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+
+	model.Update(common.CommandCompletedMsg{Err: errors.New("conflict")})
+	assert.Error(t, model.err)
+	model.Update(common.UpdateRevisionsSuccessMsg{})
+	assert.NoError(t, model.err)
+}
+
+func TestModel_DescribeDestinationCancelledKeepsSquash(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+	model.Update(squash.DescribeDestinationMsg{ChangeId: "xyz"})
+
+	assert.Nil(t, model.Update(input.CancelledMsg{}))
+	assert.Empty(t, model.describeTarget)
+}
+
+func TestModel_NewWithDescriptionCancelled(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+	model.Update(newRevisionCreatedMsg{changeId: "xyz"})
+
+	cmd := model.Update(input.CancelledMsg{})
+	assert.Equal(t, intents.AddMessage{Text: "Created xyz"}, cmd())
+	assert.Empty(t, model.describeTarget)
+}
+
+func TestModel_InlineDescribe(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.IsImmutable("a")).SetOutput([]byte("false"))
+	commandRunner.Expect(jj.GetDescription("a")).SetOutput([]byte("first line"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	model.Update(intents.StartInlineDescribe{})
+	assert.True(t, model.IsEditing())
+}
+
+func TestModel_InlineDescribeImmutableRevision(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.IsImmutable("a")).SetOutput([]byte("true"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+
+	cmd := model.Update(intents.StartInlineDescribe{})
+	msg, ok := cmd().(intents.AddMessage)
+	assert.True(t, ok)
+	assert.Equal(t, "cannot describe immutable revision a", msg.Text)
+	assert.Error(t, msg.Err)
+	assert.False(t, model.IsEditing())
+}
+
+func TestModel_FollowWorkingCopy(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+
+	workingCopyRows := []parser.Row{rows[0], {Commit: &jj.Commit{ChangeId: "c", CommitId: "7", IsWorkingCopy: true}}}
+
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(workingCopyRows, "a")
+	assert.Equal(t, "a", model.SelectedRevision().ChangeId)
+
+	model.updateGraphRows(workingCopyRows, "")
+	assert.Equal(t, "a", model.SelectedRevision().ChangeId, "keeps the selection when disabled")
+
+	config.Current.UI.FollowWorkingCopy = true
+	model.updateGraphRows(workingCopyRows, "")
+	assert.Equal(t, "c", model.SelectedRevision().ChangeId)
+}
+
+func TestModel_CopyPatch(t *testing.T) {
+	var copied string
+	origWriteClipboard := writeClipboard
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	defer func() { writeClipboard = origWriteClipboard }()
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.DiffPatch("b")).SetOutput([]byte("patch b\n"))
+	commandRunner.Expect(jj.DiffPatch("a")).SetOutput([]byte("patch a\n"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := New(ctx)
+	model.updateGraphRows(rows, "a")
+	ctx.AddCheckedItem(appContext.SelectedRevision{ChangeId: "a", CommitId: "8"})
+	ctx.AddCheckedItem(appContext.SelectedRevision{ChangeId: "b", CommitId: "9"})
+
+	cmd := model.Update(intents.CopyPatch{})
+	assert.Equal(t, intents.AddMessage{Text: "copied patch of 2 revision(s) to the clipboard"}, cmd())
+	assert.Equal(t, "patch b\npatch a\n", copied)
+}
+
+func TestModel_CopyPatchWithoutChanges(t *testing.T) {
+	origWriteClipboard := writeClipboard
+	writeClipboard = func(string) error {
+		t.Fatal("clipboard should not be written")
+		return nil
+	}
+	defer func() { writeClipboard = origWriteClipboard }()
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.DiffPatch("a"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+
+	cmd := model.Update(intents.CopyPatch{})
+	assert.Equal(t, intents.AddMessage{Text: "no changes"}, cmd())
+}
+
+func TestModel_NavigateToFirstParentOfMerge(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetParents("8")).SetOutput([]byte("9 7"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+
+	cmd := model.Update(intents.Navigate{Target: intents.TargetParent})
+	assert.Equal(t, "b", model.SelectedRevision().ChangeId)
+	var msgs []tea.Msg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if c != nil {
+			msgs = append(msgs, c())
+		}
+	}
+	assert.Contains(t, msgs, intents.AddMessage{Text: "a has 2 parents, jumped to the first one"})
+}
+
+// clickableOperation renders two lines below the selected revision and records the clicks it gets
+type clickableOperation struct {
+	*operations.Default
+	*common.ViewNode
+	clicks []cellbuf.Position
+}
+
+func (o *clickableOperation) Render(commit *jj.Commit, pos operations.RenderPosition) string {
+	if commit.ChangeId != "a" || pos != operations.RenderPositionAfter {
+		return ""
+	}
+	return "first\nsecond\n"
+}
+
+func (o *clickableOperation) ClickAt(x, y int) tea.Cmd {
+	o.clicks = append(o.clicks, cellbuf.Pos(x, y-o.Frame.Min.Y))
+	return nil
+}
+
+func (o *clickableOperation) Scroll(int) tea.Cmd {
+	return nil
+}
+
+func TestModel_ClickOnOperationLines(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 3, 100, 50))
+	model.updateGraphRows(rows, "a")
+	op := &clickableOperation{Default: operations.NewDefault(), ViewNode: common.NewViewNode(0, 0)}
+	model.op = op
+	model.View()
+
+	leftClick := func(y int) tea.MouseMsg {
+		return tea.MouseMsg{X: 2, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+	}
+	model.Update(leftClick(3))
+	model.Update(leftClick(5))
+	model.Update(leftClick(6))
+	assert.Equal(t, []cellbuf.Position{cellbuf.Pos(2, 1)}, op.clicks)
+	assert.Equal(t, "a", model.SelectedRevision().ChangeId)
+}
+
+func TestModel_GotoChangeInLog(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	assert.IsType(t, common.ShowInputMsg{}, cmd())
+	model.Update(input.SelectedMsg{Value: " B "})
+	assert.Equal(t, "b", model.SelectedRevision().ChangeId)
+}
+
+func TestModel_GotoChangeOutsideRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("xyz")).SetOutput([]byte("xyzw\n"))
+	commandRunner.Expect(jj.GetIdsFromRevset("xyzw & (::@)"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "::@"
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	msg := model.Update(input.SelectedMsg{Value: "xyz"})()
+	assert.Equal(t, gotoOutsideRevsetMsg{changeId: "xyzw"}, msg)
+	chooseMsg := model.Update(msg)()
+	assert.Equal(t, "xyzw is not in the revset", chooseMsg.(common.ShowChooseMsg).Title)
+
+	cmd := model.Update(choose.SelectedMsg{Value: addToRevset})
+	assert.Equal(t, common.UpdateRevSetMsg("(::@) | xyzw"), cmd())
+	assert.Equal(t, "xyzw", model.selectAfter)
+}
+
+func TestModel_GotoChangeNotLoadedYet(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("xyz")).SetOutput([]byte("xyzw\n"))
+	commandRunner.Expect(jj.GetIdsFromRevset("xyzw & (::@)")).SetOutput([]byte("xyzw\n"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "::@"
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	msg := model.Update(input.SelectedMsg{Value: "xyz"})()
+	assert.Equal(t, gotoUnloadedMsg{changeId: "xyzw"}, msg)
+}
+
+func TestModel_GotoChangeNotFound(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("xyz")).SetError(errors.New("revision doesn't exist"))
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	msg := model.Update(input.SelectedMsg{Value: "xyz"})()
+	assert.Equal(t, "change xyz not found", msg.(intents.AddMessage).Text)
+}
+
+func TestModel_RebaseOntoWorkingCopy(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("a & ::@"))
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	msg := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("O")})()
+	assert.Equal(t, rebaseOntoWorkingCopyMsg{commit: rows[0].Commit, source: rebase.SourceDescendants}, msg)
+	model.Update(msg)
+	assert.IsType(t, &rebase.OntoWorkingCopyOperation{}, model.op)
+}
+
+func TestModel_RebaseAncestorOntoWorkingCopy(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset("a & ::@")).SetOutput([]byte("a\n"))
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	msg := model.Update(intents.RebaseOntoWorkingCopy{Source: rebase.SourceDescendants})()
+	assert.Equal(t, "cannot rebase a onto @ as the working copy descends from it", msg.(intents.AddMessage).Text)
+}
+
+func TestModel_SetMainBookmark(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Git.MainBookmark = "trunk"
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset(`present("trunk") ~ ::a`))
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	msg := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B"), Alt: true})()
+	assert.Equal(t, setMainBookmarkMsg{commit: rows[0].Commit, name: "trunk"}, msg)
+	model.Update(msg)
+	assert.IsType(t, &bookmark.SetMainOperation{}, model.op)
+}
+
+func TestModel_SetMainBookmarkBackwards(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetIdsFromRevset(`present("main") ~ ::a`)).SetOutput([]byte("b\n"))
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	msg := model.Update(intents.SetMainBookmark{})()
+	assert.Equal(t, "cannot move main to a as it is not a descendant of main", msg.(intents.AddMessage).Text)
+}
+
+func TestModel_EmptyRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.DefaultRevset = "::@"
+	ctx.CurrentRevset = "author(nobody)"
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 10))
+
+	view := model.View()
+	assert.Contains(t, view, "No revisions match the revset")
+	assert.Contains(t, view, "author(nobody)")
+	assert.Contains(t, view, "press backspace to show the default revset ::@")
+
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, common.UpdateRevSetMsg("::@"), cmd())
+}
+
+func TestModel_EmptyDefaultRevsetHasNoResetHint(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.DefaultRevset = "::@"
+	ctx.CurrentRevset = "::@"
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 10))
+
+	assert.NotContains(t, model.View(), "press backspace")
+	assert.Nil(t, model.Update(tea.KeyMsg{Type: tea.KeyBackspace}))
+}
+
+func TestModel_EmphasizesWorkingCopyAndTrunk(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetTrunk()).SetOutput([]byte("9\n"))
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+
+	model.Update(model.loadTrunk())
+	assert.Equal(t, "9", model.trunkCommitId)
+
+	assert.Equal(t, &model.workingCopyStyle, model.emphasisStyle(&jj.Commit{ChangeId: "a", CommitId: "8", IsWorkingCopy: true}))
+	assert.Equal(t, &model.trunkStyle, model.emphasisStyle(&jj.Commit{ChangeId: "b", CommitId: "9"}))
+	assert.Nil(t, model.emphasisStyle(&jj.Commit{ChangeId: "c", CommitId: "7"}))
+}
+
+func TestModel_TrunkIsResolvedWhenTheRevsetOrConfigChanges(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "::@"
+	model := New(ctx)
+
+	assert.NotNil(t, model.reloadTrunk())
+	assert.Nil(t, model.reloadTrunk())
+
+	ctx.CurrentRevset = "mine()"
+	assert.NotNil(t, model.reloadTrunk())
+	assert.Nil(t, model.reloadTrunk())
+
+	ctx.JJConfig = &config.JJConfig{}
+	assert.NotNil(t, model.reloadTrunk())
+	assert.Nil(t, model.reloadTrunk())
+
+	model.Update(common.CommandCompletedMsg{})
+	assert.NotNil(t, model.reloadTrunk())
+	assert.Nil(t, model.reloadTrunk())
+}
+
+func TestModel_ExpandElided(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Revisions.Elide = "always"
+	config.Current.Revisions.LogBatching = false
+
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "mine()"
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+	assert.Equal(t, jj.ElideRevset("mine()", 0, nil), model.logRevset())
+
+	cmd := model.Update(test.Type("z")())
+	assert.NotNil(t, cmd)
+	assert.Equal(t, jj.ElideRevset("mine()", 0, []string{"a"}), model.logRevset())
+
+	// pressed again on the same revision, its run is elided again
+	assert.NotNil(t, model.Update(test.Type("z")()))
+	assert.Equal(t, jj.ElideRevset("mine()", 0, nil), model.logRevset())
+
+	model.Update(test.Type("z")())
+	assert.NotNil(t, model.Update(test.Type("Z")()))
+	assert.Equal(t, jj.ElideRevset("mine()", 0, nil), model.logRevset())
+	assert.Nil(t, model.Update(test.Type("Z")()))
+
+	model.Update(test.Type("z")())
+	ctx.CurrentRevset = "all()"
+	assert.Equal(t, jj.ElideRevset("all()", 0, nil), model.logRevset())
+}
+
+func TestModel_ExpandElided_NotElided(t *testing.T) {
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	assert.Nil(t, model.Update(test.Type("z")()))
+}
+
+func TestModel_OpenShell(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	model.Update(intents.OpenShell{})
+	assert.IsType(t, &edit.Operation{}, model.op)
+}
+
+func TestModel_OpenShell_AtWorkingCopy(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := New(test.NewTestContext(commandRunner))
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	msg := model.Update(intents.OpenShell{Selected: &jj.Commit{ChangeId: "a", IsWorkingCopy: true}})()
+	assert.Equal(t, common.ExecMsg{Line: config.Current.UI.Shell, Mode: common.ExecShell}, msg)
+}
+
+func TestModel_SelectRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetRevisionIds("::@", 0)).SetOutput([]byte("a 8\nb 9\nc 7\n"))
+	commandRunner.Expect(jj.GetCommitIdsFromRevset("(mine()) & (::@)")).SetOutput([]byte("9\n7\n"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "::@"
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows[:2], "a")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v"), Alt: true})
+	assert.True(t, model.selectingRevset)
+
+	var flash string
+	test.SimulateModel(model, model.Update(input.SelectedMsg{Value: "mine()"}), func(msg tea.Msg) {
+		if msg, ok := msg.(intents.AddMessage); ok {
+			flash = msg.Text
+		}
+	})
+	// c isn't loaded yet
+	assert.Equal(t, map[string]bool{"b": true, "c": true}, ctx.GetSelectedRevisions())
+	assert.Equal(t, "2 revision(s) selected", flash)
+}
+
+func TestModel_InvertSelection(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GetRevisionIds("::@", 0)).SetOutput([]byte("a 8\nb 9\nc 7\n"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "::@"
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows[:2], "a")
+	ctx.AddCheckedItem(appContext.SelectedRevision{ChangeId: "a", CommitId: "8"})
+
+	test.SimulateModel(model, func() tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i"), Alt: true} })
+	// c isn't loaded yet
+	assert.Equal(t, map[string]bool{"b": true, "c": true}, ctx.GetSelectedRevisions())
+}
+
+func TestModel_ThemeChangedReloadsStyles(t *testing.T) {
+	origPalette := common.DefaultPalette
+	defer func() { common.DefaultPalette = origPalette }()
+
+	assert.NoError(t, common.ApplyTheme(false, nil))
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	light := model.selectedStyle
+
+	assert.NoError(t, common.ApplyTheme(true, nil))
+	model.Update(common.ThemeChangedMsg{})
+	assert.NotEqual(t, light.GetBackground(), model.selectedStyle.GetBackground())
+	assert.Equal(t, common.DefaultPalette.Get("revisions selected").GetBackground(), model.selectedStyle.GetBackground())
+}
+
+func TestModel_DetailsFileClearedWhenRefreshFails(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := New(ctx)
+	model.SetFrame(cellbuf.Rect(0, 0, 100, 50))
+	model.updateGraphRows(rows, "a")
+
+	model.OpenDetailsAfterRefresh("file.go")
+	model.Update(common.UpdateRevisionsFailedMsg{Err: errors.New("failed")})
+	assert.Empty(t, model.detailsFile)
+
+	// a later refresh doesn't open the file asked for before the failed one
+	assert.Nil(t, model.Update(common.UpdateRevisionsSuccessMsg{}))
+}
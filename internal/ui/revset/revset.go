@@ -1,11 +1,17 @@
 package revset
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/common/autocompletion"
 	appContext "github.com/idursun/jjui/internal/ui/context"
@@ -22,6 +28,23 @@ type revsetMsg struct {
 	msg tea.Msg
 }
 
+const (
+	validationDebounceId       = "revset-validation"
+	validationDebounceDuration = 300 * time.Millisecond
+	countDebounceId            = "revset-count"
+)
+
+type revsetValidatedMsg struct {
+	revset string
+	err    string
+}
+
+// revsetCountedMsg carries the number of revisions in the revset, or -1 when jj failed to count them
+type revsetCountedMsg struct {
+	revset string
+	count  int
+}
+
 // Allow a message to be targeted to this component.
 func RevsetCmd(msg tea.Msg) tea.Cmd {
 	return func() tea.Msg {
@@ -41,11 +64,19 @@ type Model struct {
 	MaxHistoryItems int
 	context         *appContext.MainContext
 	styles          styles
+	validated       string
+	invalid         string
+	counting        bool
+	counted         string
+	count           int
+	cancelCount     context.CancelFunc
 }
 
 type styles struct {
 	promptStyle lipgloss.Style
 	textStyle   lipgloss.Style
+	errorStyle  lipgloss.Style
+	countStyle  lipgloss.Style
 }
 
 func (m *Model) IsFocused() bool {
@@ -68,15 +99,25 @@ func (k keymap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{k.ShortHelp()}
 }
 
-func New(context *appContext.MainContext) *Model {
-	styles := styles{
+func newStyles() styles {
+	return styles{
 		promptStyle: common.DefaultPalette.Get("revset title"),
 		textStyle:   common.DefaultPalette.Get("revset text"),
+		errorStyle:  common.DefaultPalette.Get("revset error"),
+		countStyle:  common.DefaultPalette.Get("revset count"),
 	}
+}
+
+func New(context *appContext.MainContext) *Model {
+	styles := newStyles()
 
 	revsetAliases := context.JJConfig.RevsetAliases
 	completionProvider := NewCompletionProvider(revsetAliases)
-	autoComplete := autocompletion.New(completionProvider, autocompletion.WithStylePrefix("revset"))
+	autoComplete := autocompletion.New(completionProvider,
+		autocompletion.WithStylePrefix("revset"),
+		autocompletion.WithMaxSuggestions(config.Current.Suggest.Revset.MaxSuggestions),
+		autocompletion.WithMaxWidth(config.Current.Suggest.Revset.MaxWidth),
+	)
 
 	autoComplete.SetValue(context.DefaultRevset)
 	autoComplete.Focus()
@@ -138,6 +179,9 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case intents.Intent:
 		return m.handleIntent(msg)
+	case common.ThemeChangedMsg:
+		m.styles = newStyles()
+		return nil
 	case tea.KeyMsg:
 		if !m.Editing {
 			return nil
@@ -184,9 +228,88 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		}
 	case EditRevSetMsg:
 		return m.handleIntent(intents.Edit{Clear: msg.Clear})
+	case revsetValidatedMsg:
+		m.validated, m.invalid = msg.revset, msg.err
+		return nil
+	case revsetCountedMsg:
+		if !m.Editing || msg.revset != m.autoComplete.Value() {
+			return nil
+		}
+		m.counting = false
+		m.counted, m.count = msg.revset, msg.count
+		return nil
 	}
 
-	return m.autoComplete.Update(msg)
+	value := m.autoComplete.Value()
+	cmd := m.autoComplete.Update(msg)
+	if m.Editing && m.autoComplete.Value() != value {
+		value = m.autoComplete.Value()
+		cmd = tea.Batch(cmd, common.Debounce(validationDebounceId, validationDebounceDuration, func() tea.Msg {
+			return revsetValidatedMsg{revset: value, err: m.validate(value)}
+		}), m.countMatches(value))
+	}
+	return cmd
+}
+
+// countMatches counts the revisions in the revset in the background, up to suggest.revset.count_limit,
+// and stops the count of the previous value if it is still running
+func (m *Model) countMatches(revset string) tea.Cmd {
+	m.stopCounting()
+	limit := config.Current.Suggest.Revset.CountLimit
+	if limit <= 0 || strings.TrimSpace(revset) == "" {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelCount = cancel
+	m.counting = true
+	return common.Debounce(countDebounceId, validationDebounceDuration, func() tea.Msg {
+		// one more than the limit is asked for to tell whether there are more
+		output, err := m.context.RunCommandImmediateContext(ctx, jj.CountRevset(revset, limit+1))
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		if err != nil {
+			return revsetCountedMsg{revset: revset, count: -1}
+		}
+		return revsetCountedMsg{revset: revset, count: len(output)}
+	})
+}
+
+func (m *Model) stopCounting() {
+	if m.cancelCount != nil {
+		m.cancelCount()
+		m.cancelCount = nil
+	}
+	m.counting = false
+	m.counted, m.count = "", 0
+}
+
+func (m *Model) countView() string {
+	if m.counting {
+		return m.styles.countStyle.PaddingLeft(1).Render("…")
+	}
+	if m.counted != m.autoComplete.Value() || m.count < 0 {
+		return ""
+	}
+	count := fmt.Sprintf("%d revisions", m.count)
+	if limit := config.Current.Suggest.Revset.CountLimit; m.count > limit {
+		count = fmt.Sprintf("%d+ revisions", limit)
+	} else if m.count == 1 {
+		count = "1 revision"
+	}
+	return m.styles.countStyle.PaddingLeft(1).Render(count)
+}
+
+// validate returns the reason jj rejects the revset, or an empty string when it is valid
+func (m *Model) validate(revset string) string {
+	if strings.TrimSpace(revset) == "" {
+		return ""
+	}
+	if _, err := m.context.RunCommandImmediate(jj.ValidateRevset(revset)); err != nil {
+		reason, _, _ := strings.Cut(strings.TrimSpace(err.Error()), "\n")
+		return strings.TrimPrefix(reason, "Error: ")
+	}
+	return ""
 }
 
 func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
@@ -194,6 +317,7 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 	case intents.Set:
 		m.Editing = false
 		m.autoComplete.Blur()
+		m.stopCounting()
 		value := intent.Value
 		if strings.TrimSpace(value) == "" {
 			value = m.context.DefaultRevset
@@ -202,6 +326,7 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 	case intents.Reset:
 		m.Editing = false
 		m.autoComplete.Blur()
+		m.stopCounting()
 		return tea.Batch(common.Close, common.UpdateRevSet(m.context.DefaultRevset))
 	case intents.Edit:
 		m.Editing = true
@@ -211,14 +336,13 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		}
 		m.historyActive = false
 		m.historyIndex = -1
-		return m.autoComplete.Init()
+		return tea.Batch(m.autoComplete.Init(), m.countMatches(m.autoComplete.Value()))
 	case intents.Cancel:
 		m.Editing = false
 		m.autoComplete.Blur()
+		m.stopCounting()
 		return nil
 	case intents.Apply:
-		m.Editing = false
-		m.autoComplete.Blur()
 		value := intent.Value
 		if value == "" {
 			value = m.autoComplete.Value()
@@ -226,6 +350,16 @@ func (m *Model) handleIntent(intent intents.Intent) tea.Cmd {
 		if strings.TrimSpace(value) == "" {
 			value = m.context.DefaultRevset
 		}
+		// invalid revsets are not applied so that the revisions stay on screen
+		if value != m.validated {
+			m.validated, m.invalid = value, m.validate(value)
+		}
+		if m.invalid != "" {
+			return nil
+		}
+		m.Editing = false
+		m.autoComplete.Blur()
+		m.stopCounting()
 		return tea.Batch(common.Close, common.UpdateRevSet(value))
 	}
 	return nil
@@ -236,6 +370,10 @@ func (m *Model) View() string {
 	w.WriteString(m.styles.promptStyle.PaddingRight(1).Render("revset:"))
 	if m.Editing {
 		w.WriteString(m.autoComplete.View())
+		w.WriteString(m.countView())
+		if m.invalid != "" && m.validated == m.autoComplete.Value() {
+			w.WriteString(m.styles.errorStyle.PaddingLeft(1).Render(m.invalid))
+		}
 	} else {
 		w.WriteString(m.styles.textStyle.Render(m.context.CurrentRevset))
 	}
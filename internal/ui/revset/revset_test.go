@@ -1,9 +1,13 @@
 package revset
 
 import (
+	"errors"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -40,3 +44,88 @@ func TestModel_View_DisplaysCurrentRevset(t *testing.T) {
 	model := New(ctx)
 	assert.Contains(t, model.View(), ctx.CurrentRevset)
 }
+
+func TestModel_Apply_RefusesInvalidRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ValidateRevset("bad((")).SetError(errors.New("Error: Failed to parse revset: Syntax error\nCaused by: ..."))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetWidth(100)
+	model.SetHeight(1)
+	model.Update(intents.Edit{Clear: true})
+	model.autoComplete.SetValue("bad((")
+
+	cmd := model.Update(test.Press(tea.KeyEnter)())
+	assert.Nil(t, cmd)
+	assert.True(t, model.Editing)
+	assert.Contains(t, model.View(), "Failed to parse revset: Syntax error")
+}
+
+func TestModel_Apply_ValidRevset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ValidateRevset("trunk()..@"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.Update(intents.Edit{Clear: true})
+	model.autoComplete.SetValue("trunk()..@")
+
+	cmd := model.Update(test.Press(tea.KeyEnter)())
+	assert.NotNil(t, cmd)
+	assert.False(t, model.Editing)
+}
+
+func TestModel_CountsMatchingRevisions(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.CountRevset("mine()", 1001)).SetOutput([]byte("xxx\n"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.DefaultRevset = "mine()"
+	model := New(ctx)
+	model.SetWidth(100)
+	model.SetHeight(1)
+
+	cmd := model.Update(intents.Edit{})
+	assert.Contains(t, model.View(), "…")
+
+	test.SimulateModel(model, cmd)
+	assert.Contains(t, model.View(), "3 revisions")
+}
+
+func TestModel_CountStopsAtLimit(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.Suggest.Revset.CountLimit = 2
+
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	model.SetWidth(100)
+	model.SetHeight(1)
+	model.Update(intents.Edit{Clear: true})
+	model.autoComplete.SetValue("all()")
+
+	model.Update(revsetCountedMsg{revset: "all()", count: 3})
+	assert.Contains(t, model.View(), "2+ revisions")
+}
+
+func TestModel_IgnoresStaleCount(t *testing.T) {
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	model.SetWidth(100)
+	model.SetHeight(1)
+	model.Update(intents.Edit{Clear: true})
+	model.autoComplete.SetValue("all()")
+
+	model.Update(revsetCountedMsg{revset: "al", count: 7})
+	assert.NotContains(t, model.View(), "7 revisions")
+}
+
+func TestModel_Paste_CollapsesLines(t *testing.T) {
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	model.Update(intents.Edit{Clear: true})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("trunk()..@\n  & mine()\n"), Paste: true})
+	assert.True(t, model.Editing)
+	assert.Equal(t, "trunk()..@ & mine()", model.autoComplete.Value())
+	assert.Empty(t, model.autoComplete.Suggestions)
+}
@@ -0,0 +1,98 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+)
+
+const (
+	// maxSegmentWidth keeps a long revset or command from taking over the status bar
+	maxSegmentWidth = 40
+	// minHelpWidth is the room kept for the key bindings before segments are dropped
+	minHelpWidth = 20
+)
+
+type segment struct {
+	text  string
+	style lipgloss.Style
+}
+
+// segmentsView renders the segments listed in ui.statusbar.segments followed by the key bindings.
+// Segments are dropped from the end when the terminal is too narrow to show all of them.
+func (m *Model) segmentsView() string {
+	var segments []segment
+	for _, name := range config.Current.UI.StatusBar.Segments {
+		text := m.segmentText(name)
+		if text == "" {
+			continue
+		}
+		text = ansi.Truncate(text, maxSegmentWidth, "…")
+		segments = append(segments, segment{text: text, style: common.DefaultPalette.Get("status segment " + name)})
+	}
+
+	separator := config.Current.UI.StatusBar.Separator
+	markers := m.dryRunView() + m.atOperationView() + m.signingView()
+	bar := markers + renderSegments(segments, separator, m.styles.text)
+	for len(segments) > 0 && lipgloss.Width(bar) > m.Width-minHelpWidth {
+		segments = segments[:len(segments)-1]
+		bar = markers + renderSegments(segments, separator, m.styles.text)
+	}
+
+	var rest string
+	if m.hint != "" {
+		rest = m.styles.text.Render(m.hint)
+	} else {
+		rest = m.helpView(m.keyMap)
+	}
+	ret := lipgloss.JoinHorizontal(lipgloss.Left, bar, m.styles.text.Render(" "), rest)
+	return lipgloss.Place(m.Width, lipgloss.Height(ret), 0, 0, ret, lipgloss.WithWhitespaceBackground(m.styles.text.GetBackground()))
+}
+
+func (m *Model) segmentText(name string) string {
+	switch name {
+	case "mode":
+		return m.mode
+	case "revset":
+		return m.context.CurrentRevset
+	case "operation":
+		command := strings.ReplaceAll(m.command, "\n", "⏎")
+		switch m.status {
+		case commandRunning:
+			return m.spinner.View() + command
+		case commandCompleted:
+			return "✓ " + command
+		case commandFailed:
+			return "✗ " + command
+		}
+	case "remote":
+		return m.tracking.String()
+	case "selection":
+		if count := len(m.context.CheckedItems); count > 0 {
+			return fmt.Sprintf("%d selected", count)
+		}
+	}
+	return ""
+}
+
+// renderSegments joins the segments, each separator takes the background of the segment before it
+// as its foreground and the background of the segment after it so that they blend like a powerline
+func renderSegments(segments []segment, separator string, base lipgloss.Style) string {
+	var sb strings.Builder
+	for i, s := range segments {
+		sb.WriteString(s.style.Padding(0, 1).Render(s.text))
+		if separator == "" {
+			continue
+		}
+		next := base
+		if i+1 < len(segments) {
+			next = segments[i+1].style
+		}
+		sb.WriteString(lipgloss.NewStyle().Foreground(s.style.GetBackground()).Background(next.GetBackground()).Render(separator))
+	}
+	return sb.String()
+}
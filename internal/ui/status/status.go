@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -43,12 +44,20 @@ type Model struct {
 	status     commandStatus
 	running    bool
 	mode       string
+	hint       string
 	editStatus editStatus
 	history    map[string][]string
 	fuzzy      fuzzy_search.Model
+	summary    string
+	tracking   *tracking
 	styles     styles
 }
 
+type updateSummaryMsg struct {
+	commitId string
+	summary  string
+}
+
 type styles struct {
 	shortcut lipgloss.Style
 	dimmed   lipgloss.Style
@@ -88,12 +97,28 @@ func (m *Model) Init() tea.Cmd {
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	km := config.Current.GetKeyMap()
 	switch msg := msg.(type) {
+	case common.ThemeChangedMsg:
+		m.styles = newStyles()
+		return nil
 	case clearMsg:
 		if m.command == string(msg) {
 			m.command = ""
 			m.status = none
 		}
 		return nil
+	case common.SelectionChangedMsg:
+		return m.loadSummary()
+	case common.RefreshMsg:
+		// an auto refresh only reloads the revisions with a RefreshMsg once the repository has changed
+		return m.loadTracking()
+	case updateTrackingMsg:
+		m.tracking = msg.tracking
+		return nil
+	case updateSummaryMsg:
+		if selectedCommitId(m.context.SelectedItem) == msg.commitId {
+			m.summary = msg.summary
+		}
+		return nil
 	case common.CommandRunningMsg:
 		m.command = string(msg)
 		m.status = commandRunning
@@ -110,6 +135,9 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		})
 	case common.FileSearchMsg:
 		m.mode = "rev file"
+		if msg.Workspace {
+			m.mode = "workspace file"
+		}
 		m.input.Prompt = "> "
 		m.loadEditingSuggestions()
 		m.fuzzy, m.editStatus = fuzzy_files.NewModel(msg)
@@ -201,6 +229,50 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	}
 }
 
+// loadSummary fetches the one line summary of the selected revision when
+// ui.show_selection_summary is enabled
+func (m *Model) loadSummary() tea.Cmd {
+	if !config.Current.UI.ShowSelectionSummary {
+		return nil
+	}
+	commitId := selectedCommitId(m.context.SelectedItem)
+	if commitId == "" {
+		m.summary = ""
+		return nil
+	}
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.SelectionSummary(commitId, config.Current.Revisions.GetSummaryTemplate()))
+		if err != nil {
+			return updateSummaryMsg{commitId: commitId}
+		}
+		return updateSummaryMsg{commitId: commitId, summary: strings.TrimSpace(string(output))}
+	}
+}
+
+func selectedCommitId(item context.SelectedItem) string {
+	switch item := item.(type) {
+	case context.SelectedRevision:
+		return item.CommitId
+	case context.SelectedFile:
+		return item.CommitId
+	}
+	return ""
+}
+
+// summaryView renders the summary on a single line, cutting it at the width
+// of the terminal instead of wrapping
+func (m *Model) summaryView() string {
+	summary := strings.ReplaceAll(m.summary, "\n", " ")
+	if lipgloss.Width(summary) > m.Width {
+		runes := []rune(summary)
+		for len(runes) > 0 && lipgloss.Width(string(runes)+"…") > m.Width {
+			runes = runes[:len(runes)-1]
+		}
+		summary = string(runes) + "…"
+	}
+	return m.styles.dimmed.Width(m.Width).MaxWidth(m.Width).Render(summary)
+}
+
 func (m *Model) saveEditingSuggestions() {
 	input := m.input.Value()
 	if len(strings.TrimSpace(input)) == 0 {
@@ -218,6 +290,9 @@ func (m *Model) loadEditingSuggestions() {
 }
 
 func (m *Model) View() string {
+	if len(config.Current.UI.StatusBar.Segments) > 0 && !m.IsFocused() {
+		return m.withSummary(m.segmentsView())
+	}
 	commandStatusMark := m.styles.text.Render(" ")
 	if m.status == commandRunning {
 		commandStatusMark = m.styles.text.Render(m.spinner.View())
@@ -225,6 +300,9 @@ func (m *Model) View() string {
 		commandStatusMark = m.styles.error.Render("✗ ")
 	} else if m.status == commandCompleted {
 		commandStatusMark = m.styles.success.Render("✓ ")
+	} else if m.hint != "" {
+		commandStatusMark = m.styles.text.Render(m.hint)
+		commandStatusMark = lipgloss.PlaceHorizontal(m.Width, 0, commandStatusMark, lipgloss.WithWhitespaceBackground(m.styles.text.GetBackground()))
 	} else {
 		commandStatusMark = m.helpView(m.keyMap)
 		commandStatusMark = lipgloss.PlaceHorizontal(m.Width, 0, commandStatusMark, lipgloss.WithWhitespaceBackground(m.styles.text.GetBackground()))
@@ -242,15 +320,52 @@ func (m *Model) View() string {
 		ret = lipgloss.JoinHorizontal(0, m.input.View(), editHelp)
 	}
 	mode := m.styles.title.Width(modeWith).Render("", m.mode)
-	ret = lipgloss.JoinHorizontal(lipgloss.Left, mode, m.styles.text.Render(" "), commandStatusMark, ret)
+	ret = lipgloss.JoinHorizontal(lipgloss.Left, m.dryRunView(), m.atOperationView(), mode, m.signingView(), m.styles.text.Render(" "), commandStatusMark, ret)
 	height := lipgloss.Height(ret)
-	return lipgloss.Place(m.Width, height, 0, 0, ret, lipgloss.WithWhitespaceBackground(m.styles.text.GetBackground()))
+	ret = lipgloss.Place(m.Width, height, 0, 0, ret, lipgloss.WithWhitespaceBackground(m.styles.text.GetBackground()))
+	return m.withSummary(ret)
+}
+
+// atOperationView marks the status bar while the repository is viewed at a past operation
+func (m *Model) atOperationView() string {
+	if m.context.AtOperation == "" {
+		return ""
+	}
+	return m.styles.error.Reverse(true).Render(" at operation " + m.context.AtOperation + " ")
+}
+
+// dryRunView marks the status bar while the commands changing the repository are only shown
+func (m *Model) dryRunView() string {
+	if !m.context.DryRun {
+		return ""
+	}
+	return m.styles.error.Reverse(true).Render(" dry run ")
+}
+
+// signingView reminds that the commits created or rewritten from jjui are signed
+func (m *Model) signingView() string {
+	if !m.context.SignCommits {
+		return ""
+	}
+	return m.styles.success.Render(" signing")
+}
+
+func (m *Model) withSummary(view string) string {
+	if config.Current.UI.ShowSelectionSummary && m.summary != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, m.summaryView(), view)
+	}
+	return view
 }
 
 func (m *Model) SetHelp(keyMap help.KeyMap) {
 	m.keyMap = keyMap
 }
 
+// SetHint shows the hint in place of the key bindings, an empty hint shows them again
+func (m *Model) SetHint(hint string) {
+	m.hint = hint
+}
+
 func (m *Model) SetMode(mode string) {
 	if !m.IsFocused() {
 		m.mode = mode
@@ -271,8 +386,8 @@ func (m *Model) helpView(keyMap help.KeyMap) string {
 	return help
 }
 
-func New(context *context.MainContext) *Model {
-	styles := styles{
+func newStyles() styles {
+	return styles{
 		shortcut: common.DefaultPalette.Get("status shortcut"),
 		dimmed:   common.DefaultPalette.Get("status dimmed"),
 		text:     common.DefaultPalette.Get("status text"),
@@ -280,6 +395,10 @@ func New(context *context.MainContext) *Model {
 		success:  common.DefaultPalette.Get("status success"),
 		error:    common.DefaultPalette.Get("status error"),
 	}
+}
+
+func New(context *context.MainContext) *Model {
+	styles := newStyles()
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 
@@ -2,11 +2,14 @@ package status
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,3 +72,171 @@ func TestStatus_Update_ExecProcessCompletedMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestStatus_SelectionSummary(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+	config.Current.UI.ShowSelectionSummary = true
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.SelectionSummary("123", "")).SetOutput([]byte("abcdefgh Jane Doe a rather long description of the change that does not fit"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc", CommitId: "123"}
+	model := New(ctx)
+	model.SetWidth(50)
+	model.SetHint("hint")
+
+	test.SimulateModel(model, common.SelectionChanged)
+	lines := strings.Split(model.View(), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "abcdefgh Jane Doe a rather long description of th…", strings.TrimRight(lines[0], " "))
+}
+
+func TestStatus_SelectionSummaryUsesSummaryTemplate(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+	config.Current.UI.ShowSelectionSummary = true
+	config.Current.Revisions.SummaryTemplate = "description.first_line()"
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.SelectionSummary("123", "description.first_line()")).SetOutput([]byte("fix the parser"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc", CommitId: "123"}
+	model := New(ctx)
+	model.SetWidth(50)
+	model.SetHint("hint")
+
+	test.SimulateModel(model, common.SelectionChanged)
+	assert.Equal(t, "fix the parser", strings.TrimRight(strings.Split(model.View(), "\n")[0], " "))
+}
+
+func TestStatus_Segments(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+	config.Current.UI.StatusBar = config.StatusConfig{Segments: []string{"mode", "revset", "selection"}, Separator: ">"}
+
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	ctx.CurrentRevset = "trunk()..@"
+	ctx.AddCheckedItem(context.SelectedRevision{ChangeId: "abc", CommitId: "123"})
+	model := New(ctx)
+	model.SetMode("normal")
+	model.SetHint("hint")
+
+	model.SetWidth(80)
+	assert.Equal(t, "normal > trunk()..@ > 1 selected > hint", strings.TrimSpace(test.Stripped(model.View())))
+
+	model.SetWidth(45)
+	assert.Equal(t, "normal > trunk()..@ > hint", strings.TrimSpace(test.Stripped(model.View())))
+}
+
+func TestStatus_AtOperationIsMarked(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := New(ctx)
+	model.SetMode("normal")
+	model.SetHint("hint")
+	model.SetWidth(80)
+	assert.NotContains(t, test.Stripped(model.View()), "at operation")
+
+	ctx.AtOperation = "abc123"
+	view := test.Stripped(model.View())
+	assert.True(t, strings.HasPrefix(view, "at operation abc123"), view)
+	assert.Contains(t, view, "normal")
+}
+
+func TestStatus_DryRunIsMarked(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := New(ctx)
+	model.SetMode("normal")
+	model.SetHint("hint")
+	model.SetWidth(80)
+	assert.NotContains(t, test.Stripped(model.View()), "dry run")
+
+	ctx.DryRun = true
+	view := test.Stripped(model.View())
+	assert.True(t, strings.HasPrefix(view, "dry run"), view)
+	assert.Contains(t, view, "normal")
+}
+
+func TestStatus_SigningIsMarked(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := New(ctx)
+	model.SetMode("normal")
+	model.SetHint("hint")
+	model.SetWidth(80)
+	assert.NotContains(t, test.Stripped(model.View()), "signing")
+
+	ctx.SignCommits = true
+	assert.Contains(t, test.Stripped(model.View()), "signing")
+}
+
+func TestStatus_ShowsAheadBehindTrackedRemote(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.StatusBar.Segments = []string{"mode", "remote"}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.NearestBookmarks()).SetOutput([]byte(
+		"feature;.;false;false;false;1\nfeature;fork;true;false;false;3\nfeature;origin;true;false;false;4\n"))
+	commandRunner.Expect(jj.AheadBehind("feature", "origin")).SetOutput([]byte("+\n+\n-\n"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetMode("normal")
+	model.SetHint("hint")
+	model.SetWidth(80)
+
+	test.SimulateModel(model, common.Refresh)
+	assert.Equal(t, 1, strings.Count(test.Stripped(model.View()), "feature@origin ↑2 ↓1"))
+}
+
+func TestStatus_OmitsAheadBehindWithoutTrackedRemote(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.StatusBar.Segments = []string{"mode", "remote"}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.NearestBookmarks()).SetOutput([]byte("feature;.;false;false;false;1\nfeature;origin;false;false;false;4\n"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetMode("normal")
+	model.SetHint("hint")
+	model.SetWidth(80)
+
+	test.SimulateModel(model, common.Refresh)
+	assert.NotContains(t, test.Stripped(model.View()), "↑")
+}
+
+func TestStatus_AheadBehindIsOnlyCountedWhenShown(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	assert.Nil(t, model.Update(common.RefreshMsg{}))
+}
+
+func TestStatus_LongSegmentIsTruncated(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+	config.Current.UI.StatusBar = config.StatusConfig{Segments: []string{"revset"}, Separator: ">"}
+
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	ctx.CurrentRevset = strings.Repeat("a", 39) + "界"
+	model := New(ctx)
+	model.SetHint("hint")
+
+	model.SetWidth(120)
+	assert.Equal(t, strings.Repeat("a", 39)+"… > hint", strings.TrimSpace(test.Stripped(model.View())))
+}
@@ -0,0 +1,95 @@
+package status
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+)
+
+// tracking is how far the working copy has moved from the remote bookmark that the closest
+// bookmark below it tracks
+type tracking struct {
+	bookmark string
+	remote   string
+	ahead    int
+	behind   int
+}
+
+type updateTrackingMsg struct {
+	tracking *tracking
+}
+
+func (t *tracking) String() string {
+	if t == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s@%s ↑%d ↓%d", t.bookmark, t.remote, t.ahead, t.behind)
+}
+
+// trackingEnabled reports whether the remote segment is shown, the revisions are only counted then
+func trackingEnabled() bool {
+	return slices.Contains(config.Current.UI.StatusBar.Segments, "remote")
+}
+
+// loadTracking counts the revisions between the working copy and the tracked remote bookmark,
+// the indicator is hidden when none of the closest bookmarks tracks a remote. The nearest
+// bookmarks are listed with their remote bookmarks, so only the counting takes another jj process.
+func (m *Model) loadTracking() tea.Cmd {
+	if !trackingEnabled() {
+		return nil
+	}
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.NearestBookmarks())
+		if err != nil {
+			return updateTrackingMsg{}
+		}
+		bookmarks := jj.ParseBookmarkListOutput(string(output))
+		var nearest []string
+		for _, b := range bookmarks {
+			if b.Local != nil {
+				nearest = append(nearest, b.Name)
+			}
+		}
+		t := trackedRemote(bookmarks, nearest, config.GetGitDefaultRemote(config.Current))
+		if t == nil {
+			return updateTrackingMsg{}
+		}
+
+		output, err = m.context.RunCommandImmediate(jj.AheadBehind(t.bookmark, t.remote))
+		if err != nil {
+			return updateTrackingMsg{}
+		}
+		t.ahead = strings.Count(string(output), "+")
+		t.behind = strings.Count(string(output), "-")
+		return updateTrackingMsg{tracking: t}
+	}
+}
+
+// trackedRemote picks the first of the nearest bookmarks that tracks a remote, the default remote
+// is preferred when the bookmark tracks more than one
+func trackedRemote(bookmarks []jj.Bookmark, nearest []string, defaultRemote string) *tracking {
+	for _, name := range nearest {
+		for _, b := range bookmarks {
+			if b.Name != name {
+				continue
+			}
+			var found *tracking
+			for _, remote := range b.Remotes {
+				if !remote.Tracked {
+					continue
+				}
+				if found == nil || remote.Remote == defaultRemote {
+					found = &tracking{bookmark: name, remote: remote.Remote}
+				}
+			}
+			if found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
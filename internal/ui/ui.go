@@ -1,12 +1,16 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/x/cellbuf"
+	"github.com/idursun/jjui/internal/clipboard"
 	"github.com/idursun/jjui/internal/scripting"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
@@ -33,6 +37,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/oplog"
 	"github.com/idursun/jjui/internal/ui/preview"
 	"github.com/idursun/jjui/internal/ui/redo"
+	"github.com/idursun/jjui/internal/ui/reorder"
 	"github.com/idursun/jjui/internal/ui/revisions"
 	"github.com/idursun/jjui/internal/ui/revset"
 	"github.com/idursun/jjui/internal/ui/status"
@@ -41,6 +46,12 @@ import (
 
 var _ common.Model = (*Model)(nil)
 
+// writeClipboard and readClipboard are swapped out in tests
+var (
+	writeClipboard = clipboard.Copy
+	readClipboard  = clipboard.Paste
+)
+
 type SizableModel interface {
 	common.Model
 	common.IViewNode
@@ -48,28 +59,59 @@ type SizableModel interface {
 
 type Model struct {
 	*common.ViewNode
-	revisions       *revisions.Model
-	oplog           *oplog.Model
-	revsetModel     *revset.Model
-	previewModel    *preview.Model
-	diff            *diff.Model
-	leader          *leader.Model
-	flash           *flash.Model
-	state           common.State
-	status          *status.Model
-	password        *password.Model
-	context         *context.MainContext
-	scriptRunner    *scripting.Runner
-	keyMap          config.KeyMappings[key.Binding]
-	stacked         SizableModel
-	dragTarget      common.Draggable
-	sequenceOverlay *customcommands.SequenceOverlay
+	revisions        *revisions.Model
+	oplog            *oplog.Model
+	revsetModel      *revset.Model
+	previewModel     *preview.Model
+	diff             *diff.Model
+	leader           *leader.Model
+	flash            *flash.Model
+	state            common.State
+	status           *status.Model
+	password         *password.Model
+	context          *context.MainContext
+	scriptRunner     *scripting.Runner
+	refreshHooks     []*scripting.Runner
+	timerRunners     []*scripting.Runner // finished scripts running their pending timers in the background
+	gitPending       bool                // the g of the git mode was pressed, it makes gg with a second g
+	gitPendingTag    int
+	keyMap           config.KeyMappings[key.Binding]
+	stacked          SizableModel
+	dragTarget       common.Draggable
+	sequenceOverlay  *customcommands.SequenceOverlay
+	choosingDiffTool bool
+	savedRevsets     map[string]string
+	repositories     []string
+	undoingMany      bool
+	// workspaces maps the names offered by the workspace switcher to their roots
+	workspaces map[string]string
+	// lastCommand is the custom command repeated by keys.repeat_command
+	lastCommand context.CustomCommand
+	// hiddenRevset is the revset applied by toggle_hidden, revsetBeforeHidden is restored when it is toggled off
+	hiddenRevset       string
+	revsetBeforeHidden string
 }
 
 type triggerAutoRefreshMsg struct{}
 
+// workspacesLoadedMsg carries the workspaces of the repository, choose shows the switcher
+type workspacesLoadedMsg struct {
+	workspaces []context.Workspace
+	err        error
+	choose     bool
+}
+
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(tea.SetWindowTitle(fmt.Sprintf("jjui - %s", m.context.Location)), m.revisions.Init(), m.scheduleAutoRefresh())
+	return tea.Batch(tea.SetWindowTitle(m.windowTitle()), m.revisions.Init(), m.scheduleAutoRefresh(), m.loadWorkspaces(false))
+}
+
+// closeStacked closes the stacked window, the bookmarks checked in the bookmark mode are
+// unchecked when it is left
+func (m *Model) closeStacked() {
+	if _, ok := m.stacked.(*bookmarks.Model); ok {
+		m.context.ClearCheckedItems(reflect.TypeFor[context.SelectedBookmark]())
+	}
+	m.stacked = nil
 }
 
 func (m *Model) handleFocusInputMessage(msg tea.Msg) (tea.Cmd, bool) {
@@ -83,7 +125,7 @@ func (m *Model) handleFocusInputMessage(msg tea.Msg) (tea.Cmd, bool) {
 			return nil, true
 		}
 		if m.stacked != nil {
-			m.stacked = nil
+			m.closeStacked()
 			return nil, true
 		}
 		if m.oplog != nil {
@@ -107,6 +149,10 @@ func (m *Model) handleFocusInputMessage(msg tea.Msg) (tea.Cmd, bool) {
 			return m.diff.Update(msg), true
 		}
 
+		if m.previewModel.Visible() && m.previewModel.IsSearching() {
+			return m.previewModel.Update(msg), true
+		}
+
 		if m.revsetModel.Editing {
 			m.state = common.Loading
 			return m.revsetModel.Update(msg), true
@@ -169,6 +215,17 @@ func (m *Model) shouldStartSequenceOverlay(msg tea.KeyMsg) bool {
 }
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	// the theme changes under the leader menu too, so it is handled before the focused view
+	if background, ok := common.ParseBackgroundReport(msg); ok {
+		return m.applyBackground(background.Dark)
+	}
+	switch msg := msg.(type) {
+	case common.BackgroundChangedMsg:
+		return m.applyBackground(msg.Dark)
+	case common.ThemeChangedMsg:
+		return m.broadcastThemeChanged(msg)
+	}
+
 	if cmd, handled := m.handleFocusInputMessage(msg); handled {
 		return cmd
 	}
@@ -185,7 +242,15 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		return tea.Batch(common.RefreshAndKeepSelections, tea.EnableMouseCellMotion)
 	case tea.MouseMsg:
 		if m.stacked != nil {
-			// for now, stacked windows don't respond to mouse events
+			// stacked windows only respond to the mouse wheel, clicks and drags are ignored
+			if scrollable, ok := m.stacked.(common.Scrollable); ok && msg.Action == tea.MouseActionPress {
+				switch msg.Button {
+				case tea.MouseButtonWheelUp:
+					return scrollable.Scroll(-3)
+				case tea.MouseButtonWheelDown:
+					return scrollable.Scroll(3)
+				}
+			}
 			return nil
 		}
 		if m.dragTarget != nil && m.dragTarget.IsDragging() {
@@ -222,17 +287,39 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			// process it further.
 			return nil
 		}
+		if m.gitPending {
+			m.gitPending = false
+			if msg.String() == "g" {
+				// gg jumps to the top as it does where g isn't bound to a mode
+				return m.revisions.Update(intents.Navigate{Target: intents.TargetTop})
+			}
+			if key.Matches(msg, m.keyMap.Cancel) {
+				return nil
+			}
+			cmd := m.openGit()
+			return tea.Batch(cmd, m.stacked.Update(msg))
+		}
 
 		switch {
+		case m.context.ReadOnly && m.isMutatingKey(msg):
+			return intents.Invoke(intents.AddMessage{Text: "read-only mode"})
+		case m.context.AtOperation != "" && key.Matches(msg, m.keyMap.OpLog.ViewLatest):
+			m.context.SetAtOperation("")
+			m.oplog = nil
+			return tea.Batch(common.Refresh, common.SelectionChanged)
+		case m.context.AtOperation != "" && m.isMutatingKey(msg):
+			return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("viewing operation %s, press %s to go back to the latest operation", m.context.AtOperation, m.keyMap.OpLog.ViewLatest.Help().Key)})
 		case key.Matches(msg, m.keyMap.Cancel) && m.state == common.Error:
 			m.state = common.Ready
 			return tea.Batch(cmds...)
 		case key.Matches(msg, m.keyMap.Cancel) && m.stacked != nil:
-			m.stacked = nil
+			m.closeStacked()
 			return tea.Batch(cmds...)
 		case key.Matches(msg, m.keyMap.Cancel) && m.flash.Any():
 			m.flash.DeleteOldest()
 			return tea.Batch(cmds...)
+		case key.Matches(msg, m.keyMap.ExpandMessage) && m.flash.Expandable():
+			return m.flash.Expand()
 		case key.Matches(msg, m.keyMap.Quit) && m.isSafeToQuit():
 			return tea.Quit
 		case key.Matches(msg, m.keyMap.OpLog.Mode):
@@ -241,17 +328,38 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			return m.oplog.Init()
 		case key.Matches(msg, m.keyMap.Revset) && m.revisions.InNormalMode():
 			return m.revsetModel.Update(intents.Edit{Clear: m.state != common.Error})
-		case key.Matches(msg, m.keyMap.Git.Mode) && m.revisions.InNormalMode():
-			model := git.NewModel(m.context, m.revisions.SelectedRevisions())
-			model.Parent = m.ViewNode
-			m.stacked = model
-			return m.stacked.Init()
+		case key.Matches(msg, m.keyMap.SavedRevsets) && m.revisions.InNormalMode():
+			return m.chooseSavedRevset()
+		case key.Matches(msg, m.keyMap.CopyRevset) && m.revisions.InNormalMode():
+			return m.copyRevset()
+		case key.Matches(msg, m.keyMap.PasteRevset) && m.revisions.InNormalMode():
+			return m.pasteRevset()
+		case key.Matches(msg, m.keyMap.Repositories) && m.revisions.InNormalMode():
+			return m.chooseRecentRepository()
+		case key.Matches(msg, m.keyMap.Workspaces) && m.revisions.InNormalMode():
+			return m.loadWorkspaces(true)
+		case key.Matches(msg, m.keyMap.Git.Mode) && m.oplog == nil && m.revisions.InNormalMode():
+			if msg.String() == "g" {
+				// wait for the next key, a second g makes gg
+				m.gitPending = true
+				m.gitPendingTag++
+				tag := m.gitPendingTag
+				return tea.Tick(gitPendingTimeout, func(time.Time) tea.Msg {
+					return gitPendingTimeoutMsg{tag: tag}
+				})
+			}
+			return m.openGit()
+		case key.Matches(msg, m.keyMap.Git.FetchAll) && m.oplog == nil && m.revisions.InNormalMode():
+			return git.FetchAll(m.context)
 		case key.Matches(msg, m.keyMap.Undo) && m.revisions.InNormalMode():
 			model := undo.NewModel(m.context)
 			model.Parent = m.ViewNode
 			m.stacked = model
 			cmds = append(cmds, m.stacked.Init())
 			return tea.Batch(cmds...)
+		case key.Matches(msg, m.keyMap.UndoMany) && m.revisions.InNormalMode():
+			m.undoingMany = true
+			return input.ShowWithTitle("Undo operations", "number of operations: ")
 		case key.Matches(msg, m.keyMap.Redo) && m.revisions.InNormalMode():
 			model := redo.NewModel(m.context)
 			model.Parent = m.ViewNode
@@ -265,6 +373,23 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.stacked = model
 			cmds = append(cmds, m.stacked.Init())
 			return tea.Batch(cmds...)
+		case key.Matches(msg, m.keyMap.DiffTool) && m.canChooseDiffTool():
+			m.choosingDiffTool = true
+			return func() tea.Msg {
+				return common.ShowChooseMsg{Options: config.Current.Diff.ToolNames(), Title: "Diff with"}
+			}
+		case key.Matches(msg, m.keyMap.ToggleTimestamps) && (m.oplog != nil || m.revisions.InNormalMode()):
+			return m.toggleTimestamps()
+		case key.Matches(msg, m.keyMap.ToggleIdType) && m.oplog == nil && m.revisions.InNormalMode():
+			return m.toggleIdType()
+		case key.Matches(msg, m.keyMap.ToggleHidden) && m.oplog == nil && m.revisions.InNormalMode():
+			return m.toggleHidden()
+		case key.Matches(msg, m.keyMap.ToggleGraph) && m.oplog == nil && m.revisions.InNormalMode():
+			return m.toggleGraph()
+		case key.Matches(msg, m.keyMap.ToggleDryRun) && (m.oplog != nil || m.revisions.InNormalMode()):
+			return m.toggleDryRun()
+		case key.Matches(msg, m.keyMap.ToggleSigning) && (m.oplog != nil || m.revisions.InNormalMode()):
+			return m.toggleSigning()
 		case key.Matches(msg, m.keyMap.Help):
 			cmds = append(cmds, common.ToggleHelp)
 			return tea.Batch(cmds...)
@@ -291,6 +416,8 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.stacked = model
 			cmds = append(cmds, m.stacked.Init())
 			return tea.Batch(cmds...)
+		case key.Matches(msg, m.keyMap.RepeatCommand):
+			return m.repeatCommand()
 		case key.Matches(msg, m.keyMap.Leader):
 			m.leader = leader.New(m.context)
 			cmds = append(cmds, leader.InitCmd)
@@ -303,6 +430,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			}
 			out, _ := m.context.RunCommandImmediate(jj.FilesInRevision(rev))
 			return common.FileSearch(m.context.CurrentRevset, m.previewModel.Visible(), rev, out)
+		case key.Matches(msg, m.keyMap.FileSearch.Workspace) && m.oplog == nil:
+			workingCopy := &jj.Commit{ChangeId: "@", CommitId: "@"}
+			out, err := m.context.RunCommandImmediate(jj.FilesInRevision(workingCopy))
+			if err != nil {
+				return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+			}
+			return common.WorkspaceFileSearch(m.context.CurrentRevset, m.previewModel.Visible(), workingCopy, out)
 		case key.Matches(msg, m.keyMap.QuickSearch) && m.oplog != nil:
 			// HACK: prevents quick search from activating in op log view
 			return nil
@@ -314,28 +448,46 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 					continue
 				}
 				if key.Matches(msg, command.Binding()) {
-					return command.Prepare(m.context)
+					return customcommands.Run(m.context, command)
 				}
 			}
 		}
+	case customcommands.RanMsg:
+		m.lastCommand = msg.Command
+		return nil
 	case common.ExecMsg:
 		return exec_process.ExecLine(m.context, msg)
 	case common.ExecProcessCompletedMsg:
-		cmds = append(cmds, common.Refresh)
+		postCommand, _ := config.Current.PostCommand.GetExec()
+		if msg.Msg.Mode == common.ExecShell {
+			postCommand, _ = config.Current.PostCommand.GetShell()
+		}
+		cmds = append(cmds, common.AfterCommand(postCommand))
 	case common.ToggleHelpMsg:
 		if m.stacked == nil {
 			h := helppage.New(m.context)
 			h.Parent = m.ViewNode
 			m.stacked = h
 		} else {
-			m.stacked = nil
+			m.closeStacked()
 		}
 		return nil
 	case common.ShowDiffMsg:
 		m.diff = diff.New(string(msg))
+		m.diff.HighlightWords()
+		m.diff.SetLocation(m.context.Location)
+		m.diff.SetSelectedItem(m.context.SelectedItem)
+		return m.diff.Init()
+	case common.ShowOutputMsg:
+		m.diff = diff.New(msg.Output)
+		m.diff.SetLocation(m.context.Location)
+		m.diff.SetTitle(msg.Title)
 		return m.diff.Init()
 	case common.UpdateRevisionsSuccessMsg:
 		m.state = common.Ready
+		for _, hook := range m.refreshHooks {
+			cmds = append(cmds, hook.OnRefresh(m.context.CurrentRevset))
+		}
 	case customcommands.SequenceTimeoutMsg:
 		if m.sequenceOverlay == nil {
 			return nil
@@ -345,11 +497,40 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.sequenceOverlay = nil
 		}
 		return res.Cmd
+	case workspacesLoadedMsg:
+		return m.workspacesLoaded(msg)
+	case gitPendingTimeoutMsg:
+		if !m.gitPending || msg.tag != m.gitPendingTag {
+			return nil
+		}
+		m.gitPending = false
+		return m.openGit()
+	case common.CommandCompletedMsg:
+		// the flash message still reports the failure
+		cmds = append(cmds, m.showFailedOutput(msg.Err))
+	case common.UpdateRevisionsFailedMsg:
+		cmds = append(cmds, m.showFailedOutput(msg.Err))
+	case intents.AddMessage:
+		// the commands run with RunCommandImmediate report their failures as flash messages
+		cmds = append(cmds, m.showFailedOutput(msg.Err))
+	case common.CommandResultMsg:
+		return msg.Handle(msg.Result)
 	case triggerAutoRefreshMsg:
+		if m.isAutoRefreshPaused() {
+			// skip this tick so that the view doesn't reload under the cursor
+			return m.scheduleAutoRefresh()
+		}
 		return tea.Batch(m.scheduleAutoRefresh(), func() tea.Msg {
 			return common.AutoRefreshMsg{}
 		})
+	case common.OpenFileDetailsMsg:
+		m.revisions.OpenDetailsAfterRefresh(string(msg))
+		return common.UpdateRevSet(fmt.Sprintf("files(%s)", jj.EscapeFileName(string(msg))))
 	case common.UpdateRevSetMsg:
+		if m.hiddenRevset != "" && string(msg) != m.hiddenRevset {
+			// another revset was applied while hidden revisions were shown
+			m.hiddenRevset, m.revsetBeforeHidden = "", ""
+		}
 		m.context.CurrentRevset = string(msg)
 		if m.context.CurrentRevset == "" {
 			m.context.CurrentRevset = m.context.DefaultRevset
@@ -363,7 +544,11 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 				return common.CommandCompletedMsg{Err: err}
 			}
 		}
+		m.setAsideScriptRunner()
 		m.scriptRunner = runner
+		if runner.Done() {
+			m.keepRefreshHooks(runner)
+		}
 		if cmd == nil && (runner == nil || runner.Done()) {
 			m.scriptRunner = nil
 		}
@@ -373,19 +558,78 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		model.Parent = m.ViewNode
 		m.stacked = model
 		return m.stacked.Init()
-	case choose.SelectedMsg, choose.CancelledMsg:
-		m.stacked = nil
+	case choose.SelectedMsg:
+		if _, ok := m.stacked.(*choose.Model); ok {
+			m.stacked = nil
+		}
+		if m.choosingDiffTool {
+			m.choosingDiffTool = false
+			return m.context.RunDiffTool(msg.Value)
+		}
+		if m.savedRevsets != nil {
+			revset, ok := m.savedRevsets[msg.Value]
+			m.savedRevsets = nil
+			if ok {
+				return common.UpdateRevSet(revset)
+			}
+		}
+		if m.repositories != nil {
+			m.repositories = nil
+			return m.switchRepository(msg.Value)
+		}
+		if m.workspaces != nil {
+			root, ok := m.workspaces[msg.Value]
+			m.workspaces = nil
+			if ok {
+				return m.switchWorkspace(msg.Value, root)
+			}
+		}
+	case choose.CancelledMsg:
+		if _, ok := m.stacked.(*choose.Model); ok {
+			m.stacked = nil
+		}
+		m.choosingDiffTool = false
+		m.savedRevsets = nil
+		m.repositories = nil
+		m.workspaces = nil
+	case common.ShowReorderMsg:
+		model := reorder.New(msg.Title, msg.Revisions)
+		model.Parent = m.ViewNode
+		m.stacked = model
+		return m.stacked.Init()
+	case reorder.AppliedMsg, reorder.CancelledMsg:
+		if _, ok := m.stacked.(*reorder.Model); ok {
+			m.stacked = nil
+		}
 	case common.ShowInputMsg:
 		model := input.NewWithTitle(msg.Title, msg.Prompt)
 		model.Parent = m.ViewNode
 		m.stacked = model
 		return m.stacked.Init()
 	case input.SelectedMsg, input.CancelledMsg:
-		m.stacked = nil
+		if _, ok := m.stacked.(*input.Model); ok {
+			m.stacked = nil
+		}
+		if m.diff != nil {
+			// the diff view prompts for the export file name
+			return m.diff.Update(msg)
+		}
+		if m.undoingMany {
+			m.undoingMany = false
+			if selected, ok := msg.(input.SelectedMsg); ok {
+				return undo.Operations(m.context, selected.Value)
+			}
+		}
 	case common.ShowPreview:
 		m.previewModel.SetVisible(bool(msg))
 		cmds = append(cmds, common.SelectionChanged)
 		return tea.Batch(cmds...)
+	case common.ShowDiffInPreviewMsg:
+		if m.previewModel.Visible() {
+			return msg.FullScreen
+		}
+		m.previewModel.SetVisible(true)
+		return common.SelectionChanged
 	case common.TogglePasswordMsg:
 		if m.password != nil {
 			// let the current prompt clean itself
@@ -420,9 +664,25 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			cmds = append(cmds, cmd)
 		}
 		if m.scriptRunner.Done() {
+			m.keepRefreshHooks(m.scriptRunner)
 			m.scriptRunner = nil
 		}
 	}
+	for _, runner := range slices.Clone(m.timerRunners) {
+		if cmd := runner.HandleMsg(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if runner.Done() {
+			m.keepRefreshHooks(runner)
+			m.timerRunners = slices.DeleteFunc(m.timerRunners, func(r *scripting.Runner) bool { return r == runner })
+		}
+	}
+	// the callbacks scheduled by refresh hooks with `jjui.after`
+	for _, hook := range m.refreshHooks {
+		if cmd := hook.HandleMsg(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
 
 	if m.oplog != nil {
 		cmds = append(cmds, m.oplog.Update(msg))
@@ -438,12 +698,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 }
 
 func (m *Model) updateStatus() {
+	m.status.SetHint("")
 	switch {
 	case m.diff != nil:
 		m.status.SetMode("diff")
 		m.status.SetHelp(m.diff)
 	case m.oplog != nil:
-		m.status.SetMode("oplog")
+		m.status.SetMode(fmt.Sprintf("oplog (%d)", m.oplog.Len()))
 		m.status.SetHelp(m.oplog)
 	case m.stacked != nil:
 		if s, ok := m.stacked.(help.KeyMap); ok {
@@ -454,7 +715,15 @@ func (m *Model) updateStatus() {
 		m.status.SetHelp(m.leader)
 	default:
 		m.status.SetHelp(m.revisions)
-		m.status.SetMode(m.revisions.CurrentOperation().Name())
+		mode := m.revisions.CurrentOperation().Name()
+		if m.context.IdType == config.IdTypeCommitId {
+			mode += " (commit id)"
+		}
+		if m.hiddenRevset != "" {
+			mode += " (hidden shown)"
+		}
+		m.status.SetMode(mode)
+		m.status.SetHint(m.revisions.DragHint())
 	}
 }
 
@@ -557,6 +826,329 @@ func (m *Model) scheduleAutoRefresh() tea.Cmd {
 	return nil
 }
 
+// chooseSavedRevset lists the revsets configured under revisions.saved_revsets next to their labels
+func (m *Model) chooseSavedRevset() tea.Cmd {
+	saved := config.Current.Revisions.SavedRevsets
+	if len(saved) == 0 {
+		return intents.Invoke(intents.AddMessage{Text: "no saved revsets, add them under [revisions.saved_revsets]"})
+	}
+	m.savedRevsets = make(map[string]string, len(saved))
+	var options []string
+	for _, label := range config.Current.Revisions.SavedRevsetLabels() {
+		option := fmt.Sprintf("%s: %s", label, saved[label])
+		m.savedRevsets[option] = saved[label]
+		options = append(options, option)
+	}
+	return func() tea.Msg {
+		return common.ShowChooseMsg{Options: options, Title: "Saved revsets"}
+	}
+}
+
+// copyRevset puts the current revset on the clipboard
+func (m *Model) copyRevset() tea.Cmd {
+	revset := m.context.CurrentRevset
+	return func() tea.Msg {
+		if err := writeClipboard(revset); err != nil {
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		return intents.AddMessage{Text: fmt.Sprintf("copied revset %s to the clipboard", revset)}
+	}
+}
+
+// pasteRevset applies the revset on the clipboard, jj is asked to validate it first so that
+// whatever else is on the clipboard doesn't replace the revisions with an error
+func (m *Model) pasteRevset() tea.Cmd {
+	return func() tea.Msg {
+		text, err := readClipboard()
+		if err != nil {
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		revset := common.SingleLine(text)
+		if revset == "" {
+			return intents.AddMessage{Text: "the clipboard is empty"}
+		}
+		if _, err := m.context.RunCommandImmediate(jj.ValidateRevset(revset)); err != nil {
+			err = fmt.Errorf("the clipboard doesn't hold a valid revset: %w", err)
+			return intents.AddMessage{Text: err.Error(), Err: err}
+		}
+		return common.UpdateRevSetMsg(revset)
+	}
+}
+
+// chooseRecentRepository lists the other repositories opened recently
+func (m *Model) chooseRecentRepository() tea.Cmd {
+	repositories := m.context.RecentRepositories()
+	if len(repositories) == 0 {
+		return intents.Invoke(intents.AddMessage{Text: "no other repositories opened recently"})
+	}
+	m.repositories = repositories
+	return func() tea.Msg {
+		return common.ShowChooseMsg{Options: repositories, Title: "Recent repositories"}
+	}
+}
+
+// switchRepository rebinds the context to another repository and reloads the views
+func (m *Model) switchRepository(location string) tea.Cmd {
+	m.context.SwitchLocation(location)
+	m.oplog = nil
+	return tea.Batch(tea.SetWindowTitle(m.windowTitle()), common.UpdateRevSet(m.context.CurrentRevset), m.loadWorkspaces(false))
+}
+
+// windowTitle names the workspace next to the location when the repository has more than one
+func (m *Model) windowTitle() string {
+	if m.context.Workspace != "" {
+		return fmt.Sprintf("jjui - %s (%s)", m.context.Location, m.context.Workspace)
+	}
+	return fmt.Sprintf("jjui - %s", m.context.Location)
+}
+
+// loadWorkspaces lists the workspaces of the repository in the background, the switcher
+// is shown once they are loaded when choose is set
+func (m *Model) loadWorkspaces(choose bool) tea.Cmd {
+	return func() tea.Msg {
+		workspaces, err := m.context.Workspaces()
+		return workspacesLoadedMsg{workspaces: workspaces, err: err, choose: choose}
+	}
+}
+
+// workspacesLoaded updates the window title with the current workspace and lists the
+// other workspaces when the switcher was asked for
+func (m *Model) workspacesLoaded(msg workspacesLoadedMsg) tea.Cmd {
+	if msg.err != nil {
+		if !msg.choose {
+			return nil
+		}
+		err := fmt.Errorf("failed to list workspaces: %w", msg.err)
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	m.context.Workspace = m.context.CurrentWorkspace(msg.workspaces)
+	title := tea.SetWindowTitle(m.windowTitle())
+	if !msg.choose {
+		return title
+	}
+	if len(msg.workspaces) < 2 {
+		return tea.Batch(title, intents.Invoke(intents.AddMessage{Text: "no other workspaces"}))
+	}
+	var options []string
+	m.workspaces = make(map[string]string)
+	for _, w := range msg.workspaces {
+		if w.Name == m.context.Workspace {
+			continue
+		}
+		options = append(options, w.Name)
+		m.workspaces[w.Name] = w.Root
+	}
+	return tea.Batch(title, func() tea.Msg {
+		return common.ShowChooseMsg{Options: options, Title: "Workspaces"}
+	})
+}
+
+// switchWorkspace rebinds the context to the root of another workspace of the repository
+func (m *Model) switchWorkspace(name string, root string) tea.Cmd {
+	m.context.SwitchLocation(root)
+	m.context.Workspace = name
+	m.oplog = nil
+	return tea.Batch(tea.SetWindowTitle(m.windowTitle()), common.UpdateRevSet(m.context.CurrentRevset))
+}
+
+// repeatCommand prepares the last custom command again for the current selection
+func (m *Model) repeatCommand() tea.Cmd {
+	if m.lastCommand == nil {
+		return intents.Invoke(intents.AddMessage{Text: "no custom command to repeat"})
+	}
+	if !m.lastCommand.IsApplicableTo(m.context.SelectedItem) {
+		name := m.lastCommand.Description(m.context)
+		if lc, ok := m.lastCommand.(context.LabeledCommand); ok {
+			name = lc.Label()
+		}
+		return intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("%s doesn't apply to the selection", name)})
+	}
+	return customcommands.Run(m.context, m.lastCommand)
+}
+
+// showFailedOutput opens the whole output of a failed jj command when it doesn't fit in a flash message
+func (m *Model) showFailedOutput(err error) tea.Cmd {
+	var commandErr *common.CommandError
+	if !errors.As(err, &commandErr) {
+		return nil
+	}
+	output := strings.TrimRight(commandErr.Output(), "\n")
+	maxLines := config.Current.UI.Flash.MaxLines
+	if maxLines <= 0 || strings.Count(output, "\n")+1 <= maxLines {
+		return nil
+	}
+	return func() tea.Msg {
+		return common.ShowOutputMsg{Title: commandErr.Title(), Output: output}
+	}
+}
+
+// gitPendingTimeout is how long the git mode bound to g waits for the g of gg before it opens
+const gitPendingTimeout = 500 * time.Millisecond
+
+// gitPendingTimeoutMsg opens the git mode when no key followed its g
+type gitPendingTimeoutMsg struct {
+	tag int
+}
+
+// openGit opens the git mode for the selected revisions
+func (m *Model) openGit() tea.Cmd {
+	model := git.NewModel(m.context, m.revisions.SelectedRevisions())
+	model.Parent = m.ViewNode
+	m.stacked = model
+	return m.stacked.Init()
+}
+
+// setAsideScriptRunner makes room for a new script. The running script keeps running its timers in
+// the background when it has finished otherwise, a script still waiting for a message is stopped.
+func (m *Model) setAsideScriptRunner() {
+	if m.scriptRunner == nil {
+		return
+	}
+	if m.scriptRunner.Finished() {
+		m.timerRunners = append(m.timerRunners, m.scriptRunner)
+	} else {
+		m.scriptRunner.Close()
+	}
+	m.scriptRunner = nil
+}
+
+// keepRefreshHooks holds on to finished scripts that registered callbacks to run after each refresh.
+// Running a script again replaces the callbacks its previous run registered.
+func (m *Model) keepRefreshHooks(runner *scripting.Runner) {
+	if !runner.HasHooks() {
+		return
+	}
+	index := slices.IndexFunc(m.refreshHooks, func(r *scripting.Runner) bool { return r.Script() == runner.Script() })
+	switch {
+	case index < 0:
+		m.refreshHooks = append(m.refreshHooks, runner)
+	case m.refreshHooks[index] != runner:
+		m.refreshHooks[index].Close()
+		m.refreshHooks[index] = runner
+	}
+}
+
+// toggleIdType switches the identifier shown first in the revisions view
+// between the change id and the commit id
+func (m *Model) toggleIdType() tea.Cmd {
+	if m.context.IdType == config.IdTypeCommitId {
+		m.context.IdType = config.IdTypeChangeId
+	} else {
+		m.context.IdType = config.IdTypeCommitId
+	}
+	return common.SelectionChanged
+}
+
+// applyBackground resolves the palette again when the terminal switches between a dark and a light background.
+// Views that are opened afterwards pick the new palette up by themselves, the open ones are told to read their styles again.
+func (m *Model) applyBackground(dark bool) tea.Cmd {
+	if dark == m.context.DarkBackground {
+		return nil
+	}
+	if err := common.ApplyTheme(dark, m.context.JJConfig.GetApplicableColors()); err != nil {
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	m.context.DarkBackground = dark
+	return func() tea.Msg {
+		return common.ThemeChangedMsg{}
+	}
+}
+
+// broadcastThemeChanged sends the message to every open view, including the ones that don't
+// receive messages while they are hidden
+func (m *Model) broadcastThemeChanged(msg common.ThemeChangedMsg) tea.Cmd {
+	cmds := []tea.Cmd{
+		m.revisions.Update(msg),
+		m.revsetModel.Update(msg),
+		m.status.Update(msg),
+		m.flash.Update(msg),
+	}
+	if m.oplog != nil {
+		cmds = append(cmds, m.oplog.Update(msg))
+	}
+	if m.diff != nil {
+		cmds = append(cmds, m.diff.Update(msg))
+	}
+	if m.stacked != nil {
+		cmds = append(cmds, m.stacked.Update(msg))
+	}
+	if m.previewModel.Visible() {
+		cmds = append(cmds, m.previewModel.Update(msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// toggleHidden switches between the current revset and revisions.hidden_revset wrapped around it
+func (m *Model) toggleHidden() tea.Cmd {
+	if m.hiddenRevset != "" {
+		previous := m.revsetBeforeHidden
+		m.hiddenRevset, m.revsetBeforeHidden = "", ""
+		return common.UpdateRevSet(previous)
+	}
+	m.revsetBeforeHidden = m.context.CurrentRevset
+	m.hiddenRevset = strings.ReplaceAll(config.Current.Revisions.HiddenRevset, "$revset", m.context.CurrentRevset)
+	return common.UpdateRevSet(m.hiddenRevset)
+}
+
+// toggleDryRun switches between running the commands that change the repository and only
+// showing them, for the rest of the session
+func (m *Model) toggleDryRun() tea.Cmd {
+	m.context.DryRun = !m.context.DryRun
+	text := "dry run off, commands are run again"
+	if m.context.DryRun {
+		text = "dry run on, commands are shown instead of being run"
+	}
+	return intents.Invoke(intents.AddMessage{Text: text})
+}
+
+// toggleSigning switches signing the commits created or rewritten from jjui for the rest of the session
+func (m *Model) toggleSigning() tea.Cmd {
+	m.context.SetSignCommits(!m.context.SignCommits)
+	text := "signing off, signing.behavior applies again"
+	if m.context.SignCommits {
+		text = "signing on, new and rewritten commits are signed"
+	}
+	return intents.Invoke(intents.AddMessage{Text: text})
+}
+
+// toggleTimestamps switches between relative and absolute timestamps and remembers the choice for the next launch
+func (m *Model) toggleTimestamps() tea.Cmd {
+	absolute := !config.Current.UI.AbsoluteTimestamps
+	config.Current.UI.AbsoluteTimestamps = absolute
+	if m.context.State != nil {
+		m.context.State.SetAbsoluteTimestamps(absolute)
+	}
+	if m.oplog != nil {
+		return m.oplog.Init()
+	}
+	return common.RefreshAndKeepSelections
+}
+
+// toggleGraph switches the revisions between the graph and a flat list, keeping the selection.
+// The choice is remembered for the next launch.
+func (m *Model) toggleGraph() tea.Cmd {
+	style := "flat"
+	if current, _ := config.GetGraphStyle(config.Current); current == config.GraphStyleFlat {
+		style = "graph"
+	}
+	config.Current.Revisions.GraphStyle = style
+	if m.context.State != nil {
+		m.context.State.SetGraphStyle(style)
+	}
+
+	refresh := common.RefreshMsg{KeepSelections: true}
+	if selected := m.revisions.SelectedRevision(); selected != nil {
+		refresh.SelectedRevision = selected.GetChangeId()
+	}
+	return func() tea.Msg { return refresh }
+}
+
+// isAutoRefreshPaused reports whether the user is in the middle of something
+// that an auto refresh would disrupt
+func (m *Model) isAutoRefreshPaused() bool {
+	return m.revisions.IsEditing() || m.revsetModel.Editing || m.stacked != nil
+}
+
 func (m *Model) isSafeToQuit() bool {
 	if m.stacked != nil {
 		return false
@@ -570,6 +1162,43 @@ func (m *Model) isSafeToQuit() bool {
 	return false
 }
 
+// canChooseDiffTool reports whether the diff tools apply to what the revisions view shows, which is
+// either the revisions or the files of the details view without a confirmation on top of them
+func (m *Model) canChooseDiffTool() bool {
+	if m.oplog != nil || m.stacked != nil || m.diff != nil {
+		return false
+	}
+	if m.revisions.InNormalMode() {
+		return true
+	}
+	return m.revisions.CurrentOperation().Name() == "details" && !m.revisions.IsEditing()
+}
+
+// isMutatingKey reports whether the key starts an operation that changes the
+// repository in the view that currently receives it
+func (m *Model) isMutatingKey(msg tea.KeyMsg) bool {
+	if m.stacked != nil || m.diff != nil {
+		return false
+	}
+	var bindings []*key.Binding
+	switch {
+	case m.oplog != nil:
+		bindings = m.keyMap.MutatingOpLog()
+	case m.revisions.InNormalMode():
+		bindings = m.keyMap.MutatingRevisions()
+	case m.revisions.CurrentOperation().Name() == "details":
+		bindings = m.keyMap.MutatingDetails()
+	case m.revisions.CurrentOperation().Name() == "evolog":
+		bindings = m.keyMap.MutatingEvolog()
+	}
+	for _, binding := range bindings {
+		if key.Matches(msg, *binding) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Model) findViewAt(x, y int) common.IMouseAware {
 	// well, these are all the views that can receive mouse input for now
 	pt := cellbuf.Pos(x, y)
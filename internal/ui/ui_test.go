@@ -1,11 +1,23 @@
 package ui
 
 import (
+	"errors"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/scripting"
+	"github.com/idursun/jjui/internal/ui/bookmarks"
+	"github.com/idursun/jjui/internal/ui/choose"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	customcommands "github.com/idursun/jjui/internal/ui/custom_commands"
+	"github.com/idursun/jjui/internal/ui/git"
+	"github.com/idursun/jjui/internal/ui/input"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 )
 
@@ -21,3 +33,606 @@ func Test_Update_RevsetWithEmptyInputKeepsDefaultRevset(t *testing.T) {
 
 	assert.Equal(t, ctx.DefaultRevset, ctx.CurrentRevset)
 }
+
+func Test_Update_AutoRefreshIsSkippedWhileOverlayIsOpen(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewUI(test.NewTestContext(commandRunner))
+	model.stacked = input.New()
+
+	assert.Nil(t, model.Update(triggerAutoRefreshMsg{}))
+}
+
+func Test_Update_AutoRefreshIsEmittedWhenIdle(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	cmd := model.Update(triggerAutoRefreshMsg{})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, common.AutoRefreshMsg{}, cmd())
+}
+
+func Test_Update_MouseWheelScrollsStackedModel(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewUI(test.NewTestContext(commandRunner))
+	model.stacked = choose.New([]string{"one", "two", "three", "four", "five"})
+
+	model.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, choose.SelectedMsg{Value: "four"}, cmd())
+}
+
+func Test_Update_MouseClickIsIgnoredByStackedModel(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewUI(test.NewTestContext(commandRunner))
+	model.stacked = choose.New([]string{"one", "two"})
+
+	assert.Nil(t, model.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}))
+}
+
+func Test_Update_SavedRevsetIsApplied(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+	config.Current.Revisions.SavedRevsets = map[string]string{"stack": "trunk()..@"}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewUI(test.NewTestContext(commandRunner))
+	cmd := model.chooseSavedRevset()
+	assert.Equal(t, common.ShowChooseMsg{Options: []string{"stack: trunk()..@"}, Title: "Saved revsets"}, cmd())
+
+	cmd = model.Update(choose.SelectedMsg{Value: "stack: trunk()..@"})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, common.UpdateRevSetMsg("trunk()..@"), cmd())
+}
+
+func Test_Update_ReadOnlyBlocksMutatingKeys(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.ReadOnly = true
+	model := NewUI(ctx)
+
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, intents.AddMessage{Text: "read-only mode"}, cmd())
+	assert.True(t, model.revisions.InNormalMode())
+}
+
+func Test_Update_AtOperationBlocksMutatingKeysUntilLatest(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SetAtOperation("abc123")
+	model := NewUI(ctx)
+
+	cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	assert.NotNil(t, cmd)
+	assert.Equal(t, intents.AddMessage{Text: "viewing operation abc123, press V to go back to the latest operation"}, cmd())
+	assert.True(t, model.revisions.InNormalMode())
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("V")})
+	assert.Empty(t, ctx.AtOperation)
+}
+
+func Test_Update_ReadOnlyAllowsNavigation(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.ReadOnly = true
+	model := NewUI(ctx)
+
+	assert.False(t, model.isMutatingKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}))
+	assert.False(t, model.isMutatingKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")}))
+}
+
+func Test_Update_ToggleHiddenRestoresPreviousRevset(t *testing.T) {
+	origConfig := *config.Current
+	defer func() {
+		*config.Current = origConfig
+	}()
+	config.Current.Revisions.HiddenRevset = "($revset) | at_operation(@-, $revset)"
+
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "trunk()..@"
+	model := NewUI(ctx)
+
+	cmd := model.toggleHidden()
+	assert.Equal(t, common.UpdateRevSetMsg("(trunk()..@) | at_operation(@-, trunk()..@)"), cmd())
+	model.Update(cmd())
+	model.updateStatus()
+	assert.Contains(t, model.status.View(), "(hidden shown)")
+
+	cmd = model.toggleHidden()
+	assert.Equal(t, common.UpdateRevSetMsg("trunk()..@"), cmd())
+	model.Update(cmd())
+	model.updateStatus()
+	assert.NotContains(t, model.status.View(), "(hidden shown)")
+}
+
+func Test_Update_BackgroundChangeSwitchesTheme(t *testing.T) {
+	origPalette := common.DefaultPalette
+	defer func() {
+		common.DefaultPalette = origPalette
+	}()
+
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	assert.NoError(t, common.ApplyTheme(false, nil))
+	model := NewUI(ctx)
+	light := common.DefaultPalette.Get("selected").GetBackground()
+
+	cmd := model.Update(common.BackgroundChangedMsg{Dark: true})
+	assert.True(t, ctx.DarkBackground)
+	assert.NotEqual(t, light, common.DefaultPalette.Get("selected").GetBackground())
+	assert.Equal(t, common.ThemeChangedMsg{}, cmd())
+}
+
+func Test_Update_WorkspaceSwitcherListsOtherWorkspaces(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.WorkspaceList()).SetOutput([]byte("default\nsecond\n"))
+	commandRunner.Expect(jj.WorkspaceRoot("default")).SetOutput([]byte("/repo\n"))
+	commandRunner.Expect(jj.WorkspaceRoot("second")).SetOutput([]byte("/second\n"))
+	commandRunner.Expect(jj.ConfigListAll())
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Location = "/repo"
+	model := NewUI(ctx)
+
+	model.Update(model.loadWorkspaces(true)())
+	assert.Equal(t, "default", ctx.Workspace)
+	assert.Equal(t, map[string]string{"second": "/second"}, model.workspaces)
+	assert.Equal(t, "jjui - /repo (default)", model.windowTitle())
+
+	model.Update(choose.SelectedMsg{Value: "second"})
+	assert.Equal(t, "/second", ctx.Location)
+	assert.Equal(t, "second", ctx.Workspace)
+	assert.Nil(t, model.workspaces)
+}
+
+func Test_Update_WorkspaceSwitcherIsHiddenForSingleWorkspace(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.WorkspaceList()).SetOutput([]byte("default\n"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Location = "/repo"
+	model := NewUI(ctx)
+
+	cmd := model.Update(model.loadWorkspaces(true)())
+	assert.NotNil(t, cmd)
+	assert.Empty(t, ctx.Workspace)
+	assert.Nil(t, model.workspaces)
+	assert.Equal(t, "jjui - /repo", model.windowTitle())
+}
+
+func Test_Update_RepeatsLastCustomCommand(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := NewUI(ctx)
+	repeat := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(".")}
+
+	cmd := model.Update(repeat)
+	assert.Equal(t, intents.AddMessage{Text: "no custom command to repeat"}, cmd())
+
+	command := context.CustomRunCommand{
+		CustomCommandBase: context.CustomCommandBase{Name: "show"},
+		Args:              []string{"show", jj.ChangeIdPlaceholder},
+	}
+	model.Update(customcommands.RanMsg{Command: command})
+	cmd = model.Update(repeat)
+	assert.Equal(t, intents.AddMessage{Text: "show doesn't apply to the selection"}, cmd())
+
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+	assert.NotNil(t, model.Update(repeat))
+}
+
+func Test_Update_LongCommandFailureOpensOutput(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Flash.MaxLines = 2
+
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	short := &common.CommandError{Args: []string{"git", "push"}, ExitCode: 1, Stderr: "Error: rejected\n"}
+	assert.Nil(t, model.showFailedOutput(short))
+	assert.Nil(t, model.showFailedOutput(errors.New("one\ntwo\nthree")))
+
+	long := &common.CommandError{Args: []string{"git", "push"}, ExitCode: 1, Stdout: "Changes to push:\n", Stderr: "Error: rejected\nHint: fetch first\n"}
+	cmd := model.showFailedOutput(long)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, common.ShowOutputMsg{
+		Title:  "jj git push (exit code 1)",
+		Output: "Changes to push:\nError: rejected\nHint: fetch first",
+	}, cmd())
+}
+
+func Test_Update_LongImmediateFailureOpensOutput(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+	config.Current.UI.Flash.MaxLines = 2
+
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	long := &common.CommandError{Args: []string{"log"}, ExitCode: 1, Stderr: "Error: one\nError: two\nError: three\n"}
+	expected := common.ShowOutputMsg{Title: "jj log (exit code 1)", Output: "Error: one\nError: two\nError: three"}
+	for _, msg := range []tea.Msg{
+		intents.AddMessage{Text: long.Error(), Err: long},
+		common.UpdateRevisionsFailedMsg{Err: long},
+	} {
+		var shown []common.ShowOutputMsg
+		test.SimulateModel(&recorder{}, model.Update(msg), func(msg tea.Msg) {
+			if msg, ok := msg.(common.ShowOutputMsg); ok {
+				shown = append(shown, msg)
+			}
+		})
+		assert.Equal(t, []common.ShowOutputMsg{expected}, shown)
+	}
+}
+
+func Test_Update_ToggleGraphSavesStyleAndKeepsSelection(t *testing.T) {
+	origConfig := *config.Current
+	defer func() { *config.Current = origConfig }()
+
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	model := NewUI(ctx)
+
+	cmd := model.toggleGraph()
+	assert.Equal(t, "flat", config.Current.Revisions.GraphStyle)
+	assert.Equal(t, common.RefreshMsg{KeepSelections: true}, cmd())
+	assert.Equal(t, "flat", ctx.State.GraphStyle)
+
+	model.toggleGraph()
+	assert.Equal(t, "graph", config.Current.Revisions.GraphStyle)
+}
+
+func Test_Update_CopyRevset(t *testing.T) {
+	origWriteClipboard := writeClipboard
+	defer func() { writeClipboard = origWriteClipboard }()
+	var copied string
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.CurrentRevset = "trunk()..@"
+	model := NewUI(ctx)
+
+	msg := model.copyRevset()()
+	assert.Equal(t, "trunk()..@", copied)
+	assert.Equal(t, intents.AddMessage{Text: "copied revset trunk()..@ to the clipboard"}, msg)
+}
+
+func Test_Update_PasteRevset(t *testing.T) {
+	origReadClipboard := readClipboard
+	defer func() { readClipboard = origReadClipboard }()
+	readClipboard = func() (string, error) { return "mine()\n  & ~empty()\n", nil }
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ValidateRevset("mine() & ~empty()"))
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	assert.Equal(t, common.UpdateRevSetMsg("mine() & ~empty()"), model.pasteRevset()())
+}
+
+func Test_Update_PasteInvalidRevset(t *testing.T) {
+	origReadClipboard := readClipboard
+	defer func() { readClipboard = origReadClipboard }()
+	readClipboard = func() (string, error) { return "not a revset((", nil }
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ValidateRevset("not a revset((")).SetError(errors.New("Error: Failed to parse revset"))
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	msg, ok := model.pasteRevset()().(intents.AddMessage)
+	assert.True(t, ok)
+	assert.Error(t, msg.Err)
+	assert.Contains(t, msg.Text, "the clipboard doesn't hold a valid revset")
+}
+
+func Test_Update_DiffToolIsIgnoredUnderStackedModel(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := NewUI(test.NewTestContext(commandRunner))
+	assert.True(t, model.canChooseDiffTool())
+
+	model.stacked = input.New()
+	assert.False(t, model.canChooseDiffTool())
+}
+
+func Test_Update_ShowOutputIncludesFailedOutput(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect([]string{"evolog", "-r", "abc"}).SetError(&common.CommandError{
+		Args:     []string{"evolog", "-r", "abc"},
+		ExitCode: 1,
+		Stdout:   "partial\n",
+		Stderr:   "Error: boom\n",
+	})
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+	model := NewUI(ctx)
+
+	command := context.CustomRunCommand{Args: []string{"evolog", "-r", jj.ChangeIdPlaceholder}, ShowOutput: true}
+	msgs := command.Prepare(ctx)().(tea.BatchMsg)
+	model.Update(msgs[0]())
+	cmd := model.Update(msgs[1]())
+	assert.NotNil(t, cmd)
+	assert.Equal(t, common.ShowOutputMsg{Title: "jj evolog -r abc", Output: "partial\nError: boom\n"}, cmd())
+}
+
+// ignoredMsgs is a model leaving the messages to the observers
+type ignoredMsgs struct{}
+
+func (ignoredMsgs) Update(tea.Msg) tea.Cmd { return nil }
+
+func Test_Update_ShowOutputRunsPostCommand(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect([]string{"evolog", "-r", "abc"}).SetOutput([]byte("evolog output"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+
+	command := context.CustomRunCommand{Args: []string{"evolog", "-r", jj.ChangeIdPlaceholder}, ShowOutput: true}
+	var shown []common.ShowOutputMsg
+	refreshed := false
+	test.SimulateModel(test.CommandResults(ignoredMsgs{}), command.Prepare(ctx), func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case common.ShowOutputMsg:
+			shown = append(shown, msg)
+		case common.RefreshMsg:
+			refreshed = true
+		}
+	})
+	assert.Equal(t, []common.ShowOutputMsg{{Title: "jj evolog -r abc", Output: "evolog output"}}, shown)
+	assert.True(t, refreshed)
+}
+
+func Test_Update_ReadOnlyRefusesCustomCommands(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.ReadOnly = true
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+
+	command := context.CustomRunCommand{Args: []string{"abandon", "-r", jj.ChangeIdPlaceholder}}
+	var completed []common.CommandCompletedMsg
+	test.SimulateModel(&recorder{}, customcommands.Run(ctx, command), func(msg tea.Msg) {
+		if msg, ok := msg.(common.CommandCompletedMsg); ok {
+			completed = append(completed, msg)
+		}
+	})
+	assert.Len(t, completed, 1)
+	assert.EqualError(t, completed[0].Err, "read-only mode, jj abandon -r abc is not run")
+}
+
+func Test_Update_ReadOnlyRunsReadingCustomCommands(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect([]string{"log", "-r", "abc"}).SetOutput([]byte("log output"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.ReadOnly = true
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+
+	command := context.CustomRunCommand{Args: []string{"log", "-r", jj.ChangeIdPlaceholder}}
+	var completed []common.CommandCompletedMsg
+	test.SimulateModel(&recorder{}, customcommands.Run(ctx, command), func(msg tea.Msg) {
+		if msg, ok := msg.(common.CommandCompletedMsg); ok {
+			completed = append(completed, msg)
+		}
+	})
+	assert.Len(t, completed, 1)
+	assert.NoError(t, completed[0].Err)
+}
+
+func Test_Update_AtOperationRefusesCustomCommands(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SetAtOperation("abc123")
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+
+	command := context.CustomRunCommand{Args: []string{"abandon", "-r", jj.ChangeIdPlaceholder}}
+	var completed []common.CommandCompletedMsg
+	test.SimulateModel(&recorder{}, customcommands.Run(ctx, command), func(msg tea.Msg) {
+		if msg, ok := msg.(common.CommandCompletedMsg); ok {
+			completed = append(completed, msg)
+		}
+	})
+	assert.Len(t, completed, 1)
+	assert.EqualError(t, completed[0].Err, "viewing operation abc123, jj abandon -r abc is not run")
+}
+
+func Test_Update_AtOperationRunsReadingCustomCommands(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect([]string{"show", "-r", "abc"})
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.SetAtOperation("abc123")
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+
+	command := context.CustomRunCommand{Args: []string{"show", "-r", jj.ChangeIdPlaceholder}}
+	var completed []common.CommandCompletedMsg
+	test.SimulateModel(&recorder{}, customcommands.Run(ctx, command), func(msg tea.Msg) {
+		if msg, ok := msg.(common.CommandCompletedMsg); ok {
+			completed = append(completed, msg)
+		}
+	})
+	assert.Len(t, completed, 1)
+	assert.NoError(t, completed[0].Err)
+}
+
+func Test_Update_DryRunRunsReadingCustomCommands(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect([]string{"show", "-r", "abc"}).SetOutput([]byte("shown"))
+	defer commandRunner.Verify()
+	ctx := test.NewTestContext(commandRunner)
+	ctx.DryRun = true
+	ctx.SelectedItem = context.SelectedRevision{ChangeId: "abc"}
+
+	var completed []common.CommandCompletedMsg
+	observe := func(msg tea.Msg) {
+		if msg, ok := msg.(common.CommandCompletedMsg); ok {
+			completed = append(completed, msg)
+		}
+	}
+	test.SimulateModel(&recorder{}, customcommands.Run(ctx, context.CustomRunCommand{Args: []string{"show", "-r", jj.ChangeIdPlaceholder}}), observe)
+	test.SimulateModel(&recorder{}, customcommands.Run(ctx, context.CustomRunCommand{Args: []string{"abandon", "-r", jj.ChangeIdPlaceholder}}), observe)
+	assert.Equal(t, []string{"shown", "dry run: jj abandon -r abc"}, []string{completed[0].Output, completed[1].Output})
+}
+
+// recorder receives the messages of a simulation without reacting to them
+type recorder struct{}
+
+func (r *recorder) Update(tea.Msg) tea.Cmd {
+	return nil
+}
+
+func Test_Update_NewScriptKeepsTheTimersOfTheFinishedOne(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	cmd := model.Update(common.RunLuaScriptMsg{Script: `jjui.after(1, function() flash("first") end)`})
+	first := model.scriptRunner
+	model.Update(common.RunLuaScriptMsg{Script: `jjui.after(1, function() flash("second") end)`})
+	assert.Equal(t, []*scripting.Runner{first}, model.timerRunners)
+
+	var messages []intents.AddMessage
+	test.SimulateModel(model, cmd, func(msg tea.Msg) {
+		if msg, ok := msg.(intents.AddMessage); ok {
+			messages = append(messages, msg)
+		}
+	})
+	assert.Equal(t, []intents.AddMessage{{Text: "first"}}, messages)
+	assert.Empty(t, model.timerRunners)
+}
+
+func Test_Update_NewScriptStopsTheWaitingOne(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	model.Update(common.RunLuaScriptMsg{Script: `choose({"a", "b"})`})
+	first := model.scriptRunner
+	assert.False(t, first.Finished())
+	model.Update(common.RunLuaScriptMsg{Script: `flash("second")`})
+	assert.Empty(t, model.timerRunners)
+	assert.True(t, first.Done())
+	assert.Nil(t, first.HandleMsg(choose.SelectedMsg{Value: "a"}))
+}
+
+func Test_Update_GitModeOnGLeavesGGToJumpToTheTop(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	assert.Nil(t, model.stacked)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	assert.Nil(t, model.stacked)
+	assert.False(t, model.gitPending)
+}
+
+// loadRevision loads a log of a single revision into the model
+func loadRevision(t *testing.T, model *Model, commandRunner *test.CommandRunner) {
+	origConfig := *config.Current
+	t.Cleanup(func() { *config.Current = origConfig })
+	config.Current.Revisions.LogBatching = false
+	commandRunner.Expect(jj.Log("", config.Current.Limit, "")).SetOutput([]byte("@  _PREFIX:abcde_PREFIX:xyrq_PREFIX:false abcde\n"))
+	commandRunner.Expect(jj.GetTrunk())
+	test.SimulateModel(model, common.Refresh)
+}
+
+func Test_Update_GitModeOnGOpensOnTheNextKey(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList())
+	commandRunner.Expect(jj.BookmarkList("abcde"))
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+	loadRevision(t, model, commandRunner)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.IsType(t, &git.Model{}, model.stacked)
+}
+
+func Test_Update_GitModeOnGOpensAfterTheTimeout(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList())
+	commandRunner.Expect(jj.BookmarkList("abcde"))
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+	loadRevision(t, model, commandRunner)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	assert.Nil(t, model.Update(gitPendingTimeoutMsg{tag: model.gitPendingTag - 1}))
+	assert.Nil(t, model.stacked)
+	model.Update(gitPendingTimeoutMsg{tag: model.gitPendingTag})
+	assert.IsType(t, &git.Model{}, model.stacked)
+}
+
+func Test_Update_ClosingBookmarkModeUnchecksTheBookmarks(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := NewUI(ctx)
+	model.stacked = bookmarks.NewModel(ctx, &jj.Commit{ChangeId: "abc", CommitId: "123"}, nil)
+	ctx.AddCheckedItem(context.SelectedRevision{ChangeId: "abc", CommitId: "123"})
+	ctx.AddCheckedItem(context.SelectedBookmark{Name: "main"})
+
+	model.Update(common.CloseViewMsg{})
+	assert.Nil(t, model.stacked)
+	assert.Equal(t, []context.SelectedItem{context.SelectedRevision{ChangeId: "abc", CommitId: "123"}}, ctx.CheckedItems)
+}
+
+func Test_Update_RunningAScriptAgainReplacesItsRefreshHooks(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+	model := NewUI(test.NewTestContext(commandRunner))
+
+	const script = `jjui.on_refresh(function(revset) flash(revset) end)`
+	model.Update(common.RunLuaScriptMsg{Script: script})
+	first := model.refreshHooks[0]
+	model.Update(common.RunLuaScriptMsg{Script: `jjui.on_refresh(function(revset) end)`})
+	model.Update(common.RunLuaScriptMsg{Script: script})
+
+	assert.Len(t, model.refreshHooks, 2)
+	assert.NotSame(t, first, model.refreshHooks[0])
+	assert.False(t, first.HasHooks())
+}
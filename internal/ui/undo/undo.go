@@ -1,6 +1,10 @@
 package undo
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -9,6 +13,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/confirmation"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
 )
 
 var _ common.Model = (*Model)(nil)
@@ -59,3 +64,27 @@ func NewModel(context *context.MainContext) *Model {
 		confirmation: model,
 	}
 }
+
+// Operations restores the repository to the operation before the last count operations
+func Operations(context *context.MainContext, value string) tea.Cmd {
+	count, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || count < 1 {
+		err := fmt.Errorf("invalid number of operations: %q", value)
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	output, err := context.RunCommandImmediate(jj.OpLogIds(count + 1))
+	if err != nil {
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	ids := strings.Fields(string(output))
+	// the oldest operation can't be undone as there is nothing before it to restore
+	if len(ids) <= count {
+		err := fmt.Errorf("cannot undo %d operations, only %d can be undone", count, max(len(ids)-1, 0))
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	target := ids[count]
+	return tea.Sequence(
+		intents.Invoke(intents.AddMessage{Text: fmt.Sprintf("undoing %d operations by restoring %.12s", count, target)}),
+		context.RunCommand(jj.OpRestore(target), common.Refresh),
+	)
+}
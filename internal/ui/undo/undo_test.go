@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/x/cellbuf"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -39,3 +40,37 @@ func TestCancel(t *testing.T) {
 
 	test.SimulateModel(model, test.Press(tea.KeyEsc))
 }
+
+func TestOperations(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogIds(3)).SetOutput([]byte("aaa\nbbb\nccc\n"))
+	commandRunner.Expect(jj.OpRestore("ccc"))
+	defer commandRunner.Verify()
+
+	var msgs []tea.Msg
+	test.SimulateModel(noopModel{}, Operations(test.NewTestContext(commandRunner), "2"), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	assert.Contains(t, msgs, intents.AddMessage{Text: "undoing 2 operations by restoring ccc"})
+}
+
+type noopModel struct{}
+
+func (noopModel) Update(tea.Msg) tea.Cmd { return nil }
+
+func TestOperations_MoreThanAvailable(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogIds(6)).SetOutput([]byte("aaa\nbbb\n"))
+	defer commandRunner.Verify()
+
+	msg := Operations(test.NewTestContext(commandRunner), "5")()
+	assert.ErrorContains(t, msg.(intents.AddMessage).Err, "only 1 can be undone")
+}
+
+func TestOperations_InvalidCount(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	msg := Operations(test.NewTestContext(commandRunner), "zero")()
+	assert.Error(t, msg.(intents.AddMessage).Err)
+}
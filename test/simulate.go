@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/cursor"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/idursun/jjui/internal/ui/common"
 )
 
 func SimulateModel[T interface {
@@ -87,3 +88,20 @@ func asCmdSlice(msg tea.Msg) ([]tea.Cmd, bool) {
 	}
 	return out, true
 }
+
+// CommandResults wraps model to hand the continuations asking for the result of a command the
+// result of the command they follow, the way the ui does
+func CommandResults(model interface{ Update(tea.Msg) tea.Cmd }) *CommandResultsModel {
+	return &CommandResultsModel{model: model}
+}
+
+type CommandResultsModel struct {
+	model interface{ Update(tea.Msg) tea.Cmd }
+}
+
+func (m *CommandResultsModel) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(common.CommandResultMsg); ok {
+		return msg.Handle(msg.Result)
+	}
+	return m.model.Update(msg)
+}
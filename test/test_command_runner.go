@@ -39,7 +39,14 @@ type CommandRunner struct {
 	mutex        sync.Mutex
 }
 
+// RunCommandImmediate trims the newlines around the output the same way the real runner does
 func (t *CommandRunner) RunCommandImmediate(args []string) ([]byte, error) {
+	output, err := t.run(args)
+	return bytes.Trim(output, "\n"), err
+}
+
+// run returns the output of the expected command as it is set
+func (t *CommandRunner) run(args []string) ([]byte, error) {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
@@ -59,8 +66,15 @@ func (t *CommandRunner) RunCommandImmediate(args []string) ([]byte, error) {
 	return nil, nil
 }
 
+func (t *CommandRunner) RunCommandImmediateContext(ctx context.Context, args []string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return t.RunCommandImmediate(args)
+}
+
 func (t *CommandRunner) RunCommandStreaming(_ context.Context, args []string) (*appContext.StreamingCommand, error) {
-	reader, err := t.RunCommandImmediate(args)
+	reader, err := t.run(args)
 	return &appContext.StreamingCommand{
 		ReadCloser: io.NopCloser(bytes.NewReader(reader)),
 		ErrPipe:    nil,
@@ -68,13 +82,11 @@ func (t *CommandRunner) RunCommandStreaming(_ context.Context, args []string) (*
 }
 
 func (t *CommandRunner) RunCommand(args []string, continuations ...tea.Cmd) tea.Cmd {
-	cmds := make([]tea.Cmd, 0)
-	cmds = append(cmds, func() tea.Msg {
-		output, err := t.RunCommandImmediate(args)
+	run := func() common.CommandCompletedMsg {
+		output, err := t.run(args)
 		return common.CommandCompletedMsg{Output: string(output), Err: err}
-	})
-	cmds = append(cmds, continuations...)
-	return tea.Batch(cmds...)
+	}
+	return tea.Batch(common.WithCommandResult(run, continuations...)...)
 }
 
 func (t *CommandRunner) RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd {
@@ -127,5 +139,6 @@ func NewTestContext(commandRunner appContext.CommandRunner) *appContext.MainCont
 		JJConfig:       &config.JJConfig{},
 		SelectedItem:   nil,
 		CustomCommands: make(map[string]appContext.CustomCommand),
+		State:          &config.State{},
 	}
 }